@@ -3,17 +3,25 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"time"
 
+	"qmdsr/config"
 	"qmdsr/model"
+	"qmdsr/orchestrator"
+	"qmdsr/scheduler"
 )
 
-var errGuardianUnavailable = errors.New("guardian not available")
+var (
+	errGuardianUnavailable      = errors.New("guardian not available")
+	errConfigManagerUnavailable = errors.New("runtime config changes not available")
+)
 
 type adminOpResult struct {
 	Message   string
+	JobID     string
 	TraceID   string
 	LatencyMs int64
 }
@@ -24,11 +32,51 @@ type adminCollectionsResult struct {
 	LatencyMs   int64
 }
 
-func (s *Server) executeAdminReindexCore(ctx context.Context, traceID string) (*adminOpResult, error) {
+type adminCollectionChangeResult struct {
+	Message    string
+	Collection config.CollectionCfg
+	TraceID    string
+	LatencyMs  int64
+}
+
+// collectionUpdate carries the fields of an UpdateCollection request; nil
+// fields keep their current value.
+type collectionUpdate struct {
+	Name            string
+	Path            *string
+	Mask            *string
+	Exclude         []string
+	ReplaceExclude  bool
+	Context         *string
+	Tier            *int
+	Embed           *bool
+	RequireExplicit *bool
+	SafetyPrompt    *bool
+}
+
+type adminReconcileResult struct {
+	Report    *orchestrator.ReconcileReport
+	TraceID   string
+	LatencyMs int64
+}
+
+type adminSchedulerStatusResult struct {
+	Tasks     []scheduler.TaskStatus
+	TraceID   string
+	LatencyMs int64
+}
+
+type adminReloadResult struct {
+	Diff      config.Diff
+	TraceID   string
+	LatencyMs int64
+}
+
+func (s *Server) executeAdminReindexCore(traceID string) (*adminOpResult, error) {
 	start := time.Now()
 	traceID = normalizeTraceID(traceID)
 
-	err := s.sched.TriggerReindex(ctx)
+	job, err := s.sched.SubmitReindex(traceID)
 	latency := time.Since(start).Milliseconds()
 	if err != nil {
 		s.logAdminCall("Reindex", traceID, latency, false, err)
@@ -36,7 +84,8 @@ func (s *Server) executeAdminReindexCore(ctx context.Context, traceID string) (*
 	}
 
 	res := &adminOpResult{
-		Message:   "reindex triggered",
+		Message:   "reindex job " + job.ID + " started",
+		JobID:     job.ID,
 		TraceID:   traceID,
 		LatencyMs: latency,
 	}
@@ -44,16 +93,12 @@ func (s *Server) executeAdminReindexCore(ctx context.Context, traceID string) (*
 	return res, nil
 }
 
-func (s *Server) executeAdminEmbedCore(ctx context.Context, traceID string, force bool) (*adminOpResult, error) {
+func (s *Server) executeAdminEmbedCore(traceID string, force bool) (*adminOpResult, error) {
+	cfg := s.config()
 	start := time.Now()
 	traceID = normalizeTraceID(traceID)
 
-	message := "embed triggered"
-	if force {
-		message = "full embed triggered"
-	}
-
-	if s.cfg.Runtime.LowResourceMode && !(s.cfg.Runtime.AllowCPUVSearch || s.cfg.Runtime.AllowCPUDeepQuery) {
+	if cfg.Runtime.LowResourceMode && !(cfg.Runtime.AllowCPUVSearch || cfg.Runtime.AllowCPUDeepQuery) {
 		res := &adminOpResult{
 			Message:   "embed disabled in low_resource_mode",
 			TraceID:   traceID,
@@ -63,15 +108,20 @@ func (s *Server) executeAdminEmbedCore(ctx context.Context, traceID string, forc
 		return res, nil
 	}
 
-	err := s.sched.TriggerEmbed(ctx, force)
+	job, err := s.sched.SubmitEmbed(force, traceID)
 	latency := time.Since(start).Milliseconds()
 	if err != nil {
 		s.logAdminCall("Embed", traceID, latency, false, err)
 		return nil, err
 	}
 
+	message := "embed job " + job.ID + " started"
+	if force {
+		message = "full embed job " + job.ID + " started"
+	}
 	res := &adminOpResult{
 		Message:   message,
+		JobID:     job.ID,
 		TraceID:   traceID,
 		LatencyMs: latency,
 	}
@@ -79,6 +129,53 @@ func (s *Server) executeAdminEmbedCore(ctx context.Context, traceID string, forc
 	return res, nil
 }
 
+func (s *Server) executeAdminGetJobCore(traceID, id string) (scheduler.Job, error) {
+	return s.jobCall("GetJob", traceID, func() (scheduler.Job, error) {
+		return s.sched.GetJob(strings.TrimSpace(id))
+	})
+}
+
+func (s *Server) executeAdminCancelJobCore(traceID, id string) (scheduler.Job, error) {
+	return s.jobCall("CancelJob", traceID, func() (scheduler.Job, error) {
+		return s.sched.CancelJob(strings.TrimSpace(id))
+	})
+}
+
+func (s *Server) executeAdminListJobsCore(traceID string) []scheduler.Job {
+	start := time.Now()
+	jobs := s.sched.ListJobs()
+	s.logAdminCall("ListJobs", normalizeTraceID(traceID), time.Since(start).Milliseconds(), true, nil)
+	return jobs
+}
+
+func (s *Server) executeAdminSchedulerStatusCore(traceID string) *adminSchedulerStatusResult {
+	start := time.Now()
+	traceID = normalizeTraceID(traceID)
+
+	tasks := s.sched.Status()
+	latency := time.Since(start).Milliseconds()
+	s.logAdminCall("SchedulerStatus", traceID, latency, true, nil)
+	return &adminSchedulerStatusResult{
+		Tasks:     tasks,
+		TraceID:   traceID,
+		LatencyMs: latency,
+	}
+}
+
+func (s *Server) jobCall(method, traceID string, fn func() (scheduler.Job, error)) (scheduler.Job, error) {
+	start := time.Now()
+	traceID = normalizeTraceID(traceID)
+
+	job, err := fn()
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		s.logAdminCall(method, traceID, latency, false, err)
+		return job, err
+	}
+	s.logAdminCall(method, traceID, latency, true, nil)
+	return job, nil
+}
+
 func (s *Server) executeAdminCacheClearCore(traceID string) (*adminOpResult, error) {
 	start := time.Now()
 	traceID = normalizeTraceID(traceID)
@@ -141,6 +238,173 @@ func (s *Server) executeAdminMCPRestartCore(ctx context.Context, traceID string)
 	return res, nil
 }
 
+func (s *Server) executeAdminReloadConfigCore(ctx context.Context, traceID string) (*adminReloadResult, error) {
+	start := time.Now()
+	traceID = normalizeTraceID(traceID)
+
+	if s.configMgr == nil {
+		err := errConfigManagerUnavailable
+		latency := time.Since(start).Milliseconds()
+		s.logAdminCall("ReloadConfig", traceID, latency, false, err)
+		return nil, err
+	}
+
+	diff, err := s.configMgr.Reload(ctx)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		s.logAdminCall("ReloadConfig", traceID, latency, false, err)
+		return nil, err
+	}
+
+	res := &adminReloadResult{
+		Diff:      diff,
+		TraceID:   traceID,
+		LatencyMs: latency,
+	}
+	s.logAdminCall("ReloadConfig", traceID, latency, true, nil)
+	return res, nil
+}
+
+func (s *Server) executeAdminReconcileCollectionsCore(ctx context.Context, traceID string, repair, dryRun bool) (*adminReconcileResult, error) {
+	start := time.Now()
+	traceID = normalizeTraceID(traceID)
+
+	report, err := s.orch.ReconcileCollections(ctx, repair, dryRun)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		s.logAdminCall("ReconcileCollections", traceID, latency, false, err)
+		return nil, err
+	}
+
+	res := &adminReconcileResult{
+		Report:    report,
+		TraceID:   traceID,
+		LatencyMs: latency,
+	}
+	s.logAdminCall("ReconcileCollections", traceID, latency, true, nil)
+	return res, nil
+}
+
+func (s *Server) executeAdminAddCollectionCore(ctx context.Context, traceID string, col config.CollectionCfg) (*adminCollectionChangeResult, error) {
+	return s.changeCollection(ctx, "AddCollection", traceID, func() (config.CollectionCfg, string, error) {
+		col.Name = strings.TrimSpace(col.Name)
+		col.Path = strings.TrimSpace(col.Path)
+		if err := validateCollectionSpec(col); err != nil {
+			return col, "", err
+		}
+		if err := s.configMgr.AddCollection(ctx, col); err != nil {
+			return col, "", err
+		}
+		return col, "collection added, reindex triggered", nil
+	})
+}
+
+func (s *Server) executeAdminUpdateCollectionCore(ctx context.Context, traceID string, upd collectionUpdate) (*adminCollectionChangeResult, error) {
+	return s.changeCollection(ctx, "UpdateCollection", traceID, func() (config.CollectionCfg, string, error) {
+		col, ok := s.findConfiguredCollection(strings.TrimSpace(upd.Name))
+		if !ok {
+			return col, "", fmt.Errorf("collection %s not found", upd.Name)
+		}
+		upd.applyTo(&col)
+		if err := validateCollectionSpec(col); err != nil {
+			return col, "", err
+		}
+		if err := s.configMgr.UpdateCollection(ctx, col); err != nil {
+			return col, "", err
+		}
+		return col, "collection updated, reindex triggered", nil
+	})
+}
+
+func (s *Server) executeAdminRemoveCollectionCore(ctx context.Context, traceID string, name string) (*adminCollectionChangeResult, error) {
+	return s.changeCollection(ctx, "RemoveCollection", traceID, func() (config.CollectionCfg, string, error) {
+		col, ok := s.findConfiguredCollection(strings.TrimSpace(name))
+		if !ok {
+			return col, "", fmt.Errorf("collection %s not found", name)
+		}
+		if err := s.configMgr.RemoveCollection(ctx, col.Name); err != nil {
+			return col, "", err
+		}
+		return col, "collection removed", nil
+	})
+}
+
+func (s *Server) changeCollection(ctx context.Context, method, traceID string, fn func() (config.CollectionCfg, string, error)) (*adminCollectionChangeResult, error) {
+	start := time.Now()
+	traceID = normalizeTraceID(traceID)
+
+	var (
+		col     config.CollectionCfg
+		message string
+		err     = errConfigManagerUnavailable
+	)
+	if s.configMgr != nil {
+		col, message, err = fn()
+	}
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		s.logAdminCall(method, traceID, latency, false, err)
+		return nil, err
+	}
+
+	res := &adminCollectionChangeResult{
+		Message:    message,
+		Collection: col,
+		TraceID:    traceID,
+		LatencyMs:  latency,
+	}
+	s.logAdminCall(method, traceID, latency, true, nil)
+	return res, nil
+}
+
+func (s *Server) findConfiguredCollection(name string) (config.CollectionCfg, bool) {
+	for _, col := range s.config().Collections {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return config.CollectionCfg{}, false
+}
+
+func validateCollectionSpec(col config.CollectionCfg) error {
+	switch {
+	case col.Name == "":
+		return errors.New("collection name is required")
+	case col.Path == "":
+		return fmt.Errorf("collection %s: path is required", col.Name)
+	case col.Tier <= 0:
+		return fmt.Errorf("collection %s: tier is required", col.Name)
+	}
+	return nil
+}
+
+func (u collectionUpdate) applyTo(col *config.CollectionCfg) {
+	if u.Path != nil {
+		col.Path = strings.TrimSpace(*u.Path)
+	}
+	if u.Mask != nil {
+		col.Mask = *u.Mask
+	}
+	if u.ReplaceExclude {
+		col.Exclude = u.Exclude
+	}
+	if u.Context != nil {
+		col.Context = *u.Context
+	}
+	if u.Tier != nil {
+		col.Tier = *u.Tier
+	}
+	if u.Embed != nil {
+		col.Embed = *u.Embed
+	}
+	if u.RequireExplicit != nil {
+		col.RequireExplicit = *u.RequireExplicit
+	}
+	if u.SafetyPrompt != nil {
+		col.SafetyPrompt = *u.SafetyPrompt
+	}
+}
+
 func normalizeTraceID(traceID string) string {
 	traceID = strings.TrimSpace(traceID)
 	if traceID == "" {
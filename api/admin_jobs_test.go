@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+	"qmdsr/scheduler"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestGRPCReindex_ReturnsJob(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	srv.sched = scheduler.New(srv.cfg, srv.exec, c, nil, nil, srv.log)
+	g := &grpcAdminServer{s: srv}
+
+	resp, err := g.Reindex(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("Reindex failed: %v", err)
+	}
+	if resp.GetJobId() == "" {
+		t.Fatalf("expected a job id, got %+v", resp)
+	}
+
+	var job *qmdsrv1.Job
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		job, err = g.GetJob(context.Background(), &qmdsrv1.GetJobRequest{Id: resp.GetJobId()})
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if job.GetState() != qmdsrv1.JobState_JOB_RUNNING {
+			break
+		}
+	}
+	if job.GetState() != qmdsrv1.JobState_JOB_SUCCEEDED || job.GetKind() != "reindex" || job.GetEndedAtUnixMs() == 0 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	list, err := g.ListJobs(context.Background(), &emptypb.Empty{})
+	if err != nil || len(list.GetJobs()) != 1 {
+		t.Fatalf("unexpected ListJobs result: %+v err=%v", list, err)
+	}
+
+	_, err = g.CancelJob(context.Background(), &qmdsrv1.CancelJobRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NOT_FOUND for an unknown job, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"qmdsr/config"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeConfigManager struct {
+	diff      config.Diff
+	reloadErr error
+
+	added   []config.CollectionCfg
+	updated []config.CollectionCfg
+	removed []string
+}
+
+func (m *fakeConfigManager) Reload(context.Context) (config.Diff, error) {
+	return m.diff, m.reloadErr
+}
+
+func (m *fakeConfigManager) AddCollection(_ context.Context, col config.CollectionCfg) error {
+	m.added = append(m.added, col)
+	return nil
+}
+
+func (m *fakeConfigManager) UpdateCollection(_ context.Context, col config.CollectionCfg) error {
+	m.updated = append(m.updated, col)
+	return nil
+}
+
+func (m *fakeConfigManager) RemoveCollection(_ context.Context, name string) error {
+	m.removed = append(m.removed, name)
+	return nil
+}
+
+func TestGRPCReloadConfig_ReportsDiff(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	srv.configMgr = &fakeConfigManager{diff: config.Diff{
+		CollectionsAdded: []string{"notes"},
+		Sections:         []string{"search"},
+		RestartRequired:  []string{"server"},
+	}}
+	g := &grpcAdminServer{s: srv}
+
+	resp, err := g.ReloadConfig(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if !resp.GetChanged() ||
+		!slices.Equal(resp.GetCollectionsAdded(), []string{"notes"}) ||
+		!slices.Equal(resp.GetSections(), []string{"search"}) ||
+		!slices.Equal(resp.GetRestartRequired(), []string{"server"}) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestGRPCReloadConfig_Errors(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	g := &grpcAdminServer{s: srv}
+
+	if _, err := g.ReloadConfig(context.Background(), &emptypb.Empty{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected UNIMPLEMENTED without a config manager, got %v", err)
+	}
+
+	srv.configMgr = &fakeConfigManager{
+		reloadErr: fmt.Errorf("%w: validate config: collection notes: duplicate name", config.ErrRejected),
+	}
+	if _, err := g.ReloadConfig(context.Background(), &emptypb.Empty{}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FAILED_PRECONDITION for a rejected config, got %v", err)
+	}
+}
+
+func TestGRPCAddCollection_ValidatesSpec(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	mgr := &fakeConfigManager{}
+	srv.configMgr = mgr
+	g := &grpcAdminServer{s: srv}
+
+	_, err := g.AddCollection(context.Background(), &qmdsrv1.CollectionSpec{Name: "vault", Tier: 2})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected INVALID_ARGUMENT without a path, got %v", err)
+	}
+
+	resp, err := g.AddCollection(context.Background(), &qmdsrv1.CollectionSpec{
+		Name: " vault ",
+		Path: "/data/vault",
+		Mask: "**/*.md",
+		Tier: 2,
+	})
+	if err != nil {
+		t.Fatalf("AddCollection failed: %v", err)
+	}
+	if len(mgr.added) != 1 || mgr.added[0].Name != "vault" || mgr.added[0].Path != "/data/vault" {
+		t.Fatalf("unexpected added collections: %+v", mgr.added)
+	}
+	if resp.GetCollection().GetName() != "vault" || resp.GetCollection().GetTier() != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestGRPCUpdateCollection_MergesSetFields(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	mgr := &fakeConfigManager{}
+	srv.configMgr = mgr
+	g := &grpcAdminServer{s: srv}
+
+	_, err := g.UpdateCollection(context.Background(), &qmdsrv1.UpdateCollectionRequest{Name: "missing", Tier: proto.Int32(1)})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NOT_FOUND for an unknown collection, got %v", err)
+	}
+
+	_, err = g.UpdateCollection(context.Background(), &qmdsrv1.UpdateCollectionRequest{
+		Name:         "personal",
+		Path:         proto.String("/personal-moved"),
+		SafetyPrompt: proto.Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCollection failed: %v", err)
+	}
+	if len(mgr.updated) != 1 {
+		t.Fatalf("expected one update, got %+v", mgr.updated)
+	}
+	got := mgr.updated[0]
+	if got.Path != "/personal-moved" || got.SafetyPrompt || got.Tier != 99 || !got.RequireExplicit {
+		t.Fatalf("unexpected merged collection: %+v", got)
+	}
+}
+
+func TestGRPCRemoveCollection(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	g := &grpcAdminServer{s: srv}
+
+	req := &qmdsrv1.RemoveCollectionRequest{Name: "personal"}
+	if _, err := g.RemoveCollection(context.Background(), req); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected UNIMPLEMENTED without a config manager, got %v", err)
+	}
+
+	mgr := &fakeConfigManager{}
+	srv.configMgr = mgr
+	if _, err := g.RemoveCollection(context.Background(), req); err != nil {
+		t.Fatalf("RemoveCollection failed: %v", err)
+	}
+	if !slices.Equal(mgr.removed, []string{"personal"}) {
+		t.Fatalf("unexpected removals: %v", mgr.removed)
+	}
+}
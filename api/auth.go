@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"qmdsr/internal/authz"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate resolves the bearer token on an incoming call and checks that
+// its principal may invoke fullMethod. Health checks stay open so probes and
+// the systemd watchdog keep working without credentials.
+func authenticate(ctx context.Context, tokens *authz.TokenStore, fullMethod string) (context.Context, error) {
+	if strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+
+	token := bearerTokenFromContext(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	p, ok := tokens.Lookup(token)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	if !p.AllowsRPC(fullMethod) {
+		return nil, status.Errorf(codes.PermissionDenied, "token %q may not call %s", p.Name, authz.ShortMethod(fullMethod))
+	}
+	return authz.WithPrincipal(ctx, p), nil
+}
+
+func bearerTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(strings.TrimSpace(v), " ")
+		if found && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+func tokenUnaryInterceptor(tokens *authz.TokenStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		authCtx, err := authenticate(ctx, tokens, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(authCtx, req)
+	}
+}
+
+// wrappedServerStream overrides the stream context so interceptors can pass
+// request-scoped values (principal, span) to stream handlers.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context { return s.ctx }
+
+func tokenStreamInterceptor(tokens *authz.TokenStore) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		authCtx, err := authenticate(ss.Context(), tokens, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: authCtx})
+	}
+}
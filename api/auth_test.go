@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"qmdsr/internal/authz"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestTokenStore(t *testing.T) *authz.TokenStore {
+	t.Helper()
+	store, err := authz.ParseTokenFile([]byte(`
+tokens:
+  - name: owner
+    token: owner-secret
+    rpcs: ["*"]
+  - name: helper
+    token: helper-secret
+    tiers: [1]
+`))
+	if err != nil {
+		t.Fatalf("ParseTokenFile failed: %v", err)
+	}
+	return store
+}
+
+func callWithToken(t *testing.T, interceptor grpc.UnaryServerInterceptor, method, token string) (*authz.Principal, error) {
+	t.Helper()
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+	var got *authz.Principal
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+		got, _ = authz.FromContext(ctx)
+		return nil, nil
+	})
+	return got, err
+}
+
+func TestTokenInterceptor_RejectsMissingAndUnknownTokens(t *testing.T) {
+	interceptor := tokenUnaryInterceptor(newTestTokenStore(t))
+
+	for _, token := range []string{"", "wrong"} {
+		_, err := callWithToken(t, interceptor, "/qmdsr.v1.QueryService/Search", token)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("token %q: expected Unauthenticated, got %v", token, err)
+		}
+	}
+}
+
+func TestTokenInterceptor_EnforcesRPCAllowList(t *testing.T) {
+	interceptor := tokenUnaryInterceptor(newTestTokenStore(t))
+
+	if _, err := callWithToken(t, interceptor, "/qmdsr.v1.AdminService/Reindex", "helper-secret"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected helper admin call to be denied, got %v", err)
+	}
+	p, err := callWithToken(t, interceptor, "/qmdsr.v1.AdminService/Reindex", "owner-secret")
+	if err != nil || p == nil || p.Name != "owner" {
+		t.Fatalf("expected owner principal on admin call, got %+v err=%v", p, err)
+	}
+}
+
+func TestTokenInterceptor_HealthIsOpen(t *testing.T) {
+	interceptor := tokenUnaryInterceptor(newTestTokenStore(t))
+
+	if _, err := callWithToken(t, interceptor, "/grpc.health.v1.Health/Check", ""); err != nil {
+		t.Fatalf("expected health check without token to pass, got %v", err)
+	}
+}
+
+func TestGRPCGet_PermissionDeniedForRestrictedToken(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	g := &grpcQueryServer{s: srv}
+	ctx := authz.WithPrincipal(context.Background(), &authz.Principal{Name: "helper", Tiers: []int{1}})
+
+	_, err := g.Get(ctx, &qmdsrv1.GetRequest{DocRef: "qmd://personal/private-note.md"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if srv.exec.(*fakeConfirmExec).getCalls != 0 {
+		t.Fatalf("did not expect executor get for denied document")
+	}
+}
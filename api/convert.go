@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"qmdsr/model"
+	"qmdsr/router"
 )
 
 func requestedModeToOrchestratorMode(mode string) string {
@@ -16,6 +17,8 @@ func requestedModeToOrchestratorMode(mode string) string {
 		return "search"
 	case "deep":
 		return "query"
+	case "hybrid":
+		return "hybrid"
 	default:
 		return "auto"
 	}
@@ -29,6 +32,8 @@ func normalizeRequestedMode(mode string) string {
 		return "broad"
 	case "deep", "query":
 		return "deep"
+	case "hybrid", "fused":
+		return "hybrid"
 	default:
 		return "auto"
 	}
@@ -65,13 +70,16 @@ func deriveServedMode(requestedMode, modeUsed string, fallbackTriggered, degrade
 	if modeUsed == "query" {
 		return "deep"
 	}
+	if modeUsed == "hybrid" {
+		return "hybrid"
+	}
 
 	switch requestedMode {
 	case "deep":
 		return "broad"
 	case "broad":
 		return "broad"
-	case "core":
+	case "hybrid", "core":
 		if fallbackTriggered {
 			return "broad"
 		}
@@ -84,8 +92,8 @@ func deriveServedMode(requestedMode, modeUsed string, fallbackTriggered, degrade
 	}
 }
 
-func buildRouteLog(requestedMode string, allowFallback bool, orchestratorMode string, meta model.SearchMeta, collectionCount int, hitCount int) []string {
-	return []string{
+func buildRouteLog(requestedMode string, allowFallback bool, orchestratorMode string, route router.Decision, meta model.SearchMeta, collectionCount int, hitCount int) []string {
+	log := []string{
 		"requested_mode=" + requestedMode,
 		"orchestrator_mode=" + orchestratorMode,
 		fmt.Sprintf("allow_fallback=%t", allowFallback),
@@ -97,6 +105,26 @@ func buildRouteLog(requestedMode string, allowFallback bool, orchestratorMode st
 		fmt.Sprintf("hits=%d", hitCount),
 		fmt.Sprintf("cache_hit=%t", meta.CacheHit),
 	}
+	if orchestratorMode == "auto" {
+		log = append(log, "route_rule="+route.ModeRule, "route_mode="+string(route.Mode))
+	}
+	if route.CollectionsRule != "" {
+		log = append(log, "route_collections_rule="+route.CollectionsRule)
+	}
+	if route.GateRule != "" {
+		log = append(log, "route_gate="+string(route.Gate), "route_gate_rule="+route.GateRule)
+	}
+	return log
+}
+
+// formatTierStep renders one tier of a search cascade for route_log, e.g.
+// "cascade=tier1[notes,work] hits=1 top_score=0.42 fall_through=min_top_score".
+func formatTierStep(step model.TierStep) string {
+	entry := fmt.Sprintf("cascade=tier%d[%s] hits=%d top_score=%.2f", step.Tier, strings.Join(step.Collections, ","), step.Hits, step.TopScore)
+	if step.FallThrough != "" {
+		entry += " fall_through=" + step.FallThrough
+	}
+	return entry
 }
 
 func durationToInt32Milliseconds(d time.Duration) int32 {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -30,6 +31,9 @@ type searchCoreRequest struct {
 	FilesAll      bool
 	TraceID       string
 	Confirm       bool
+	// OnFrame receives intermediate snapshots for SearchStream. Frames are
+	// delivered sequentially and never after executeSearchCore returns.
+	OnFrame func(searchFrame)
 }
 
 type searchCoreResult struct {
@@ -37,6 +41,13 @@ type searchCoreResult struct {
 	RouteLog []string
 }
 
+// searchFrame is an intermediate snapshot: the merged hits known so far and
+// the meta they would be served with if the search stopped here.
+type searchFrame struct {
+	Stage    string
+	Response *model.SearchResponse
+}
+
 var errCriticalOverloadShed = errors.New("cpu critical overload shed")
 
 type searchAndGetCoreRequest struct {
@@ -57,6 +68,7 @@ type searchAndGetCoreResult struct {
 }
 
 func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (*searchCoreResult, error) {
+	cfg := s.config()
 	query := strings.TrimSpace(req.Query)
 	if query == "" {
 		return nil, fmt.Errorf("query is required")
@@ -73,12 +85,12 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 		if req.FilesOnly && req.FilesAll {
 			topK = 0
 		} else {
-			topK = s.cfg.Search.TopK
+			topK = cfg.Search.TopK
 		}
 	}
 	minScore := req.MinScore
 	if minScore <= 0 {
-		minScore = s.cfg.Search.MinScore
+		minScore = cfg.Search.MinScore
 	}
 
 	searchCtx := ctx
@@ -89,13 +101,19 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 	}
 
 	collections := normalizeCollections(req.Collections)
+	route := s.orch.Route(ctx, query, collections)
 	if len(collections) == 0 {
+		// Rule-selected collections are an implicit scope: protected or
+		// forbidden ones are skipped rather than failing the search.
 		collections = []string{""}
+		if scoped := s.orch.ImplicitCollections(ctx, route.Collections); len(scoped) > 0 {
+			collections = scoped
+		}
 	}
 
 	start := time.Now()
 	mode := requestedModeToOrchestratorMode(requestedMode)
-	disableDeepEscalation := requestedMode == "core" || requestedMode == "broad"
+	disableDeepEscalation := requestedMode == "core" || requestedMode == "broad" || requestedMode == "hybrid"
 	preDegraded := false
 	preDegradeReason := ""
 
@@ -109,7 +127,7 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 	if s.orch.IsCriticalOverloaded() {
 		allowByCache := true
 		for _, collection := range collections {
-			if !s.orch.HasCachedResult(orchestrator.SearchParams{
+			if !s.orch.HasCachedResult(ctx, orchestrator.SearchParams{
 				Query:      query,
 				Mode:       mode,
 				Collection: collection,
@@ -135,7 +153,7 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 
 	// Explicit deep requests are still guarded in low-resource mode when fallback is allowed.
 	// This prevents known OOM-prone deep paths from destabilizing the service.
-	if requestedMode == "deep" && req.AllowFallback && !s.orch.AllowDeepQuery(query) {
+	if requestedMode == "deep" && req.AllowFallback && !s.orch.AllowDeepQuery(query, route.Gate) {
 		mode = "search"
 		disableDeepEscalation = true
 		preDegraded = true
@@ -149,10 +167,55 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 	cacheHit := false
 	degraded := false
 	degradeReason := ""
+	var bm25Queries, expansions []string
+	var cascade []model.TierStep
 	firstErr := error(nil)
 	successCount := 0
 
-	for _, collection := range collections {
+	emitFrame := func(stage string, pending []model.SearchResult, meta model.SearchMeta) {
+		merged := make([]model.SearchResult, 0, len(combined)+len(pending))
+		merged = append(merged, combined...)
+		merged = append(merged, pending...)
+		merged = searchutil.DedupSortLimit(merged, topK)
+
+		frameMode := meta.ModeUsed
+		if modeUsed == "query" || frameMode == "" {
+			frameMode = modeUsed
+		}
+		frameMeta := model.SearchMeta{
+			ModeUsed:            frameMode,
+			CollectionsSearched: meta.CollectionsSearched,
+			FallbackTriggered:   fallbackTriggered || meta.FallbackTriggered,
+			Degraded:            degraded || meta.Degraded || preDegraded,
+			DegradeReason:       degradeReason,
+			TraceID:             traceID,
+			LatencyMs:           time.Since(start).Milliseconds(),
+		}
+		if frameMeta.DegradeReason == "" {
+			frameMeta.DegradeReason = meta.DegradeReason
+		}
+		if frameMeta.DegradeReason == "" && preDegraded {
+			frameMeta.DegradeReason = preDegradeReason
+		}
+		frameMeta.ServedMode = deriveServedMode(requestedMode, frameMeta.ModeUsed, frameMeta.FallbackTriggered, frameMeta.Degraded)
+		req.OnFrame(searchFrame{
+			Stage: stage,
+			Response: &model.SearchResponse{
+				Results:       merged,
+				Meta:          frameMeta,
+				FormattedText: renderFormattedText(merged, frameMeta, req.FilesOnly),
+			},
+		})
+	}
+
+	var onPartial func(orchestrator.PartialResult)
+	if req.OnFrame != nil {
+		onPartial = func(p orchestrator.PartialResult) {
+			emitFrame(p.Stage, p.Results, p.Meta)
+		}
+	}
+
+	for i, collection := range collections {
 		result, err := s.orch.Search(searchCtx, orchestrator.SearchParams{
 			Query:                 query,
 			Mode:                  mode,
@@ -164,6 +227,9 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 			FilesAll:              req.FilesAll,
 			DisableDeepEscalation: disableDeepEscalation,
 			Confirm:               req.Confirm,
+			Route:                 &route,
+			Downgraded:            preDegraded,
+			OnPartial:             onPartial,
 		})
 		if err != nil {
 			if firstErr == nil {
@@ -184,17 +250,34 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 		if degradeReason == "" && result.Meta.DegradeReason != "" {
 			degradeReason = result.Meta.DegradeReason
 		}
+		for _, q := range result.Meta.BM25Queries {
+			if !slices.Contains(bm25Queries, q) {
+				bm25Queries = append(bm25Queries, q)
+			}
+		}
+		cascade = append(cascade, result.Meta.Cascade...)
+		for _, x := range result.Meta.Expansions {
+			if !slices.Contains(expansions, x) {
+				expansions = append(expansions, x)
+			}
+		}
 		if len(result.Meta.CollectionsSearched) == 0 {
 			if collection != "" {
 				searchedSet[collection] = struct{}{}
 			}
-			continue
 		}
 		for _, c := range result.Meta.CollectionsSearched {
 			if c != "" {
 				searchedSet[c] = struct{}{}
 			}
 		}
+
+		if req.OnFrame != nil && i < len(collections)-1 {
+			emitFrame(searchStageForMeta(result.Meta), nil, model.SearchMeta{
+				ModeUsed:            modeUsed,
+				CollectionsSearched: sortedKeys(searchedSet),
+			})
+		}
 	}
 
 	if successCount == 0 {
@@ -210,8 +293,8 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 
 	combined = searchutil.DedupSortLimit(combined, topK)
 	filesAllCapped := false
-	if req.FilesOnly && req.FilesAll && s.cfg.Search.FilesAllMaxHits > 0 && len(combined) > s.cfg.Search.FilesAllMaxHits {
-		combined = combined[:s.cfg.Search.FilesAllMaxHits]
+	if req.FilesOnly && req.FilesAll && cfg.Search.FilesAllMaxHits > 0 && len(combined) > cfg.Search.FilesAllMaxHits {
+		combined = combined[:cfg.Search.FilesAllMaxHits]
 		filesAllCapped = true
 	}
 	collectionsSearched := sortedKeys(searchedSet)
@@ -222,6 +305,12 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 			degradeReason = "DEEP_GATE_REJECTED"
 		}
 	}
+	if requestedMode == "hybrid" && servedMode != "hybrid" {
+		degraded = true
+		if degradeReason == "" && !preDegraded {
+			degradeReason = "HYBRID_UNAVAILABLE"
+		}
+	}
 	if preDegraded {
 		degraded = true
 		if degradeReason == "" {
@@ -240,6 +329,7 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 		ServedMode:          servedMode,
 		CollectionsSearched: collectionsSearched,
 		FallbackTriggered:   fallbackTriggered,
+		Cascade:             cascade,
 		CacheHit:            cacheHit,
 		Degraded:            degraded,
 		DegradeReason:       degradeReason,
@@ -264,12 +354,36 @@ func (s *Server) executeSearchCore(ctx context.Context, req searchCoreRequest) (
 
 	var routeLog []string
 	if req.Explain {
-		routeLog = buildRouteLog(requestedMode, req.AllowFallback, mode, meta, len(collections), len(combined))
+		routeLog = buildRouteLog(requestedMode, req.AllowFallback, mode, route, meta, len(collections), len(combined))
+		for _, step := range cascade {
+			routeLog = append(routeLog, formatTierStep(step))
+		}
+		for _, q := range bm25Queries {
+			routeLog = append(routeLog, "bm25_rewrite="+q)
+		}
+		for _, x := range expansions {
+			routeLog = append(routeLog, "synonym="+x)
+		}
+		if bm25 := s.orch.BM25Query(query); len(bm25Queries) == 0 && bm25 != query {
+			routeLog = append(routeLog, "bm25_query="+bm25)
+		}
 	}
 
 	return &searchCoreResult{Response: resp, RouteLog: routeLog}, nil
 }
 
+// searchStageForMeta names the search stage that produced a result set.
+func searchStageForMeta(meta model.SearchMeta) string {
+	switch {
+	case meta.ModeUsed == "query":
+		return orchestrator.StageDeep
+	case meta.FallbackTriggered:
+		return orchestrator.StageTier2
+	default:
+		return orchestrator.StageTier1
+	}
+}
+
 func (s *Server) executeSearchAndGetCore(ctx context.Context, req searchAndGetCoreRequest) (*searchAndGetCoreResult, error) {
 	query := strings.TrimSpace(req.Query)
 	if query == "" {
@@ -340,7 +454,7 @@ func (s *Server) executeSearchAndGetCore(ctx context.Context, req searchAndGetCo
 	for i, uri := range targets {
 		go func(idx int, docURI string) {
 			defer wg.Done()
-			content, getErr := s.exec.Get(ctx, docURI, executor.GetOpts{Full: true})
+			content, getErr := s.orch.Get(ctx, docURI, executor.GetOpts{Full: true}, req.Confirm)
 			outcomes[idx] = getOutcome{
 				uri:     docURI,
 				content: content,
@@ -416,6 +530,19 @@ func (s *Server) buildHealthResponse() *qmdsrv1.HealthResponse {
 		})
 	}
 
+	for _, a := range s.heartbeat.Remediations() {
+		resp.Remediations = append(resp.Remediations, &qmdsrv1.RemediationAttempt{
+			Remediation: a.Remediation,
+			Component:   a.Component,
+			FromStatus:  a.From.String(),
+			ToStatus:    a.To.String(),
+			Message:     a.Message,
+			AtUnixMs:    a.At.UnixMilli(),
+			DurationMs:  a.Duration.Milliseconds(),
+			Error:       a.Err,
+		})
+	}
+
 	switch {
 	case s.orch.IsCriticalOverloaded():
 		resp.Mode = "cpu_critical_overloaded"
@@ -440,6 +567,7 @@ func (s *Server) buildHealthResponse() *qmdsrv1.HealthResponse {
 }
 
 func (s *Server) buildStatusResponse(traceID string) *qmdsrv1.StatusResponse {
+	cfg := s.config()
 	if strings.TrimSpace(traceID) == "" {
 		traceID = genRequestID()
 	}
@@ -447,17 +575,70 @@ func (s *Server) buildStatusResponse(traceID string) *qmdsrv1.StatusResponse {
 	return &qmdsrv1.StatusResponse{
 		Version:                     version.Version,
 		Commit:                      version.Commit,
-		LowResourceMode:             s.cfg.Runtime.LowResourceMode,
-		AllowCpuDeepQuery:           s.cfg.Runtime.AllowCPUDeepQuery,
+		LowResourceMode:             cfg.Runtime.LowResourceMode,
+		AllowCpuDeepQuery:           cfg.Runtime.AllowCPUDeepQuery,
 		DeepQueryEnabled:            s.exec.HasCapability("deep_query"),
 		VectorEnabled:               s.exec.HasCapability("vector"),
-		QueryMaxConcurrency:         int32(s.cfg.Runtime.QueryMaxConcurrency),
-		QueryTimeoutMs:              durationToInt32Milliseconds(s.cfg.Runtime.QueryTimeout),
-		DeepFailTimeoutMs:           durationToInt32Milliseconds(s.cfg.Runtime.DeepFailTimeout),
-		DeepNegativeTtlSec:          durationToInt32Seconds(s.cfg.Runtime.DeepNegativeTTL),
+		QueryMaxConcurrency:         int32(cfg.Runtime.QueryMaxConcurrency),
+		QueryTimeoutMs:              durationToInt32Milliseconds(cfg.Runtime.QueryTimeout),
+		DeepFailTimeoutMs:           durationToInt32Milliseconds(cfg.Runtime.DeepFailTimeout),
+		DeepNegativeTtlSec:          durationToInt32Seconds(cfg.Runtime.DeepNegativeTTL),
 		TraceId:                     traceID,
 		CpuOverloaded:               s.orch.IsOverloaded(),
 		CpuCriticalOverloaded:       s.orch.IsCriticalOverloaded(),
-		OverloadMaxConcurrentSearch: int32(s.cfg.Runtime.OverloadMaxConcurrentSearch),
+		OverloadMaxConcurrentSearch: int32(cfg.Runtime.OverloadMaxConcurrentSearch),
+		Embed:                       s.embedStatus(),
+		Scheduler:                   s.schedulerSummary(),
+	}
+}
+
+func (s *Server) schedulerSummary() []*qmdsrv1.SchedulerTaskSummary {
+	if s.sched == nil {
+		return nil
+	}
+	tasks := s.sched.Status()
+	out := make([]*qmdsrv1.SchedulerTaskSummary, 0, len(tasks))
+	for _, t := range tasks {
+		sum := &qmdsrv1.SchedulerTaskSummary{
+			Name:            t.Task,
+			LastRunUnix:     unixOrZero(t.LastRun),
+			NextRunUnix:     unixOrZero(t.NextRun),
+			LastSuccessUnix: unixOrZero(t.LastSuccess),
+			Running:         t.Running,
+			Stale:           t.Stale,
+		}
+		if len(t.History) > 0 {
+			sum.LastDurationMs = t.History[0].Duration.Milliseconds()
+			sum.LastError = t.History[0].Err
+		}
+		out = append(out, sum)
+	}
+	return out
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func (s *Server) embedStatus() []*qmdsrv1.CollectionEmbedStatus {
+	if s.sched == nil {
+		return nil
+	}
+	states := s.sched.EmbedStatus()
+	out := make([]*qmdsrv1.CollectionEmbedStatus, 0, len(states))
+	for _, st := range states {
+		var last int64
+		if !st.LastEmbedded.IsZero() {
+			last = st.LastEmbedded.Unix()
+		}
+		out = append(out, &qmdsrv1.CollectionEmbedStatus{
+			Collection:       st.Collection,
+			IntervalSec:      int64(st.Interval / time.Second),
+			LastEmbeddedUnix: last,
+		})
 	}
+	return out
 }
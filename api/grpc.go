@@ -5,11 +5,17 @@ import (
 	"errors"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
+	"qmdsr/config"
 	"qmdsr/executor"
+	"qmdsr/internal/authz"
 	"qmdsr/model"
+	"qmdsr/orchestrator"
 	qmdsrv1 "qmdsr/pb/qmdsrv1"
+	"qmdsr/scheduler"
+	"qmdsr/tracing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -32,31 +38,50 @@ type grpcAdminServer struct {
 }
 
 func (s *Server) startGRPC() error {
-	if s.cfg.Server.GRPCListen == "" {
+	cfg := s.config()
+	if cfg.Server.GRPCListen == "" {
 		return nil
 	}
 	if s.grpcServer != nil {
 		return nil
 	}
 
-	lis, err := net.Listen("tcp", s.cfg.Server.GRPCListen)
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(traceUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(traceStreamInterceptor()),
+	}
+	if cfg.Server.SecurityModel == "token" {
+		tokens, err := authz.LoadTokenFile(cfg.Server.TokenFile)
+		if err != nil {
+			return err
+		}
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(tokenUnaryInterceptor(tokens)),
+			grpc.ChainStreamInterceptor(tokenStreamInterceptor(tokens)),
+		)
+		s.log.Info("gRPC token authentication enabled", "token_file", cfg.Server.TokenFile)
+	}
+
+	lis, err := net.Listen("tcp", cfg.Server.GRPCListen)
 	if err != nil {
 		return err
 	}
 
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(opts...)
 	qmdsrv1.RegisterQueryServiceServer(grpcSrv, &grpcQueryServer{s: s})
 	qmdsrv1.RegisterAdminServiceServer(grpcSrv, &grpcAdminServer{s: s})
 	healthSrv := grpcHealth.NewServer()
-	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-	healthSrv.SetServingStatus("qmdsr.v1.QueryService", healthpb.HealthCheckResponse_SERVING)
-	healthSrv.SetServingStatus("qmdsr.v1.AdminService", healthpb.HealthCheckResponse_SERVING)
 	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
 	reflection.Register(grpcSrv)
 	s.grpcServer = grpcSrv
+	s.healthSrv = healthSrv
+	if s.heartbeat != nil {
+		s.setServingStatus(healthSrv, s.heartbeat.GetHealth())
+		s.heartbeat.OnCheck(func(h *model.SystemHealth) { s.setServingStatus(healthSrv, h) })
+	}
 
 	go func() {
-		s.log.Info("gRPC server starting", "listen", s.cfg.Server.GRPCListen)
+		s.log.Info("gRPC server starting", "listen", cfg.Server.GRPCListen)
 		if serveErr := grpcSrv.Serve(lis); serveErr != nil && !strings.Contains(serveErr.Error(), "use of closed network connection") {
 			s.log.Error("gRPC server error", "err", serveErr)
 		}
@@ -68,7 +93,7 @@ func (s *Server) startGRPC() error {
 func (g *grpcQueryServer) Search(ctx context.Context, req *qmdsrv1.SearchRequest) (*qmdsrv1.SearchResponse, error) {
 	traceID := traceIDFromContext(ctx)
 	requested := requestedModeFromProto(req.GetRequestedMode())
-	allowFallback := allowFallbackFromProto(req, requested, g.s.cfg.Search.FallbackEnabled)
+	allowFallback := allowFallbackFromProto(req, requested, g.s.config().Search.FallbackEnabled)
 
 	result, err := g.s.executeSearchCore(ctx, searchCoreRequest{
 		Query:         req.GetQuery(),
@@ -91,18 +116,60 @@ func (g *grpcQueryServer) Search(ctx context.Context, req *qmdsrv1.SearchRequest
 	return toProtoSearchResponse(result.Response, result.RouteLog), nil
 }
 
+func (g *grpcQueryServer) SearchStream(req *qmdsrv1.SearchRequest, stream qmdsrv1.QueryService_SearchStreamServer) error {
+	ctx := stream.Context()
+	traceID := traceIDFromContext(ctx)
+	requested := requestedModeFromProto(req.GetRequestedMode())
+	allowFallback := allowFallbackFromProto(req, requested, g.s.config().Search.FallbackEnabled)
+
+	var sendMu sync.Mutex
+	var sendErr error
+	send := func(frame *qmdsrv1.SearchFrame) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		if sendErr != nil {
+			return
+		}
+		sendErr = stream.Send(frame)
+	}
+
+	result, err := g.s.executeSearchCore(ctx, searchCoreRequest{
+		Query:         req.GetQuery(),
+		RequestedMode: requested,
+		Collections:   req.GetCollections(),
+		AllowFallback: allowFallback,
+		TimeoutMs:     req.GetTimeoutMs(),
+		TopK:          req.GetTopK(),
+		MinScore:      req.GetMinScore(),
+		Explain:       req.GetExplain(),
+		FilesOnly:     req.GetFilesOnly(),
+		FilesAll:      req.GetFilesAll(),
+		Confirm:       req.GetConfirm(),
+		TraceID:       traceID,
+		OnFrame: func(frame searchFrame) {
+			send(toProtoSearchFrame(frame.Stage, false, frame.Response, nil))
+		},
+	})
+	if err != nil {
+		return mapSearchError(err)
+	}
+
+	send(toProtoSearchFrame(searchStageForMeta(result.Response.Meta), true, result.Response, result.RouteLog))
+	return sendErr
+}
+
 func (g *grpcQueryServer) SearchAndGet(ctx context.Context, req *qmdsrv1.SearchAndGetRequest) (*qmdsrv1.SearchAndGetResponse, error) {
 	traceID := traceIDFromContext(ctx)
 	requested := requestedModeFromProto(req.GetRequestedMode())
 	allowFallback := req.GetAllowFallback()
 	if !allowFallback {
 		switch requested {
-		case "deep", "broad":
+		case "deep", "broad", "hybrid":
 			allowFallback = true
 		case "core":
 			allowFallback = false
 		default:
-			allowFallback = g.s.cfg.Search.FallbackEnabled
+			allowFallback = g.s.config().Search.FallbackEnabled
 		}
 	}
 
@@ -128,10 +195,10 @@ func (g *grpcQueryServer) Get(ctx context.Context, req *qmdsrv1.GetRequest) (*qm
 	start := time.Now()
 	traceID := traceIDFromContext(ctx)
 
-	content, err := g.s.exec.Get(ctx, req.GetDocRef(), executor.GetOpts{
+	content, err := g.s.orch.Get(ctx, req.GetDocRef(), executor.GetOpts{
 		Full:        req.GetFull(),
 		LineNumbers: req.GetLineNumbers(),
-	})
+	}, req.GetConfirm())
 	if err != nil {
 		return nil, mapSearchError(err)
 	}
@@ -147,7 +214,7 @@ func (g *grpcQueryServer) MultiGet(ctx context.Context, req *qmdsrv1.MultiGetReq
 	start := time.Now()
 	traceID := traceIDFromContext(ctx)
 
-	docs, err := g.s.exec.MultiGet(ctx, req.GetPattern(), int(req.GetMaxBytes()))
+	docs, err := g.s.orch.MultiGet(ctx, req.GetPattern(), int(req.GetMaxBytes()), req.GetConfirm())
 	if err != nil {
 		return nil, mapSearchError(err)
 	}
@@ -176,7 +243,7 @@ func (g *grpcQueryServer) Status(ctx context.Context, _ *qmdsrv1.StatusRequest)
 }
 
 func (g *grpcAdminServer) Reindex(ctx context.Context, _ *emptypb.Empty) (*qmdsrv1.OpResponse, error) {
-	res, err := g.s.executeAdminReindexCore(ctx, traceIDFromContext(ctx))
+	res, err := g.s.executeAdminReindexCore(traceIDFromContext(ctx))
 	if err != nil {
 		return nil, mapAdminRPCError(err)
 	}
@@ -184,13 +251,71 @@ func (g *grpcAdminServer) Reindex(ctx context.Context, _ *emptypb.Empty) (*qmdsr
 }
 
 func (g *grpcAdminServer) Embed(ctx context.Context, req *qmdsrv1.EmbedRequest) (*qmdsrv1.OpResponse, error) {
-	res, err := g.s.executeAdminEmbedCore(ctx, traceIDFromContext(ctx), req.GetForce())
+	res, err := g.s.executeAdminEmbedCore(traceIDFromContext(ctx), req.GetForce())
 	if err != nil {
 		return nil, mapAdminRPCError(err)
 	}
 	return toProtoOpResponse(res), nil
 }
 
+func (g *grpcAdminServer) GetJob(ctx context.Context, req *qmdsrv1.GetJobRequest) (*qmdsrv1.Job, error) {
+	job, err := g.s.executeAdminGetJobCore(traceIDFromContext(ctx), req.GetId())
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return toProtoJob(job), nil
+}
+
+func (g *grpcAdminServer) ListJobs(ctx context.Context, _ *emptypb.Empty) (*qmdsrv1.ListJobsResponse, error) {
+	jobs := g.s.executeAdminListJobsCore(traceIDFromContext(ctx))
+	resp := &qmdsrv1.ListJobsResponse{Jobs: make([]*qmdsrv1.Job, 0, len(jobs))}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, toProtoJob(job))
+	}
+	return resp, nil
+}
+
+func (g *grpcAdminServer) CancelJob(ctx context.Context, req *qmdsrv1.CancelJobRequest) (*qmdsrv1.Job, error) {
+	job, err := g.s.executeAdminCancelJobCore(traceIDFromContext(ctx), req.GetId())
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return toProtoJob(job), nil
+}
+
+func (g *grpcAdminServer) SchedulerStatus(ctx context.Context, _ *emptypb.Empty) (*qmdsrv1.SchedulerStatusResponse, error) {
+	res := g.s.executeAdminSchedulerStatusCore(traceIDFromContext(ctx))
+	tasks := make([]*qmdsrv1.SchedulerTask, 0, len(res.Tasks))
+	for _, t := range res.Tasks {
+		history := make([]*qmdsrv1.TaskRun, 0, len(t.History))
+		for _, run := range t.History {
+			history = append(history, &qmdsrv1.TaskRun{
+				StartUnixMs:   run.Start.UnixMilli(),
+				DurationMs:    run.Duration.Milliseconds(),
+				Attempts:      intToInt32(run.Attempts),
+				Error:         run.Err,
+				JobId:         run.JobID,
+				CacheVersions: run.CacheVersions,
+			})
+		}
+		tasks = append(tasks, &qmdsrv1.SchedulerTask{
+			Name:            t.Task,
+			Schedule:        t.Schedule,
+			LastRunUnix:     unixOrZero(t.LastRun),
+			NextRunUnix:     unixOrZero(t.NextRun),
+			LastSuccessUnix: unixOrZero(t.LastSuccess),
+			Running:         t.Running,
+			Stale:           t.Stale,
+			History:         history,
+		})
+	}
+	return &qmdsrv1.SchedulerStatusResponse{
+		Tasks:     tasks,
+		TraceId:   res.TraceID,
+		LatencyMs: res.LatencyMs,
+	}, nil
+}
+
 func (g *grpcAdminServer) CacheClear(ctx context.Context, _ *emptypb.Empty) (*qmdsrv1.OpResponse, error) {
 	res, err := g.s.executeAdminCacheClearCore(traceIDFromContext(ctx))
 	if err != nil {
@@ -228,6 +353,91 @@ func (g *grpcAdminServer) MCPRestart(ctx context.Context, _ *emptypb.Empty) (*qm
 	return toProtoOpResponse(res), nil
 }
 
+func (g *grpcAdminServer) ReloadConfig(ctx context.Context, _ *emptypb.Empty) (*qmdsrv1.ReloadConfigResponse, error) {
+	res, err := g.s.executeAdminReloadConfigCore(ctx, traceIDFromContext(ctx))
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return &qmdsrv1.ReloadConfigResponse{
+		Changed:            !res.Diff.Empty(),
+		CollectionsAdded:   res.Diff.CollectionsAdded,
+		CollectionsRemoved: res.Diff.CollectionsRemoved,
+		CollectionsChanged: res.Diff.CollectionsChanged,
+		Sections:           res.Diff.Sections,
+		RestartRequired:    res.Diff.RestartRequired,
+		TraceId:            res.TraceID,
+		LatencyMs:          res.LatencyMs,
+	}, nil
+}
+
+func (g *grpcAdminServer) ReconcileCollections(ctx context.Context, req *qmdsrv1.ReconcileCollectionsRequest) (*qmdsrv1.ReconcileCollectionsResponse, error) {
+	res, err := g.s.executeAdminReconcileCollectionsCore(ctx, traceIDFromContext(ctx), req.GetRepair(), req.GetDryRun())
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	drift := make([]*qmdsrv1.CollectionDrift, 0, len(res.Report.Drift))
+	for _, d := range res.Report.Drift {
+		kinds := make([]string, len(d.Kinds))
+		for i, k := range d.Kinds {
+			kinds[i] = string(k)
+		}
+		drift = append(drift, &qmdsrv1.CollectionDrift{
+			Name:           d.Name,
+			Kinds:          kinds,
+			ConfiguredPath: d.ConfiguredPath,
+			RegisteredPath: d.RegisteredPath,
+			ConfiguredMask: d.ConfiguredMask,
+			RegisteredMask: d.RegisteredMask,
+		})
+	}
+	return &qmdsrv1.ReconcileCollectionsResponse{
+		Drift:     drift,
+		Actions:   res.Report.Actions,
+		Applied:   res.Report.Applied,
+		TraceId:   res.TraceID,
+		LatencyMs: res.LatencyMs,
+	}, nil
+}
+
+func (g *grpcAdminServer) AddCollection(ctx context.Context, req *qmdsrv1.CollectionSpec) (*qmdsrv1.CollectionChangeResponse, error) {
+	res, err := g.s.executeAdminAddCollectionCore(ctx, traceIDFromContext(ctx), collectionFromProto(req))
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return toProtoCollectionChange(res), nil
+}
+
+func (g *grpcAdminServer) UpdateCollection(ctx context.Context, req *qmdsrv1.UpdateCollectionRequest) (*qmdsrv1.CollectionChangeResponse, error) {
+	upd := collectionUpdate{
+		Name:            req.GetName(),
+		Path:            req.Path,
+		Mask:            req.Mask,
+		Exclude:         req.GetExclude(),
+		ReplaceExclude:  req.GetReplaceExclude(),
+		Context:         req.Context,
+		Embed:           req.Embed,
+		RequireExplicit: req.RequireExplicit,
+		SafetyPrompt:    req.SafetyPrompt,
+	}
+	if req.Tier != nil {
+		tier := int(req.GetTier())
+		upd.Tier = &tier
+	}
+	res, err := g.s.executeAdminUpdateCollectionCore(ctx, traceIDFromContext(ctx), upd)
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return toProtoCollectionChange(res), nil
+}
+
+func (g *grpcAdminServer) RemoveCollection(ctx context.Context, req *qmdsrv1.RemoveCollectionRequest) (*qmdsrv1.CollectionChangeResponse, error) {
+	res, err := g.s.executeAdminRemoveCollectionCore(ctx, traceIDFromContext(ctx), req.GetName())
+	if err != nil {
+		return nil, mapAdminRPCError(err)
+	}
+	return toProtoCollectionChange(res), nil
+}
+
 func requestedModeFromProto(mode qmdsrv1.Mode) string {
 	switch mode {
 	case qmdsrv1.Mode_MODE_CORE:
@@ -238,6 +448,8 @@ func requestedModeFromProto(mode qmdsrv1.Mode) string {
 		return "deep"
 	case qmdsrv1.Mode_MODE_AUTO:
 		return "auto"
+	case qmdsrv1.Mode_MODE_HYBRID:
+		return "hybrid"
 	default:
 		return "auto"
 	}
@@ -249,7 +461,7 @@ func allowFallbackFromProto(req *qmdsrv1.SearchRequest, requestedMode string, fa
 	}
 
 	switch requestedMode {
-	case "deep", "broad":
+	case "deep", "broad", "hybrid":
 		return true
 	case "core":
 		return false
@@ -271,6 +483,21 @@ func toProtoSearchResponse(resp *model.SearchResponse, routeLog []string) *qmdsr
 	}
 }
 
+func toProtoSearchFrame(stage string, final bool, resp *model.SearchResponse, routeLog []string) *qmdsrv1.SearchFrame {
+	return &qmdsrv1.SearchFrame{
+		Stage:         searchStageToProto(stage),
+		Final:         final,
+		Hits:          toProtoHits(resp.Results),
+		ServedMode:    servedModeToProto(resp.Meta.ServedMode),
+		Degraded:      resp.Meta.Degraded,
+		DegradeReason: strings.ToUpper(resp.Meta.DegradeReason),
+		LatencyMs:     resp.Meta.LatencyMs,
+		TraceId:       resp.Meta.TraceID,
+		RouteLog:      routeLog,
+		FormattedText: resp.FormattedText,
+	}
+}
+
 func toProtoSearchAndGetResponse(resp *model.SearchAndGetResponse) *qmdsrv1.SearchAndGetResponse {
 	docs := make([]*qmdsrv1.DocContent, 0, len(resp.Documents))
 	for _, d := range resp.Documents {
@@ -314,11 +541,26 @@ func servedModeToProto(mode string) qmdsrv1.ServedMode {
 		return qmdsrv1.ServedMode_SERVED_BROAD
 	case "core":
 		return qmdsrv1.ServedMode_SERVED_CORE
+	case "hybrid":
+		return qmdsrv1.ServedMode_SERVED_HYBRID
 	default:
 		return qmdsrv1.ServedMode_SERVED_UNSPECIFIED
 	}
 }
 
+func searchStageToProto(stage string) qmdsrv1.SearchStage {
+	switch stage {
+	case orchestrator.StageTier1:
+		return qmdsrv1.SearchStage_STAGE_TIER1
+	case orchestrator.StageTier2:
+		return qmdsrv1.SearchStage_STAGE_TIER2
+	case orchestrator.StageDeep:
+		return qmdsrv1.SearchStage_STAGE_DEEP
+	default:
+		return qmdsrv1.SearchStage_STAGE_UNSPECIFIED
+	}
+}
+
 func mapSearchError(err error) error {
 	if err == nil {
 		return nil
@@ -330,6 +572,8 @@ func mapSearchError(err error) error {
 	switch {
 	case errors.Is(err, errCriticalOverloadShed):
 		return status.Error(codes.ResourceExhausted, "RESOURCE_EXHAUSTED: "+msg)
+	case errors.Is(err, authz.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "PERMISSION_DENIED: "+msg)
 	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(lower, "deadline exceeded"):
 		return status.Error(codes.DeadlineExceeded, "QMD_TIMEOUT: "+msg)
 	case strings.Contains(lower, "outofmemory") || strings.Contains(lower, "resource exhausted"):
@@ -358,12 +602,20 @@ func mapAdminRPCError(err error) error {
 	switch {
 	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(lower, "deadline exceeded"):
 		return status.Error(codes.DeadlineExceeded, msg)
-	case errors.Is(err, errGuardianUnavailable) || strings.Contains(lower, "guardian not available"):
+	case errors.Is(err, errGuardianUnavailable) || strings.Contains(lower, "guardian not available") || errors.Is(err, scheduler.ErrStopped):
 		return status.Error(codes.Unavailable, msg)
+	case errors.Is(err, errConfigManagerUnavailable):
+		return status.Error(codes.Unimplemented, msg)
+	case errors.Is(err, config.ErrRejected):
+		return status.Error(codes.FailedPrecondition, msg)
+	case errors.Is(err, authz.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, msg)
 	case strings.Contains(lower, "requires confirm=true"):
 		return status.Error(codes.FailedPrecondition, msg)
 	case strings.Contains(lower, "not found"):
 		return status.Error(codes.NotFound, msg)
+	case errors.Is(err, scheduler.ErrTaskRunning) || strings.Contains(lower, "already exists"):
+		return status.Error(codes.AlreadyExists, msg)
 	case strings.Contains(lower, "invalid") || strings.Contains(lower, "required"):
 		return status.Error(codes.InvalidArgument, msg)
 	default:
@@ -371,12 +623,80 @@ func mapAdminRPCError(err error) error {
 	}
 }
 
+func collectionFromProto(spec *qmdsrv1.CollectionSpec) config.CollectionCfg {
+	return config.CollectionCfg{
+		Name:            spec.GetName(),
+		Path:            spec.GetPath(),
+		Mask:            spec.GetMask(),
+		Exclude:         spec.GetExclude(),
+		Context:         spec.GetContext(),
+		Tier:            int(spec.GetTier()),
+		Embed:           spec.GetEmbed(),
+		RequireExplicit: spec.GetRequireExplicit(),
+		SafetyPrompt:    spec.GetSafetyPrompt(),
+	}
+}
+
+func toProtoCollectionChange(res *adminCollectionChangeResult) *qmdsrv1.CollectionChangeResponse {
+	col := res.Collection
+	return &qmdsrv1.CollectionChangeResponse{
+		Ok:      true,
+		Message: res.Message,
+		Collection: &qmdsrv1.CollectionSpec{
+			Name:            col.Name,
+			Path:            col.Path,
+			Mask:            col.Mask,
+			Exclude:         col.Exclude,
+			Context:         col.Context,
+			Tier:            intToInt32(col.Tier),
+			Embed:           col.Embed,
+			RequireExplicit: col.RequireExplicit,
+			SafetyPrompt:    col.SafetyPrompt,
+		},
+		TraceId:   res.TraceID,
+		LatencyMs: res.LatencyMs,
+	}
+}
+
 func toProtoOpResponse(res *adminOpResult) *qmdsrv1.OpResponse {
 	return &qmdsrv1.OpResponse{
 		Ok:        true,
 		Message:   res.Message,
 		TraceId:   res.TraceID,
 		LatencyMs: res.LatencyMs,
+		JobId:     res.JobID,
+	}
+}
+
+func toProtoJob(job scheduler.Job) *qmdsrv1.Job {
+	out := &qmdsrv1.Job{
+		Id:              job.ID,
+		Kind:            job.Kind,
+		State:           toProtoJobState(job.State),
+		StartedAtUnixMs: job.StartedAt.UnixMilli(),
+		Retries:         intToInt32(job.Retries),
+		StderrTail:      job.StderrTail,
+		Error:           job.Err,
+		TraceId:         job.TraceID,
+	}
+	if !job.EndedAt.IsZero() {
+		out.EndedAtUnixMs = job.EndedAt.UnixMilli()
+	}
+	return out
+}
+
+func toProtoJobState(state scheduler.JobState) qmdsrv1.JobState {
+	switch state {
+	case scheduler.JobRunning:
+		return qmdsrv1.JobState_JOB_RUNNING
+	case scheduler.JobSucceeded:
+		return qmdsrv1.JobState_JOB_SUCCEEDED
+	case scheduler.JobFailed:
+		return qmdsrv1.JobState_JOB_FAILED
+	case scheduler.JobCancelled:
+		return qmdsrv1.JobState_JOB_CANCELLED
+	default:
+		return qmdsrv1.JobState_JOB_UNSPECIFIED
 	}
 }
 
@@ -389,5 +709,8 @@ func traceIDFromContext(ctx context.Context) string {
 			}
 		}
 	}
+	if trace := tracing.TraceID(ctx); trace != "" {
+		return trace
+	}
 	return genRequestID()
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"sync"
 	"testing"
 
 	"qmdsr/config"
@@ -17,12 +18,16 @@ import (
 )
 
 type fakeConfirmExec struct {
+	mu          sync.Mutex
 	searchCalls int
 	getCalls    int
+	multiDocs   []model.Document
 }
 
 func (f *fakeConfirmExec) Search(context.Context, string, executor.SearchOpts) ([]model.SearchResult, error) {
+	f.mu.Lock()
 	f.searchCalls++
+	f.mu.Unlock()
 	return []model.SearchResult{
 		{
 			Title:      "private note",
@@ -43,15 +48,18 @@ func (f *fakeConfirmExec) Query(ctx context.Context, query string, opts executor
 }
 
 func (f *fakeConfirmExec) Get(context.Context, string, executor.GetOpts) (string, error) {
+	f.mu.Lock()
 	f.getCalls++
+	f.mu.Unlock()
 	return "private content", nil
 }
 
 func (f *fakeConfirmExec) MultiGet(context.Context, string, int) ([]model.Document, error) {
-	return nil, nil
+	return f.multiDocs, nil
 }
 
 func (f *fakeConfirmExec) CollectionAdd(context.Context, string, string, string) error { return nil }
+func (f *fakeConfirmExec) CollectionRemove(context.Context, string) error              { return nil }
 
 func (f *fakeConfirmExec) CollectionList(context.Context) ([]model.CollectionInfo, error) {
 	return nil, nil
@@ -61,6 +69,8 @@ func (f *fakeConfirmExec) Update(context.Context) error { return nil }
 
 func (f *fakeConfirmExec) Embed(context.Context, bool) error { return nil }
 
+func (f *fakeConfirmExec) EmbedCollection(context.Context, string, bool) error { return nil }
+
 func (f *fakeConfirmExec) ContextAdd(context.Context, string, string) error { return nil }
 
 func (f *fakeConfirmExec) ContextList(context.Context) ([]model.PathContext, error) { return nil, nil }
@@ -174,3 +184,56 @@ func TestGRPCSearchAndGet_ConfirmRequiredForPersonalCollection(t *testing.T) {
 		t.Fatalf("expected one document, got %d", len(resp.GetDocuments()))
 	}
 }
+
+func TestGRPCGet_ConfirmRequiredForPersonalDocument(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	g := &grpcQueryServer{s: srv}
+	exec := srv.exec.(*fakeConfirmExec)
+
+	for _, ref := range []string{"qmd://personal/private-note.md", "/personal/private-note.md"} {
+		_, err := g.Get(context.Background(), &qmdsrv1.GetRequest{DocRef: ref})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("%s: expected FAILED_PRECONDITION, got err=%v code=%v", ref, err, status.Code(err))
+		}
+	}
+	if exec.getCalls != 0 {
+		t.Fatalf("did not expect executor get without confirm, got %d", exec.getCalls)
+	}
+
+	resp, err := g.Get(context.Background(), &qmdsrv1.GetRequest{DocRef: "qmd://personal/private-note.md", Confirm: true})
+	if err != nil {
+		t.Fatalf("expected success with confirm=true, got err=%v", err)
+	}
+	if resp.GetContent() != "private content" {
+		t.Fatalf("unexpected content: %q", resp.GetContent())
+	}
+}
+
+func TestGRPCMultiGet_PersonalDocumentsFilteredWithoutConfirm(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	srv.cfg.Collections = append(srv.cfg.Collections, config.CollectionCfg{Name: "notes", Path: "/notes", Tier: 1})
+	exec := srv.exec.(*fakeConfirmExec)
+	exec.multiDocs = []model.Document{
+		{File: "qmd://notes/todo.md", Content: "todo"},
+		{File: "qmd://personal/private-note.md", Content: "private content"},
+	}
+	g := &grpcQueryServer{s: srv}
+
+	_, err := g.MultiGet(context.Background(), &qmdsrv1.MultiGetRequest{Pattern: "personal/*.md"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FAILED_PRECONDITION for personal glob, got err=%v code=%v", err, status.Code(err))
+	}
+
+	resp, err := g.MultiGet(context.Background(), &qmdsrv1.MultiGetRequest{Pattern: "*.md"})
+	if err != nil {
+		t.Fatalf("MultiGet failed: %v", err)
+	}
+	if len(resp.GetDocuments()) != 1 || resp.GetDocuments()[0].GetFile() != "qmd://notes/todo.md" {
+		t.Fatalf("expected personal document filtered, got %+v", resp.GetDocuments())
+	}
+
+	resp, err = g.MultiGet(context.Background(), &qmdsrv1.MultiGetRequest{Pattern: "*.md", Confirm: true})
+	if err != nil || len(resp.GetDocuments()) != 2 {
+		t.Fatalf("expected both documents with confirm=true, got %+v err=%v", resp.GetDocuments(), err)
+	}
+}
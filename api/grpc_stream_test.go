@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/model"
+	"qmdsr/orchestrator"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+
+	"google.golang.org/grpc"
+)
+
+// fakeStreamExec answers BM25 immediately and holds the deep query until
+// release is closed, so tests can observe frames sent while deep is pending.
+type fakeStreamExec struct {
+	fakeConfirmExec
+	release chan struct{}
+}
+
+func (f *fakeStreamExec) Search(context.Context, string, executor.SearchOpts) ([]model.SearchResult, error) {
+	return []model.SearchResult{{File: "qmd://notes/bm25.md", Collection: "notes", Score: 0.6}}, nil
+}
+
+func (f *fakeStreamExec) Query(ctx context.Context, _ string, _ executor.SearchOpts) ([]model.SearchResult, error) {
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return []model.SearchResult{{File: "qmd://notes/deep.md", Collection: "notes", Score: 0.9}}, nil
+}
+
+func (f *fakeStreamExec) HasCapability(cap string) bool { return cap == "deep_query" }
+
+type fakeSearchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	frames []*qmdsrv1.SearchFrame
+	onSend func(*qmdsrv1.SearchFrame)
+}
+
+func (f *fakeSearchStream) Context() context.Context { return f.ctx }
+
+func (f *fakeSearchStream) Send(frame *qmdsrv1.SearchFrame) error {
+	f.frames = append(f.frames, frame)
+	if f.onSend != nil {
+		f.onSend(frame)
+	}
+	return nil
+}
+
+func newStreamTestServer(exec executor.Executor) *Server {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{Name: "notes", Path: "/notes", Tier: 1, Embed: true}},
+		Search: config.SearchConfig{
+			TopK:     3,
+			MaxChars: 4500,
+			CoarseK:  20,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return &Server{
+		cfg:  cfg,
+		orch: orchestrator.New(cfg, exec, nil, logger),
+		exec: exec,
+		log:  logger,
+	}
+}
+
+func TestGRPCSearchStream_Tier1FrameBeforeDeep(t *testing.T) {
+	exec := &fakeStreamExec{release: make(chan struct{})}
+	g := &grpcQueryServer{s: newStreamTestServer(exec)}
+
+	stream := &fakeSearchStream{ctx: context.Background()}
+	stream.onSend = func(frame *qmdsrv1.SearchFrame) {
+		if !frame.GetFinal() && frame.GetStage() == qmdsrv1.SearchStage_STAGE_TIER1 {
+			close(exec.release)
+		}
+	}
+
+	err := g.SearchStream(&qmdsrv1.SearchRequest{
+		Query:         "how does traffic shaping work",
+		RequestedMode: qmdsrv1.Mode_MODE_DEEP,
+	}, stream)
+	if err != nil {
+		t.Fatalf("SearchStream failed: %v", err)
+	}
+
+	if len(stream.frames) != 2 {
+		t.Fatalf("expected tier-1 frame and final frame, got %d frames", len(stream.frames))
+	}
+	first, final := stream.frames[0], stream.frames[1]
+	if first.GetFinal() || first.GetServedMode() != qmdsrv1.ServedMode_SERVED_BROAD {
+		t.Fatalf("unexpected first frame: %+v", first)
+	}
+	if len(first.GetHits()) != 1 || first.GetHits()[0].GetUri() != "qmd://notes/bm25.md" {
+		t.Fatalf("unexpected first frame hits: %+v", first.GetHits())
+	}
+	if !final.GetFinal() || final.GetStage() != qmdsrv1.SearchStage_STAGE_DEEP || final.GetServedMode() != qmdsrv1.ServedMode_SERVED_DEEP {
+		t.Fatalf("unexpected final frame: %+v", final)
+	}
+	if len(final.GetHits()) != 1 || final.GetHits()[0].GetUri() != "qmd://notes/deep.md" {
+		t.Fatalf("expected deep hits to supersede tier-1 hits, got %+v", final.GetHits())
+	}
+}
+
+func TestGRPCSearchStream_Tier2FallbackFrame(t *testing.T) {
+	exec := &fakeConfirmExec{}
+	srv := newStreamTestServer(exec)
+	srv.cfg.Collections = []config.CollectionCfg{
+		{Name: "empty", Path: "/empty", Tier: 1},
+		{Name: "personal", Path: "/personal", Tier: 2},
+	}
+	srv.cfg.Search.FallbackEnabled = true
+	srv.cfg.Search.MinScore = 0.95
+	g := &grpcQueryServer{s: srv}
+
+	stream := &fakeSearchStream{ctx: context.Background()}
+	err := g.SearchStream(&qmdsrv1.SearchRequest{
+		Query:         "private",
+		RequestedMode: qmdsrv1.Mode_MODE_BROAD,
+		MinScore:      0.95,
+	}, stream)
+	if err != nil {
+		t.Fatalf("SearchStream failed: %v", err)
+	}
+
+	stages := make([]qmdsrv1.SearchStage, 0, len(stream.frames))
+	for _, f := range stream.frames {
+		stages = append(stages, f.GetStage())
+	}
+	if len(stages) != 3 || stages[0] != qmdsrv1.SearchStage_STAGE_TIER1 || stages[1] != qmdsrv1.SearchStage_STAGE_TIER2 {
+		t.Fatalf("unexpected frame stages: %v", stages)
+	}
+	if !stream.frames[2].GetFinal() {
+		t.Fatalf("expected last frame to be final")
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+
+	"qmdsr/model"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// serviceComponents lists the heartbeat components a gRPC service cannot
+// serve without. The service reports NOT_SERVING while any of them is
+// unhealthy or critical; the server-wide "" entry only when the whole system
+// is critical.
+var serviceComponents = map[string][]string{
+	qmdsrv1.QueryService_ServiceDesc.ServiceName: {"qmd_cli", "index_db"},
+	qmdsrv1.AdminService_ServiceDesc.ServiceName: {"qmd_cli"},
+}
+
+// setServingStatus publishes the heartbeat's view of each service through
+// grpc.health.v1.
+func (s *Server) setServingStatus(health *grpcHealth.Server, h *model.SystemHealth) {
+	overall := healthpb.HealthCheckResponse_SERVING
+	if h.Overall >= model.Critical {
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.updateServingStatus(health, "", overall, h.OverallStr)
+
+	for service, components := range serviceComponents {
+		status, reason := healthpb.HealthCheckResponse_SERVING, ""
+		for _, name := range components {
+			if comp := h.Components[name]; comp != nil && comp.Level >= model.Unhealthy {
+				status, reason = healthpb.HealthCheckResponse_NOT_SERVING, name+": "+comp.Message
+				break
+			}
+		}
+		s.updateServingStatus(health, service, status, reason)
+	}
+}
+
+func (s *Server) updateServingStatus(health *grpcHealth.Server, service string, status healthpb.HealthCheckResponse_ServingStatus, reason string) {
+	prev, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	health.SetServingStatus(service, status)
+	if err == nil && prev.GetStatus() != status {
+		s.log.Warn("gRPC serving status changed", "service", service, "status", status.String(), "reason", reason)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"qmdsr/model"
+	qmdsrv1 "qmdsr/pb/qmdsrv1"
+
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestSetServingStatus_FollowsComponentHealth(t *testing.T) {
+	srv := newConfirmTestServer(t)
+	health := grpcHealth.NewServer()
+	query := qmdsrv1.QueryService_ServiceDesc.ServiceName
+	admin := qmdsrv1.AdminService_ServiceDesc.ServiceName
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) failed: %v", service, err)
+		}
+		return resp.GetStatus()
+	}
+	systemHealth := func(overall model.HealthLevel, comps map[string]model.HealthLevel) *model.SystemHealth {
+		h := &model.SystemHealth{Overall: overall, OverallStr: overall.String(), Components: map[string]*model.ComponentHealth{}}
+		for name, level := range comps {
+			h.Components[name] = &model.ComponentHealth{Name: name, Level: level, LevelStr: level.String()}
+		}
+		return h
+	}
+	serving, notServing := healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING
+
+	srv.setServingStatus(health, systemHealth(model.Degraded, map[string]model.HealthLevel{"qmd_cli": model.Healthy, "embeddings": model.Degraded}))
+	for _, service := range []string{"", query, admin} {
+		if got := check(service); got != serving {
+			t.Errorf("%q = %s with degraded embeddings, want SERVING", service, got)
+		}
+	}
+
+	srv.setServingStatus(health, systemHealth(model.Critical, map[string]model.HealthLevel{"qmd_cli": model.Healthy, "index_db": model.Critical}))
+	if got := check(query); got != notServing {
+		t.Errorf("QueryService = %s with critical index_db, want NOT_SERVING", got)
+	}
+	if got := check(admin); got != serving {
+		t.Errorf("AdminService = %s with critical index_db, want SERVING", got)
+	}
+	if got := check(""); got != notServing {
+		t.Errorf("overall = %s while critical, want NOT_SERVING", got)
+	}
+
+	srv.setServingStatus(health, systemHealth(model.Healthy, nil))
+	for _, service := range []string{"", query, admin} {
+		if got := check(service); got != serving {
+			t.Errorf("%q = %s after recovery, want SERVING", service, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+
+	"qmdsr/config"
+	"qmdsr/orchestrator"
+	"qmdsr/router"
+)
+
+func TestExecuteSearchCore_RouteLogNamesRule(t *testing.T) {
+	rules, err := router.ParseRules([]byte(`
+rules:
+  - name: runbooks
+    match: {keywords: [nginx]}
+    mode: search
+    collections: [runbooks]
+  - name: default
+    mode: search
+`))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/notes", Tier: 1},
+			{Name: "runbooks", Path: "/runbooks", Tier: 1},
+		},
+		Search:  config.SearchConfig{TopK: 3, MaxChars: 4500, CoarseK: 20, DefaultMode: "auto"},
+		Routing: config.RoutingConfig{Rules: rules},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exec := &fakeConfirmExec{}
+	srv := &Server{cfg: cfg, orch: orchestrator.New(cfg, exec, nil, logger), exec: exec, log: logger}
+
+	res, err := srv.executeSearchCore(context.Background(), searchCoreRequest{Query: "nginx upstream timeout", Explain: true})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := res.Response.Meta.CollectionsSearched; !reflect.DeepEqual(got, []string{"runbooks"}) {
+		t.Errorf("collections searched = %v, want the rule's collections", got)
+	}
+	for _, want := range []string{"route_rule=runbooks", "route_mode=search", "route_collections_rule=runbooks"} {
+		if !slices.Contains(res.RouteLog, want) {
+			t.Errorf("route_log %v is missing %q", res.RouteLog, want)
+		}
+	}
+
+	res, err = srv.executeSearchCore(context.Background(), searchCoreRequest{Query: "release checklist", Explain: true})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if !slices.Contains(res.RouteLog, "route_rule=default") || slices.ContainsFunc(res.RouteLog, func(s string) bool { return s == "route_collections_rule=runbooks" }) {
+		t.Errorf("unexpected route_log for a non-matching query: %v", res.RouteLog)
+	}
+}
+
+func TestExecuteSearchCore_RouteLogRecordsKeywordRewrite(t *testing.T) {
+	rules, err := router.ParseRules([]byte(`
+rules:
+  - name: no_deep
+    gate: deny
+  - name: default
+    mode: search
+`))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{Name: "notes", Path: "/notes", Tier: 1}},
+		Search:      config.SearchConfig{TopK: 3, MaxChars: 4500, CoarseK: 20, DefaultMode: "auto", RewriteMinWords: 10, RewriteMaxTerms: 6},
+		Routing:     config.RoutingConfig{Rules: rules},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exec := &fakeConfirmExec{}
+	srv := &Server{cfg: cfg, orch: orchestrator.New(cfg, exec, nil, logger), exec: exec, log: logger}
+
+	res, err := srv.executeSearchCore(context.Background(), searchCoreRequest{
+		Query:         "what did we decide about the traffic shaping setup on the OpenWrt router last spring",
+		RequestedMode: "deep",
+		AllowFallback: true,
+		Explain:       true,
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.Response.Meta.DegradeReason != "DEEP_GATE_REJECTED" {
+		t.Fatalf("degrade reason = %q, want DEEP_GATE_REJECTED", res.Response.Meta.DegradeReason)
+	}
+	for _, want := range []string{"bm25_rewrite=decide traffic shaping openwrt router spring", "bm25_rewrite=traffic shaping openwrt"} {
+		if !slices.Contains(res.RouteLog, want) {
+			t.Errorf("route_log %v is missing %q", res.RouteLog, want)
+		}
+	}
+}
+
+func TestExecuteSearchCore_RouteLogRecordsTierCascade(t *testing.T) {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/notes", Tier: 1},
+			{Name: "archive", Path: "/archive", Tier: 2},
+		},
+		Search: config.SearchConfig{
+			TopK: 3, MaxChars: 4500, CoarseK: 20, DefaultMode: "auto", FallbackEnabled: true,
+			Cascade: []config.TierPolicy{{Tier: 1, Always: true}, {Tier: 2, ScoreMultiplier: 0.5}},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exec := &fakeConfirmExec{}
+	srv := &Server{cfg: cfg, orch: orchestrator.New(cfg, exec, nil, logger), exec: exec, log: logger}
+
+	res, err := srv.executeSearchCore(context.Background(), searchCoreRequest{Query: "release checklist", RequestedMode: "core", AllowFallback: true, Explain: true})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := res.Response.Meta.CollectionsSearched; !reflect.DeepEqual(got, []string{"archive", "notes"}) {
+		t.Errorf("collections searched = %v, want both tiers", got)
+	}
+	for _, want := range []string{"cascade=tier1[notes] hits=1 top_score=0.90 fall_through=always", "cascade=tier2[archive] hits=1 top_score=0.90"} {
+		if !slices.Contains(res.RouteLog, want) {
+			t.Errorf("route_log %v is missing %q", res.RouteLog, want)
+		}
+	}
+}
+
+func TestExecuteSearchCore_RuleCollectionsSkipProtectedCollections(t *testing.T) {
+	rules, err := router.ParseRules([]byte(`
+rules:
+  - name: diary
+    match: {keywords: [diary]}
+    mode: search
+    collections: [personal, notes]
+  - name: secrets
+    match: {keywords: [secret]}
+    mode: search
+    collections: [personal]
+  - name: default
+    mode: search
+`))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/notes", Tier: 1},
+			{Name: "work", Path: "/work", Tier: 1},
+			{Name: "personal", Path: "/personal", Tier: 99, RequireExplicit: true, SafetyPrompt: true},
+		},
+		Search:  config.SearchConfig{TopK: 3, MaxChars: 4500, CoarseK: 20, DefaultMode: "auto"},
+		Routing: config.RoutingConfig{Rules: rules},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exec := &fakeConfirmExec{}
+	srv := &Server{cfg: cfg, orch: orchestrator.New(cfg, exec, nil, logger), exec: exec, log: logger}
+
+	cases := map[string][]string{
+		"diary entries":  {"notes"},
+		"secret project": {"notes", "work"},
+	}
+	for query, want := range cases {
+		res, err := srv.executeSearchCore(context.Background(), searchCoreRequest{Query: query, Explain: true})
+		if err != nil {
+			t.Fatalf("%q: unconfirmed search failed: %v", query, err)
+		}
+		if got := res.Response.Meta.CollectionsSearched; !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: collections searched = %v, want %v", query, got, want)
+		}
+	}
+}
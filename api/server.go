@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"log/slog"
+	"sync"
 
 	"qmdsr/config"
 	"qmdsr/executor"
@@ -14,9 +15,11 @@ import (
 	"qmdsr/scheduler"
 
 	"google.golang.org/grpc"
+	grpcHealth "google.golang.org/grpc/health"
 )
 
 type Server struct {
+	cfgMu     sync.RWMutex
 	cfg       *config.Config
 	orch      *orchestrator.Orchestrator
 	exec      executor.Executor
@@ -24,8 +27,10 @@ type Server struct {
 	guardian  *guardian.Guardian
 	heartbeat *heartbeat.Heartbeat
 	log       *slog.Logger
+	configMgr ConfigManager
 
 	grpcServer *grpc.Server
+	healthSrv  *grpcHealth.Server
 }
 
 type Deps struct {
@@ -36,6 +41,17 @@ type Deps struct {
 	Guardian     *guardian.Guardian
 	Heartbeat    *heartbeat.Heartbeat
 	Logger       *slog.Logger
+	// ConfigManager backs ReloadConfig and the collection admin RPCs; nil
+	// disables them.
+	ConfigManager ConfigManager
+}
+
+// ConfigManager applies config changes to the running server.
+type ConfigManager interface {
+	Reload(ctx context.Context) (config.Diff, error)
+	AddCollection(ctx context.Context, col config.CollectionCfg) error
+	UpdateCollection(ctx context.Context, col config.CollectionCfg) error
+	RemoveCollection(ctx context.Context, name string) error
 }
 
 func NewServer(deps Deps) *Server {
@@ -47,9 +63,23 @@ func NewServer(deps Deps) *Server {
 		guardian:  deps.Guardian,
 		heartbeat: deps.Heartbeat,
 		log:       deps.Logger,
+		configMgr: deps.ConfigManager,
 	}
 }
 
+func (s *Server) config() *config.Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// ApplyConfig switches request defaults to a reloaded config.
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	s.cfg = cfg
+}
+
 func (s *Server) Start() error {
 	return s.startGRPC()
 }
@@ -58,6 +88,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	if s.grpcServer == nil {
 		return nil
 	}
+	s.healthSrv.Shutdown()
 
 	done := make(chan struct{})
 	go func() {
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTraceInterceptor_ContinuesTraceparent(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01"))
+
+	var gotTraceID string
+	_, err := traceUnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/qmdsr.v1.QueryService/Search"},
+		func(ctx context.Context, _ any) (any, error) {
+			gotTraceID = traceIDFromContext(ctx)
+			return nil, nil
+		})
+	if err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+	if gotTraceID != traceID {
+		t.Fatalf("expected handler trace id %s, got %s", traceID, gotTraceID)
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "/qmdsr.v1.QueryService/Search" || s.SpanKind != trace.SpanKindServer {
+		t.Fatalf("unexpected span %s kind %v", s.Name, s.SpanKind)
+	}
+	if s.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("expected remote parent span, got %s", s.Parent.SpanID())
+	}
+}
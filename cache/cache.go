@@ -2,9 +2,12 @@ package cache
 
 import (
 	"container/list"
+	"maps"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"qmdsr/config"
@@ -12,15 +15,25 @@ import (
 )
 
 type Entry struct {
-	Results           []model.SearchResult
-	IndexVersion      string
-	CreatedAt         time.Time
-	Query             string
-	Mode              string
-	Collection        string
-	FallbackTriggered bool
-	Degraded          bool
-	DegradeReason     string
+	Results      []model.SearchResult
+	IndexVersion string
+	CreatedAt    time.Time
+	Query        string
+	Mode         string
+	Collection   string
+	// Collections lists every collection the answer depends on, including
+	// tiers that were consulted but produced no hits. Empty means unknown,
+	// in which case any collection change invalidates the entry.
+	Collections        []string
+	CollectionVersions map[string]string
+	FallbackTriggered  bool
+	Cascade            []model.TierStep
+	// BM25Queries are the keyword rewrites the answer was searched with and
+	// Expansions the synonym expansions applied to them.
+	BM25Queries   []string
+	Expansions    []string
+	Degraded      bool
+	DegradeReason string
 }
 
 type Cache struct {
@@ -31,7 +44,9 @@ type Cache struct {
 	ttl          time.Duration
 	versionAware bool
 	version      string
-	enabled      bool
+	enabled      atomic.Bool
+
+	collectionVersions map[string]string
 
 	hits   int64
 	misses int64
@@ -43,18 +58,46 @@ type cacheItem struct {
 }
 
 func New(cfg *config.CacheConfig) *Cache {
-	return &Cache{
-		items:        make(map[string]*list.Element),
-		order:        list.New(),
-		maxEntries:   cfg.MaxEntries,
-		ttl:          cfg.TTL,
-		versionAware: cfg.VersionAware,
-		enabled:      cfg.Enabled,
+	c := &Cache{
+		items:              make(map[string]*list.Element),
+		order:              list.New(),
+		maxEntries:         cfg.MaxEntries,
+		ttl:                cfg.TTL,
+		versionAware:       cfg.VersionAware,
+		collectionVersions: make(map[string]string),
+	}
+	c.enabled.Store(cfg.Enabled)
+	return c
+}
+
+// Reconfigure applies reloaded cache settings. Entries beyond a smaller
+// capacity are evicted in LRU order and disabling the cache drops everything.
+// It returns the number of entries removed.
+func (c *Cache) Reconfigure(cfg *config.CacheConfig) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = cfg.MaxEntries
+	c.ttl = cfg.TTL
+	c.versionAware = cfg.VersionAware
+	c.enabled.Store(cfg.Enabled)
+
+	removed := 0
+	if !cfg.Enabled {
+		removed = c.order.Len()
+		c.items = make(map[string]*list.Element)
+		c.order.Init()
+		return removed
 	}
+	for c.order.Len() > c.maxEntries {
+		c.evict()
+		removed++
+	}
+	return removed
 }
 
 func (c *Cache) Get(key string) (*Entry, bool) {
-	if !c.enabled {
+	if !c.enabled.Load() {
 		return nil, false
 	}
 
@@ -71,23 +114,13 @@ func (c *Cache) Get(key string) (*Entry, bool) {
 
 	item := elem.Value.(*cacheItem)
 
-	if time.Since(item.entry.CreatedAt) > c.ttl {
-		c.mu.Lock()
-		c.remove(key)
-		c.misses++
-		c.mu.Unlock()
-		return nil, false
-	}
-
-	if c.versionAware && item.entry.IndexVersion != c.version {
-		c.mu.Lock()
+	c.mu.Lock()
+	if time.Since(item.entry.CreatedAt) > c.ttl || c.stale(&item.entry) {
 		c.remove(key)
 		c.misses++
 		c.mu.Unlock()
 		return nil, false
 	}
-
-	c.mu.Lock()
 	c.order.MoveToFront(elem)
 	c.hits++
 	c.mu.Unlock()
@@ -96,16 +129,21 @@ func (c *Cache) Get(key string) (*Entry, bool) {
 }
 
 func (c *Cache) Put(key string, entry Entry) {
-	if !c.enabled {
+	if !c.enabled.Load() {
 		return
 	}
 
-	entry.IndexVersion = c.version
 	entry.CreatedAt = time.Now()
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if len(entry.Collections) == 0 && entry.Collection != "" {
+		entry.Collections = splitCollections(entry.Collection)
+	}
+	entry.IndexVersion = c.version
+	entry.CollectionVersions = c.snapshotVersions(entry.Collections)
+
 	if elem, ok := c.items[key]; ok {
 		c.order.MoveToFront(elem)
 		elem.Value.(*cacheItem).entry = entry
@@ -134,6 +172,101 @@ func (c *Cache) Clear() {
 	c.order.Init()
 }
 
+// SetCollectionVersions records the current index version of each named
+// collection and drops entries that depend on a collection whose version
+// changed. It returns the collections that changed and the entries removed.
+func (c *Cache) SetCollectionVersions(versions map[string]string) ([]string, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var changed []string
+	for name, v := range versions {
+		if cur, ok := c.collectionVersions[name]; ok && cur == v {
+			continue
+		}
+		c.collectionVersions[name] = v
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	if len(changed) == 0 {
+		return nil, 0
+	}
+	return changed, c.removeChangedLocked()
+}
+
+// InvalidateCollections bumps the version of the named collections and drops
+// the entries that depend on them. Entries of untouched collections survive.
+func (c *Cache) InvalidateCollections(names []string) int {
+	if len(names) == 0 {
+		return 0
+	}
+	version := time.Now().Format("20060102150405.000000000")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range names {
+		c.collectionVersions[name] = version
+	}
+	return c.removeChangedLocked()
+}
+
+func (c *Cache) removeChangedLocked() int {
+	removed := 0
+	for key, elem := range c.items {
+		item := elem.Value.(*cacheItem)
+		if !c.collectionsChanged(&item.entry) {
+			continue
+		}
+		c.order.Remove(elem)
+		delete(c.items, key)
+		removed++
+	}
+	return removed
+}
+
+// stale reports whether entry was built against an older index. Callers must
+// hold c.mu.
+func (c *Cache) stale(entry *Entry) bool {
+	if !c.versionAware {
+		return false
+	}
+	return entry.IndexVersion != c.version || c.collectionsChanged(entry)
+}
+
+func (c *Cache) collectionsChanged(entry *Entry) bool {
+	if len(entry.Collections) == 0 && len(entry.CollectionVersions) != len(c.collectionVersions) {
+		return true
+	}
+	for name, v := range entry.CollectionVersions {
+		if c.collectionVersions[name] != v {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) snapshotVersions(collections []string) map[string]string {
+	if len(collections) == 0 {
+		return maps.Clone(c.collectionVersions)
+	}
+	out := make(map[string]string, len(collections))
+	for _, name := range collections {
+		out[name] = c.collectionVersions[name]
+	}
+	return out
+}
+
+func splitCollections(s string) []string {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 func (c *Cache) Cleanup() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -141,9 +274,7 @@ func (c *Cache) Cleanup() int {
 	removed := 0
 	for key, elem := range c.items {
 		item := elem.Value.(*cacheItem)
-		expired := time.Since(item.entry.CreatedAt) > c.ttl
-		versionMismatch := c.versionAware && item.entry.IndexVersion != c.version
-		if expired || versionMismatch {
+		if time.Since(item.entry.CreatedAt) > c.ttl || c.stale(&item.entry) {
 			c.order.Remove(elem)
 			delete(c.items, key)
 			removed++
@@ -152,7 +283,7 @@ func (c *Cache) Cleanup() int {
 	return removed
 }
 
-// Stats is reserved for future status/metrics endpoints.
+// Stats backs the cache gauges and counters exported on /metrics.
 func (c *Cache) Stats() (size int, hits, misses int64) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -1,6 +1,11 @@
 package cache
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"qmdsr/config"
+)
 
 func TestMakeCacheKey_DiffersByFilesAll(t *testing.T) {
 	a := MakeCacheKey("q", "search", "alpha", 0.3, 8, true, true, false)
@@ -9,3 +14,65 @@ func TestMakeCacheKey_DiffersByFilesAll(t *testing.T) {
 		t.Fatalf("expected distinct cache key when files_all differs")
 	}
 }
+
+func TestInvalidateCollections_OnlyTouchedEntries(t *testing.T) {
+	c := New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	c.Put("a", Entry{Collection: "claw-memory"})
+	c.Put("b", Entry{Collection: "digital,yozo"})
+	c.Put("c", Entry{Collection: "claw-memory,digital"})
+
+	if removed := c.InvalidateCollections([]string{"claw-memory"}); removed != 2 {
+		t.Fatalf("expected 2 entries removed, got %d", removed)
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Fatalf("expected unrelated entry to survive")
+	}
+	if _, ok := c.Get("c"); ok {
+		t.Fatalf("expected cross-collection entry to be invalidated")
+	}
+}
+
+func TestCollectionVersions_StaleOnlyForDependencies(t *testing.T) {
+	c := New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true})
+	c.Put("memory", Entry{Collections: []string{"claw-memory"}})
+	c.Put("cross", Entry{Collection: "digital", Collections: []string{"digital", "yozo"}})
+	c.Put("unknown", Entry{})
+
+	c.InvalidateCollections([]string{"yozo"})
+
+	if _, ok := c.Get("memory"); !ok {
+		t.Fatalf("expected claw-memory entry to survive a yozo reindex")
+	}
+	if _, ok := c.Get("cross"); ok {
+		t.Fatalf("expected entry depending on yozo to be invalidated")
+	}
+	if _, ok := c.Get("unknown"); ok {
+		t.Fatalf("expected entry without dependencies to be invalidated")
+	}
+}
+
+func TestReconfigure_ShrinksInLRUOrder(t *testing.T) {
+	c := New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 3})
+	c.Put("a", Entry{})
+	c.Put("b", Entry{})
+	c.Put("c", Entry{})
+	c.Get("a")
+
+	if removed := c.Reconfigure(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 2}); removed != 1 {
+		t.Fatalf("expected 1 entry evicted, got %d", removed)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected recently used entry to survive")
+	}
+
+	if removed := c.Reconfigure(&config.CacheConfig{Enabled: false, TTL: time.Minute, MaxEntries: 2}); removed != 2 {
+		t.Fatalf("expected disabling to drop 2 entries, got %d", removed)
+	}
+	c.Put("d", Entry{})
+	if size, _, _ := c.Stats(); size != 0 {
+		t.Fatalf("expected disabled cache to stay empty, got %d", size)
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"maps"
+	"time"
+
+	"qmdsr/internal/snapshot"
+)
+
+const (
+	snapshotKind    = "result_cache"
+	snapshotVersion = 1
+)
+
+type cacheSnapshot struct {
+	IndexVersion       string            `json:"index_version"`
+	CollectionVersions map[string]string `json:"collection_versions"`
+	// Entries are ordered most recently used first.
+	Entries []snapshotEntry `json:"entries"`
+}
+
+type snapshotEntry struct {
+	Key   string `json:"key"`
+	Entry Entry  `json:"entry"`
+}
+
+// SaveSnapshot writes all live entries together with the index versions they
+// were validated against.
+func (c *Cache) SaveSnapshot(path string) (int, error) {
+	if !c.enabled.Load() {
+		return 0, nil
+	}
+
+	c.mu.RLock()
+	snap := cacheSnapshot{
+		IndexVersion:       c.version,
+		CollectionVersions: maps.Clone(c.collectionVersions),
+		Entries:            make([]snapshotEntry, 0, c.order.Len()),
+	}
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		item := elem.Value.(*cacheItem)
+		if time.Since(item.entry.CreatedAt) > c.ttl || c.stale(&item.entry) {
+			continue
+		}
+		snap.Entries = append(snap.Entries, snapshotEntry{Key: item.key, Entry: item.entry})
+	}
+	c.mu.RUnlock()
+
+	if err := snapshot.Write(path, snapshotKind, snapshotVersion, snap); err != nil {
+		return 0, err
+	}
+	return len(snap.Entries), nil
+}
+
+// LoadSnapshot restores entries saved by SaveSnapshot. Entries past their TTL
+// are skipped; the restored collection versions make entries stale as soon as
+// the next index refresh reports a different version for their collections.
+func (c *Cache) LoadSnapshot(path string) (int, error) {
+	if !c.enabled.Load() {
+		return 0, nil
+	}
+
+	var snap cacheSnapshot
+	if _, err := snapshot.Read(path, snapshotKind, snapshotVersion, &snap); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version = snap.IndexVersion
+	for name, v := range snap.CollectionVersions {
+		c.collectionVersions[name] = v
+	}
+
+	entries := snap.Entries
+	if len(entries) > c.maxEntries {
+		entries = entries[:c.maxEntries]
+	}
+	loaded := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		se := entries[i]
+		if se.Key == "" || time.Since(se.Entry.CreatedAt) > c.ttl || c.stale(&se.Entry) {
+			continue
+		}
+		if _, exists := c.items[se.Key]; exists {
+			continue
+		}
+		item := &cacheItem{key: se.Key, entry: se.Entry}
+		c.items[se.Key] = c.order.PushFront(item)
+		loaded++
+	}
+	return loaded, nil
+}
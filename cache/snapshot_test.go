@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/internal/snapshot"
+	"qmdsr/model"
+)
+
+func TestSnapshot_RoundTripRespectsTTLAndVersions(t *testing.T) {
+	cfg := &config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true}
+	path := filepath.Join(t.TempDir(), "result_cache.json")
+
+	c := New(cfg)
+	c.SetCollectionVersions(map[string]string{"notes": "v1", "work": "v1"})
+	c.Put("fresh", Entry{Collections: []string{"notes"}, Results: []model.SearchResult{{File: "qmd://notes/a.md", Score: 0.8}}})
+	c.Put("work", Entry{Collections: []string{"work"}})
+	c.Put("expired", Entry{Collections: []string{"notes"}})
+	c.items["expired"].Value.(*cacheItem).entry.CreatedAt = time.Now().Add(-2 * time.Minute)
+
+	if n, err := c.SaveSnapshot(path); err != nil || n != 2 {
+		t.Fatalf("SaveSnapshot = %d, %v; want 2 entries", n, err)
+	}
+
+	restored := New(cfg)
+	if n, err := restored.LoadSnapshot(path); err != nil || n != 2 {
+		t.Fatalf("LoadSnapshot = %d, %v; want 2 entries", n, err)
+	}
+	entry, ok := restored.Get("fresh")
+	if !ok || len(entry.Results) != 1 || entry.Results[0].File != "qmd://notes/a.md" {
+		t.Fatalf("expected fresh entry restored, got %+v ok=%v", entry, ok)
+	}
+
+	restored.SetCollectionVersions(map[string]string{"notes": "v1", "work": "v2"})
+	if _, ok := restored.Get("work"); ok {
+		t.Fatalf("expected restored entry to be invalidated by a newer collection version")
+	}
+	if _, ok := restored.Get("fresh"); !ok {
+		t.Fatalf("expected unchanged collection entry to survive")
+	}
+}
+
+func TestLoadSnapshot_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result_cache.json")
+	if err := os.WriteFile(path, []byte(`{"kind":"result_cache","version":1,"payl`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	if _, err := c.LoadSnapshot(path); !errors.Is(err, snapshot.ErrCorrupt) {
+		t.Fatalf("expected corrupt snapshot error, got %v", err)
+	}
+	if size, _, _ := c.Stats(); size != 0 {
+		t.Fatalf("expected empty cache after corrupt load, got %d entries", size)
+	}
+}
@@ -7,6 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"qmdsr/internal/schedule"
+	"qmdsr/internal/segment"
+	"qmdsr/internal/synonym"
+	"qmdsr/router"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,44 +20,117 @@ type Config struct {
 	Server      ServerConfig    `yaml:"server"`
 	Collections []CollectionCfg `yaml:"collections"`
 	Search      SearchConfig    `yaml:"search"`
+	Routing     RoutingConfig   `yaml:"routing"`
 	Cache       CacheConfig     `yaml:"cache"`
 	Scheduler   SchedulerConfig `yaml:"scheduler"`
+	Watcher     WatcherConfig   `yaml:"watcher"`
+	Tracing     TracingConfig   `yaml:"tracing"`
 	Guardian    GuardianConfig  `yaml:"guardian"`
+	SelfHeal    SelfHealConfig  `yaml:"self_heal"`
 	Logging     LoggingConfig   `yaml:"logging"`
 	Runtime     RuntimeConfig   `yaml:"runtime"`
 }
 
 type QMDConfig struct {
-	Bin     string `yaml:"bin"`
-	IndexDB string `yaml:"index_db"`
-	MCPPort int    `yaml:"mcp_port"`
+	Bin          string `yaml:"bin"`
+	IndexDB      string `yaml:"index_db"`
+	MCPPort      int    `yaml:"mcp_port"`
+	MCPPath      string `yaml:"mcp_path"`
+	SearchViaMCP bool   `yaml:"search_via_mcp"`
+	// Reconcile is the startup drift pass: "report", "repair" or "off".
+	Reconcile string `yaml:"reconcile"`
 }
 
 type ServerConfig struct {
 	GRPCListen    string `yaml:"grpc_listen"`
 	SecurityModel string `yaml:"security_model"`
+	TokenFile     string `yaml:"token_file"`
+	StateDir      string `yaml:"state_dir"`
+	MetricsListen string `yaml:"metrics_listen"`
+	// CollectionsOverlay stores collections added, updated or removed through
+	// the admin RPCs. It is merged over Collections on every load.
+	CollectionsOverlay string `yaml:"collections_overlay"`
 }
 
 type CollectionCfg struct {
-	Name            string   `yaml:"name"`
-	Path            string   `yaml:"path"`
-	Mask            string   `yaml:"mask"`
-	Exclude         []string `yaml:"exclude"`
-	Context         string   `yaml:"context"`
-	Tier            int      `yaml:"tier"`
-	Embed           bool     `yaml:"embed"`
-	RequireExplicit bool     `yaml:"require_explicit"`
-	SafetyPrompt    bool     `yaml:"safety_prompt"`
+	Name    string   `yaml:"name"`
+	Path    string   `yaml:"path"`
+	Mask    string   `yaml:"mask"`
+	Exclude []string `yaml:"exclude"`
+	Context string   `yaml:"context"`
+	Tier    int      `yaml:"tier"`
+	Embed   bool     `yaml:"embed"`
+	// EmbedInterval overrides scheduler.embed_refresh for this collection.
+	EmbedInterval   time.Duration `yaml:"embed_interval,omitempty"`
+	RequireExplicit bool          `yaml:"require_explicit"`
+	SafetyPrompt    bool          `yaml:"safety_prompt"`
+	// SynonymsFile adds synonyms for searches of this collection only.
+	SynonymsFile string `yaml:"synonyms_file,omitempty"`
+	// Synonyms is the parsed synonyms file, filled in by Load.
+	Synonyms synonym.Dict `yaml:"-"`
 }
 
 type SearchConfig struct {
-	DefaultMode     string  `yaml:"default_mode"`
-	CoarseK         int     `yaml:"coarse_k"`
-	TopK            int     `yaml:"top_k"`
-	MinScore        float64 `yaml:"min_score"`
-	MaxChars        int     `yaml:"max_chars"`
-	FilesAllMaxHits int     `yaml:"files_all_max_hits"`
-	FallbackEnabled bool    `yaml:"fallback_enabled"`
+	DefaultMode        string  `yaml:"default_mode"`
+	CoarseK            int     `yaml:"coarse_k"`
+	TopK               int     `yaml:"top_k"`
+	MinScore           float64 `yaml:"min_score"`
+	MaxChars           int     `yaml:"max_chars"`
+	FilesAllMaxHits    int     `yaml:"files_all_max_hits"`
+	FallbackEnabled    bool    `yaml:"fallback_enabled"`
+	HybridBM25Weight   float64 `yaml:"hybrid_bm25_weight"`
+	HybridVectorWeight float64 `yaml:"hybrid_vector_weight"`
+	HybridRRFK         int     `yaml:"hybrid_rrf_k"`
+	// Segmenter tokenizes Chinese text in BM25 queries: "dict" segments it
+	// with the built-in dictionary and UserDict, "none" passes queries
+	// through unchanged. The built-in dictionary only has about 650 common
+	// and technical words; anything else is split into single characters
+	// unless UserDict lists it.
+	Segmenter string `yaml:"segmenter"`
+	UserDict  string `yaml:"user_dict"`
+	// UserWords is the parsed user dictionary, filled in by Load.
+	UserWords segment.Dict `yaml:"-"`
+	// Queries of at least RewriteMinWords words that are served by BM25
+	// instead of a richer mode are rewritten into keyword queries of at most
+	// RewriteMaxTerms terms. -1 disables the rewrite.
+	RewriteMinWords int `yaml:"rewrite_min_words"`
+	RewriteMaxTerms int `yaml:"rewrite_max_terms"`
+	// SynonymsFile lists synonym groups used by every collection.
+	SynonymsFile string `yaml:"synonyms_file"`
+	// Synonyms is the parsed synonyms file, filled in by Load.
+	Synonyms synonym.Dict `yaml:"-"`
+	// Aliases selects extra synonym sources: "frontmatter" harvests the
+	// aliases of indexed notes, "none" disables harvesting.
+	Aliases string `yaml:"aliases"`
+	// BM25 queries are expanded into at most SynonymMaxVariants variants,
+	// fused with weight SynonymWeight against 1 for the query itself. -1
+	// disables expansion.
+	SynonymMaxVariants int     `yaml:"synonym_max_variants"`
+	SynonymWeight      float64 `yaml:"synonym_weight"`
+	// Cascade orders the tiers a search without an explicit collection
+	// goes through. Empty cascades over every configured tier below 99 in
+	// ascending order, falling through when a tier has no hits.
+	Cascade []TierPolicy `yaml:"cascade"`
+}
+
+// TierPolicy decides when a cascading search continues past a tier. The
+// next tier runs when this one has fewer than MinHits hits (default 1), its
+// best score is below MinTopScore, or Always is set; the hits of all tiers
+// run are merged with each tier's scores scaled by ScoreMultiplier.
+type TierPolicy struct {
+	Tier            int     `yaml:"tier"`
+	MinHits         int     `yaml:"min_hits"`
+	MinTopScore     float64 `yaml:"min_top_score"`
+	Always          bool    `yaml:"always"`
+	ScoreMultiplier float64 `yaml:"score_multiplier"`
+}
+
+// RoutingConfig points at the routing rule file. Without one the built-in
+// rules apply.
+type RoutingConfig struct {
+	RulesFile string `yaml:"rules_file"`
+	// Rules is the parsed rule file, filled in by Load.
+	Rules router.RuleSet `yaml:"-"`
 }
 
 type CacheConfig struct {
@@ -61,6 +139,8 @@ type CacheConfig struct {
 	MaxEntries      int           `yaml:"max_entries"`
 	CleanupInterval time.Duration `yaml:"cleanup_interval"`
 	VersionAware    bool          `yaml:"version_aware"`
+	Persist         bool          `yaml:"persist"`
+	PersistInterval time.Duration `yaml:"persist_interval"`
 }
 
 type SchedulerConfig struct {
@@ -68,6 +148,50 @@ type SchedulerConfig struct {
 	EmbedRefresh     time.Duration `yaml:"embed_refresh"`
 	EmbedFullRefresh time.Duration `yaml:"embed_full_refresh"`
 	CacheCleanup     time.Duration `yaml:"cache_cleanup"`
+	// IdleWindow is how long the host must stay idle (no CPU overload, no
+	// search in flight) before a scheduled embed starts.
+	IdleWindow time.Duration `yaml:"idle_window"`
+	// MaxDeferral bounds how long a busy host can hold a task back.
+	MaxDeferral time.Duration `yaml:"max_deferral"`
+	// Nice and IONiceClass apply to spawned update/embed processes; 0 and
+	// "none" leave the priority alone. Nice is a pointer so an explicit 0
+	// differs from an unset value, which defaults to 10.
+	Nice        *int   `yaml:"nice"`
+	IONiceClass string `yaml:"ionice_class"`
+	// IndexRefreshCron and EmbedFullRefreshCron, when set, replace the
+	// matching interval with a five-field cron expression in local time.
+	IndexRefreshCron     string `yaml:"index_refresh_cron"`
+	EmbedFullRefreshCron string `yaml:"embed_full_refresh_cron"`
+	// Jitter delays every scheduled run by a random amount up to this value.
+	Jitter time.Duration `yaml:"jitter"`
+	// QuietHours lists local HH:MM-HH:MM ranges, comma separated, in which
+	// scheduled update and embed runs must not start.
+	QuietHours string `yaml:"quiet_hours"`
+	// CatchUp decides what happens to runs missed while qmdsr was down:
+	// "run" starts them once after startup, "skip" waits for the next slot.
+	CatchUp string `yaml:"catch_up"`
+}
+
+// NiceLevel returns the configured nice value, 0 when unset.
+func (s SchedulerConfig) NiceLevel() int {
+	if s.Nice == nil {
+		return 0
+	}
+	return *s.Nice
+}
+
+type WatcherConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Debounce time.Duration `yaml:"debounce"`
+	MaxWait  time.Duration `yaml:"max_wait"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`
+	TLS         bool    `yaml:"tls"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
 }
 
 type GuardianConfig struct {
@@ -76,6 +200,18 @@ type GuardianConfig struct {
 	RestartMaxRetries int           `yaml:"restart_max_retries"`
 }
 
+// SelfHealConfig controls the heartbeat remediations that run qmd update,
+// embed or collection add when a check finds the index empty, the vectors
+// missing or collections gone.
+type SelfHealConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	MinInterval time.Duration `yaml:"min_interval"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	// MaxAttempts consecutive failures stop an action until the component
+	// recovers.
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
 type LoggingConfig struct {
 	Level      string `yaml:"level"`
 	File       string `yaml:"file"`
@@ -124,6 +260,38 @@ func Load(path string) (*Config, error) {
 
 	cfg.normalize()
 
+	overlay, err := LoadOverlay(cfg.Server.CollectionsOverlay)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Collections = overlay.Apply(cfg.Collections)
+
+	if cfg.Routing.RulesFile != "" {
+		if cfg.Routing.Rules, err = router.LoadRules(cfg.Routing.RulesFile); err != nil {
+			return nil, fmt.Errorf("validate config: routing.rules_file: %w", err)
+		}
+	}
+	if cfg.Search.UserDict != "" {
+		if cfg.Search.UserWords, err = segment.LoadDict(cfg.Search.UserDict); err != nil {
+			return nil, fmt.Errorf("validate config: search.user_dict: %w", err)
+		}
+	}
+	if cfg.Search.SynonymsFile != "" {
+		if cfg.Search.Synonyms, err = synonym.Load(cfg.Search.SynonymsFile); err != nil {
+			return nil, fmt.Errorf("validate config: search.synonyms_file: %w", err)
+		}
+	}
+	for i := range cfg.Collections {
+		col := &cfg.Collections[i]
+		if col.SynonymsFile == "" {
+			continue
+		}
+		col.SynonymsFile = expandClean(col.SynonymsFile)
+		if col.Synonyms, err = synonym.Load(col.SynonymsFile); err != nil {
+			return nil, fmt.Errorf("validate config: collection %s: synonyms_file: %w", col.Name, err)
+		}
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("validate config: %w", err)
 	}
@@ -135,6 +303,12 @@ func (c *Config) normalize() {
 	c.QMD.Bin = expandClean(c.QMD.Bin)
 	c.QMD.IndexDB = expandClean(c.QMD.IndexDB)
 	c.Logging.File = expandClean(c.Logging.File)
+	c.Server.TokenFile = expandClean(c.Server.TokenFile)
+	c.Server.StateDir = expandClean(c.Server.StateDir)
+	c.Server.CollectionsOverlay = expandClean(c.Server.CollectionsOverlay)
+	c.Routing.RulesFile = expandClean(c.Routing.RulesFile)
+	c.Search.UserDict = expandClean(c.Search.UserDict)
+	c.Search.SynonymsFile = expandClean(c.Search.SynonymsFile)
 
 	for i := range c.Collections {
 		c.Collections[i].Path = expandClean(c.Collections[i].Path)
@@ -143,12 +317,24 @@ func (c *Config) normalize() {
 	if c.Server.GRPCListen == "" {
 		c.Server.GRPCListen = "127.0.0.1:19091"
 	}
+	if c.Server.StateDir == "" {
+		c.Server.StateDir = defaultStateDir()
+	}
+	if c.Server.CollectionsOverlay == "" {
+		c.Server.CollectionsOverlay = filepath.Join(c.Server.StateDir, "collections.yaml")
+	}
 	if c.Server.SecurityModel == "" {
 		c.Server.SecurityModel = "loopback_trust"
 	}
 	if c.QMD.MCPPort == 0 {
 		c.QMD.MCPPort = 8181
 	}
+	if c.QMD.MCPPath == "" {
+		c.QMD.MCPPath = "/mcp"
+	}
+	if c.QMD.Reconcile == "" {
+		c.QMD.Reconcile = "report"
+	}
 	if c.Search.DefaultMode == "" {
 		c.Search.DefaultMode = "auto"
 	}
@@ -167,6 +353,41 @@ func (c *Config) normalize() {
 	if c.Search.FilesAllMaxHits == 0 {
 		c.Search.FilesAllMaxHits = 200
 	}
+	if c.Search.HybridBM25Weight == 0 {
+		c.Search.HybridBM25Weight = 1.0
+	}
+	if c.Search.HybridVectorWeight == 0 {
+		c.Search.HybridVectorWeight = 1.0
+	}
+	if c.Search.HybridRRFK == 0 {
+		c.Search.HybridRRFK = 60
+	}
+	if c.Search.Segmenter == "" {
+		c.Search.Segmenter = "dict"
+	}
+	if c.Search.RewriteMinWords == 0 {
+		c.Search.RewriteMinWords = 10
+	}
+	if c.Search.RewriteMaxTerms == 0 {
+		c.Search.RewriteMaxTerms = 6
+	}
+	if c.Search.Aliases == "" {
+		c.Search.Aliases = "frontmatter"
+	}
+	if c.Search.SynonymMaxVariants == 0 {
+		c.Search.SynonymMaxVariants = 4
+	}
+	if c.Search.SynonymWeight == 0 {
+		c.Search.SynonymWeight = 0.5
+	}
+	for i := range c.Search.Cascade {
+		if c.Search.Cascade[i].MinHits == 0 {
+			c.Search.Cascade[i].MinHits = 1
+		}
+		if c.Search.Cascade[i].ScoreMultiplier == 0 {
+			c.Search.Cascade[i].ScoreMultiplier = 1
+		}
+	}
 	if c.Cache.TTL == 0 {
 		c.Cache.TTL = 30 * time.Minute
 	}
@@ -176,6 +397,9 @@ func (c *Config) normalize() {
 	if c.Cache.CleanupInterval == 0 {
 		c.Cache.CleanupInterval = time.Hour
 	}
+	if c.Cache.PersistInterval == 0 {
+		c.Cache.PersistInterval = 5 * time.Minute
+	}
 	if c.Scheduler.IndexRefresh == 0 {
 		c.Scheduler.IndexRefresh = 30 * time.Minute
 	}
@@ -188,6 +412,37 @@ func (c *Config) normalize() {
 	if c.Scheduler.CacheCleanup == 0 {
 		c.Scheduler.CacheCleanup = time.Hour
 	}
+	if c.Scheduler.IdleWindow == 0 {
+		c.Scheduler.IdleWindow = time.Minute
+	}
+	if c.Scheduler.MaxDeferral == 0 {
+		c.Scheduler.MaxDeferral = 2 * time.Hour
+	}
+	if c.Scheduler.Nice == nil {
+		nice := 10
+		c.Scheduler.Nice = &nice
+	}
+	if c.Scheduler.IONiceClass == "" {
+		c.Scheduler.IONiceClass = "idle"
+	}
+	if c.Scheduler.CatchUp == "" {
+		c.Scheduler.CatchUp = "run"
+	}
+	if c.Watcher.Debounce == 0 {
+		c.Watcher.Debounce = 3 * time.Second
+	}
+	if c.Watcher.MaxWait == 0 {
+		c.Watcher.MaxWait = 30 * time.Second
+	}
+	if c.Tracing.Endpoint == "" {
+		c.Tracing.Endpoint = "127.0.0.1:4317"
+	}
+	if c.Tracing.ServiceName == "" {
+		c.Tracing.ServiceName = "qmdsr"
+	}
+	if c.Tracing.SampleRatio == 0 {
+		c.Tracing.SampleRatio = 1.0
+	}
 	if c.Guardian.CheckInterval == 0 {
 		c.Guardian.CheckInterval = 60 * time.Second
 	}
@@ -197,6 +452,15 @@ func (c *Config) normalize() {
 	if c.Guardian.RestartMaxRetries == 0 {
 		c.Guardian.RestartMaxRetries = 3
 	}
+	if c.SelfHeal.MinInterval == 0 {
+		c.SelfHeal.MinInterval = 10 * time.Minute
+	}
+	if c.SelfHeal.MaxBackoff == 0 {
+		c.SelfHeal.MaxBackoff = 6 * time.Hour
+	}
+	if c.SelfHeal.MaxAttempts == 0 {
+		c.SelfHeal.MaxAttempts = 5
+	}
 	if c.Logging.Level == "" {
 		c.Logging.Level = "info"
 	}
@@ -294,13 +558,105 @@ func (c *Config) validate() error {
 	if _, err := os.Stat(c.QMD.Bin); err != nil {
 		return fmt.Errorf("qmd binary not found at %s: %w", c.QMD.Bin, err)
 	}
+	if nice := c.Scheduler.NiceLevel(); nice < 0 || nice > 19 {
+		return fmt.Errorf("scheduler.nice %d is out of range: background tasks only lower their priority, use 1..19, or 0 to leave it unchanged", nice)
+	}
+	switch c.Scheduler.IONiceClass {
+	case "idle", "best-effort", "none":
+	default:
+		return fmt.Errorf("scheduler.ionice_class %q is not supported", c.Scheduler.IONiceClass)
+	}
+	for _, sc := range []struct{ key, expr string }{
+		{"index_refresh_cron", c.Scheduler.IndexRefreshCron},
+		{"embed_full_refresh_cron", c.Scheduler.EmbedFullRefreshCron},
+	} {
+		key, expr := sc.key, sc.expr
+		if expr == "" {
+			continue
+		}
+		cron, err := schedule.ParseCron(expr)
+		if err != nil {
+			return fmt.Errorf("scheduler.%s: %w", key, err)
+		}
+		if cron.Next(time.Now()).IsZero() {
+			return fmt.Errorf("scheduler.%s %q never fires", key, expr)
+		}
+	}
+	if c.Scheduler.Jitter < 0 {
+		return fmt.Errorf("scheduler.jitter must not be negative")
+	}
+	if _, err := schedule.ParseWindows(c.Scheduler.QuietHours); err != nil {
+		return fmt.Errorf("scheduler.quiet_hours: %w", err)
+	}
+	switch c.Scheduler.CatchUp {
+	case "run", "skip":
+	default:
+		return fmt.Errorf("scheduler.catch_up %q is not supported", c.Scheduler.CatchUp)
+	}
+	switch c.Search.Segmenter {
+	case "dict", "none":
+	default:
+		return fmt.Errorf("search.segmenter %q is not supported", c.Search.Segmenter)
+	}
+	if c.Search.RewriteMinWords < -1 {
+		return fmt.Errorf("search.rewrite_min_words must be positive, or -1 to disable")
+	}
+	if c.Search.RewriteMaxTerms < 0 {
+		return fmt.Errorf("search.rewrite_max_terms must not be negative")
+	}
+	switch c.Search.Aliases {
+	case "frontmatter", "none":
+	default:
+		return fmt.Errorf("search.aliases %q is not supported", c.Search.Aliases)
+	}
+	if c.Search.SynonymMaxVariants < -1 {
+		return fmt.Errorf("search.synonym_max_variants must be positive, or -1 to disable")
+	}
+	if c.Search.SynonymWeight < 0 || c.Search.SynonymWeight > 1 {
+		return fmt.Errorf("search.synonym_weight must be between 0 and 1")
+	}
+	cascaded := make(map[int]bool, len(c.Search.Cascade))
+	for i, p := range c.Search.Cascade {
+		switch {
+		case p.Tier <= 0:
+			return fmt.Errorf("search.cascade[%d]: tier must be positive", i)
+		case cascaded[p.Tier]:
+			return fmt.Errorf("search.cascade[%d]: tier %d is listed twice", i, p.Tier)
+		case p.MinHits < 0:
+			return fmt.Errorf("search.cascade[%d]: min_hits must not be negative", i)
+		case p.MinTopScore < 0:
+			return fmt.Errorf("search.cascade[%d]: min_top_score must not be negative", i)
+		case p.ScoreMultiplier < 0:
+			return fmt.Errorf("search.cascade[%d]: score_multiplier must not be negative", i)
+		}
+		cascaded[p.Tier] = true
+	}
+	switch c.QMD.Reconcile {
+	case "report", "repair", "off":
+	default:
+		return fmt.Errorf("qmd.reconcile %q is not supported", c.QMD.Reconcile)
+	}
+	switch c.Server.SecurityModel {
+	case "loopback_trust":
+	case "token":
+		if c.Server.TokenFile == "" {
+			return fmt.Errorf("server.token_file is required when security_model is token")
+		}
+	default:
+		return fmt.Errorf("server.security_model %q is not supported", c.Server.SecurityModel)
+	}
 	if len(c.Collections) == 0 {
 		return fmt.Errorf("at least one collection is required")
 	}
+	seen := make(map[string]bool, len(c.Collections))
 	for _, col := range c.Collections {
 		if col.Name == "" {
 			return fmt.Errorf("collection name is required")
 		}
+		if seen[col.Name] {
+			return fmt.Errorf("collection %s: duplicate name", col.Name)
+		}
+		seen[col.Name] = true
 		if col.Path == "" {
 			return fmt.Errorf("collection %s: path is required", col.Name)
 		}
@@ -308,6 +664,12 @@ func (c *Config) validate() error {
 			return fmt.Errorf("collection %s: tier is required", col.Name)
 		}
 	}
+	if c.SelfHeal.MinInterval < 0 || c.SelfHeal.MaxBackoff < c.SelfHeal.MinInterval {
+		return fmt.Errorf("self_heal.max_backoff must not be below self_heal.min_interval")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -321,6 +683,15 @@ func expandPath(p string) string {
 	return filepath.Clean(p)
 }
 
+// defaultStateDir follows systemd's StateDirectory= when set, which may list
+// several colon-separated directories.
+func defaultStateDir() string {
+	if dir, _, _ := strings.Cut(os.Getenv("STATE_DIRECTORY"), ":"); dir != "" {
+		return dir
+	}
+	return "/var/lib/qmdsr"
+}
+
 func expandClean(p string) string {
 	if p == "" {
 		return ""
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad_SchedulerNice(t *testing.T) {
+	cases := []struct {
+		body string
+		want int
+	}{
+		{"", 10},
+		{"scheduler:\n  nice: 0\n", 0},
+		{"scheduler:\n  nice: 15\n", 15},
+	}
+	for _, tc := range cases {
+		cfg, err := Load(writeTestConfig(t, t.TempDir(), reloadBase+tc.body))
+		if err != nil {
+			t.Fatalf("Load(%q): %v", tc.body, err)
+		}
+		if got := cfg.Scheduler.NiceLevel(); got != tc.want {
+			t.Errorf("nice for %q = %d, want %d", tc.body, got, tc.want)
+		}
+	}
+
+	for _, nice := range []string{"-1", "20"} {
+		_, err := Load(writeTestConfig(t, t.TempDir(), reloadBase+"scheduler:\n  nice: "+nice+"\n"))
+		if err == nil || !strings.Contains(err.Error(), "scheduler.nice "+nice+" is out of range") {
+			t.Fatalf("expected nice %s to be rejected, got %v", nice, err)
+		}
+	}
+}
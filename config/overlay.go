@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay records collection changes made at runtime so they survive restarts
+// without rewriting the operator's config file.
+type Overlay struct {
+	// Collections replace entries of the same name or are appended.
+	Collections []CollectionCfg `yaml:"collections,omitempty"`
+	// Removed hides collections defined in the base config.
+	Removed []string `yaml:"removed,omitempty"`
+}
+
+// LoadOverlay reads the overlay at path. A missing file is an empty overlay.
+func LoadOverlay(path string) (*Overlay, error) {
+	var o Overlay
+	if path == "" {
+		return &o, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &o, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read collections overlay %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("parse collections overlay %s: %w", path, err)
+	}
+	for i := range o.Collections {
+		o.Collections[i].Path = expandClean(o.Collections[i].Path)
+	}
+	return &o, nil
+}
+
+// Save atomically replaces the overlay file at path.
+func (o *Overlay) Save(path string) error {
+	data, err := yaml.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("encode collections overlay: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create overlay dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create overlay temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write collections overlay: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write collections overlay: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace collections overlay: %w", err)
+	}
+	return nil
+}
+
+// Apply merges the overlay over base and returns the resulting collections.
+func (o *Overlay) Apply(base []CollectionCfg) []CollectionCfg {
+	out := make([]CollectionCfg, 0, len(base)+len(o.Collections))
+	for _, col := range base {
+		if !slices.Contains(o.Removed, col.Name) {
+			out = append(out, col)
+		}
+	}
+	for _, col := range o.Collections {
+		if i := slices.IndexFunc(out, func(c CollectionCfg) bool { return c.Name == col.Name }); i >= 0 {
+			out[i] = col
+			continue
+		}
+		out = append(out, col)
+	}
+	return out
+}
+
+// Put adds or replaces col.
+func (o *Overlay) Put(col CollectionCfg) {
+	o.Removed = slices.DeleteFunc(o.Removed, func(name string) bool { return name == col.Name })
+	if i := slices.IndexFunc(o.Collections, func(c CollectionCfg) bool { return c.Name == col.Name }); i >= 0 {
+		o.Collections[i] = col
+		return
+	}
+	o.Collections = append(o.Collections, col)
+}
+
+// Remove drops name, hiding it from the base config as well.
+func (o *Overlay) Remove(name string) {
+	o.Collections = slices.DeleteFunc(o.Collections, func(c CollectionCfg) bool { return c.Name == name })
+	if !slices.Contains(o.Removed, name) {
+		o.Removed = append(o.Removed, name)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOverlay_PersistsRuntimeCollectionChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestConfig(t, dir, reloadBase)
+	overlayPath := filepath.Join(dir, "collections.yaml")
+
+	ov, err := LoadOverlay(overlayPath)
+	if err != nil {
+		t.Fatalf("LoadOverlay on missing file: %v", err)
+	}
+	ov.Put(CollectionCfg{Name: "vault", Path: "/data/vault", Tier: 1})
+	ov.Put(CollectionCfg{Name: "notes", Path: "/data/notes", Tier: 2, Context: "moved to tier 2"})
+	ov.Remove("archive")
+	if err := ov.Save(overlayPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.CollectionsOverlay != overlayPath {
+		t.Fatalf("expected overlay under state dir, got %s", cfg.Server.CollectionsOverlay)
+	}
+	if len(cfg.Collections) != 2 {
+		t.Fatalf("expected notes and vault, got %+v", cfg.Collections)
+	}
+	if cfg.Collections[0].Name != "notes" || cfg.Collections[0].Tier != 2 {
+		t.Fatalf("expected overlay to replace notes in place, got %+v", cfg.Collections[0])
+	}
+	if cfg.Collections[1].Name != "vault" {
+		t.Fatalf("expected vault appended, got %+v", cfg.Collections[1])
+	}
+
+	ov.Put(CollectionCfg{Name: "archive", Path: "/data/archive2", Tier: 2})
+	if len(ov.Removed) != 0 {
+		t.Fatalf("re-adding a removed collection must clear its tombstone, got %v", ov.Removed)
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ErrRejected marks a reloaded config that failed to load or validate.
+var ErrRejected = errors.New("config rejected")
+
+// Diff describes what changes when a reloaded config replaces the running one.
+type Diff struct {
+	CollectionsAdded   []string
+	CollectionsRemoved []string
+	CollectionsChanged []string
+	// Sections lists the live-applied sections whose values changed.
+	Sections []string
+	// RestartRequired lists keys that differ from the running config but only
+	// take effect after a restart; the reloaded config keeps the running value.
+	RestartRequired []string
+}
+
+func (d Diff) Empty() bool {
+	return !d.CollectionsModified() && len(d.Sections) == 0 && len(d.RestartRequired) == 0
+}
+
+func (d Diff) CollectionsModified() bool {
+	return len(d.CollectionsAdded) > 0 || len(d.CollectionsRemoved) > 0 || len(d.CollectionsChanged) > 0
+}
+
+// SectionChanged reports whether the named live section changed.
+func (d Diff) SectionChanged(name string) bool {
+	for _, s := range d.Sections {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload reads and validates the config at path and diffs it against cur.
+// cur is never modified; on error the running config stays in effect.
+func Reload(path string, cur *Config) (*Config, Diff, error) {
+	next, err := Load(path)
+	if err != nil {
+		return nil, Diff{}, fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+
+	var d Diff
+	d.RestartRequired = pinRestartOnly(cur, next)
+	d.CollectionsAdded, d.CollectionsRemoved, d.CollectionsChanged = diffCollections(cur.Collections, next.Collections)
+
+	sections := []struct {
+		name     string
+		cur, new any
+	}{
+		{"search", cur.Search, next.Search},
+		{"routing", cur.Routing, next.Routing},
+		{"cache", cur.Cache, next.Cache},
+		{"scheduler", cur.Scheduler, next.Scheduler},
+		{"watcher", cur.Watcher, next.Watcher},
+		{"runtime", cur.Runtime, next.Runtime},
+	}
+	for _, s := range sections {
+		if !reflect.DeepEqual(s.cur, s.new) {
+			d.Sections = append(d.Sections, s.name)
+		}
+	}
+	return next, d, nil
+}
+
+// pinRestartOnly copies settings that are bound at startup (listeners, the
+// qmd binary and MCP daemon, executor capabilities, the CPU monitor) from cur
+// into next and returns the keys that differed.
+func pinRestartOnly(cur, next *Config) []string {
+	var keys []string
+	pin(&keys, "qmd", &cur.QMD, &next.QMD)
+	pin(&keys, "server", &cur.Server, &next.Server)
+	pin(&keys, "tracing", &cur.Tracing, &next.Tracing)
+	pin(&keys, "guardian", &cur.Guardian, &next.Guardian)
+	pin(&keys, "self_heal", &cur.SelfHeal, &next.SelfHeal)
+	pin(&keys, "logging", &cur.Logging, &next.Logging)
+	pin(&keys, "watcher.enabled", &cur.Watcher.Enabled, &next.Watcher.Enabled)
+	pin(&keys, "cache.persist", &cur.Cache.Persist, &next.Cache.Persist)
+	pin(&keys, "cache.persist_interval", &cur.Cache.PersistInterval, &next.Cache.PersistInterval)
+	pin(&keys, "runtime.low_resource_mode", &cur.Runtime.LowResourceMode, &next.Runtime.LowResourceMode)
+	pin(&keys, "runtime.allow_cpu_deep_query", &cur.Runtime.AllowCPUDeepQuery, &next.Runtime.AllowCPUDeepQuery)
+	pin(&keys, "runtime.allow_cpu_vsearch", &cur.Runtime.AllowCPUVSearch, &next.Runtime.AllowCPUVSearch)
+	pin(&keys, "runtime.cpu_overload_protect", &cur.Runtime.CPUOverloadProtect, &next.Runtime.CPUOverloadProtect)
+	pin(&keys, "runtime.cpu_overload_threshold", &cur.Runtime.CPUOverloadThreshold, &next.Runtime.CPUOverloadThreshold)
+	pin(&keys, "runtime.cpu_overload_sustain", &cur.Runtime.CPUOverloadSustain, &next.Runtime.CPUOverloadSustain)
+	pin(&keys, "runtime.cpu_recover_threshold", &cur.Runtime.CPURecoverThreshold, &next.Runtime.CPURecoverThreshold)
+	pin(&keys, "runtime.cpu_recover_sustain", &cur.Runtime.CPURecoverSustain, &next.Runtime.CPURecoverSustain)
+	pin(&keys, "runtime.cpu_critical_threshold", &cur.Runtime.CPUCriticalThreshold, &next.Runtime.CPUCriticalThreshold)
+	pin(&keys, "runtime.cpu_critical_sustain", &cur.Runtime.CPUCriticalSustain, &next.Runtime.CPUCriticalSustain)
+	pin(&keys, "runtime.cpu_sample_interval", &cur.Runtime.CPUSampleInterval, &next.Runtime.CPUSampleInterval)
+	return keys
+}
+
+func pin[T comparable](keys *[]string, key string, cur, next *T) {
+	if *cur != *next {
+		*keys = append(*keys, key)
+		*next = *cur
+	}
+}
+
+func diffCollections(cur, next []CollectionCfg) (added, removed, changed []string) {
+	curByName := make(map[string]CollectionCfg, len(cur))
+	for _, col := range cur {
+		curByName[col.Name] = col
+	}
+	nextNames := make(map[string]bool, len(next))
+	for _, col := range next {
+		nextNames[col.Name] = true
+		old, ok := curByName[col.Name]
+		switch {
+		case !ok:
+			added = append(added, col.Name)
+		case !reflect.DeepEqual(old, col):
+			changed = append(changed, col.Name)
+		}
+	}
+	for _, col := range cur {
+		if !nextNames[col.Name] {
+			removed = append(removed, col.Name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, dir, body string) string {
+	t.Helper()
+	t.Setenv("STATE_DIRECTORY", dir)
+	bin := filepath.Join(dir, "qmd")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "qmdsr.yaml")
+	body = "qmd:\n  bin: " + bin + "\n" + body
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const reloadBase = `
+server:
+  grpc_listen: 127.0.0.1:19091
+collections:
+  - {name: notes, path: /data/notes, tier: 1}
+  - {name: archive, path: /data/archive, tier: 2}
+search:
+  min_score: 0.3
+`
+
+func TestReload_DiffsCollectionsAndSections(t *testing.T) {
+	dir := t.TempDir()
+	cur, err := Load(writeTestConfig(t, dir, reloadBase))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	path := writeTestConfig(t, dir, `
+server:
+  grpc_listen: 127.0.0.1:29091
+collections:
+  - {name: notes, path: /data/notes, tier: 1, context: "personal notes"}
+  - {name: memory, path: /data/memory, tier: 1}
+search:
+  min_score: 0.5
+`)
+	next, d, err := Reload(path, cur)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if !slices.Equal(d.CollectionsAdded, []string{"memory"}) ||
+		!slices.Equal(d.CollectionsRemoved, []string{"archive"}) ||
+		!slices.Equal(d.CollectionsChanged, []string{"notes"}) {
+		t.Fatalf("unexpected collection diff: %+v", d)
+	}
+	if !slices.Equal(d.Sections, []string{"search"}) {
+		t.Fatalf("expected only search section changed, got %v", d.Sections)
+	}
+	if !slices.Equal(d.RestartRequired, []string{"server"}) {
+		t.Fatalf("expected server to require restart, got %v", d.RestartRequired)
+	}
+	if next.Server.GRPCListen != "127.0.0.1:19091" {
+		t.Fatalf("restart-only setting must keep running value, got %s", next.Server.GRPCListen)
+	}
+	if next.Search.MinScore != 0.5 {
+		t.Fatalf("expected reloaded min_score, got %v", next.Search.MinScore)
+	}
+}
+
+func TestReload_RejectsInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	cur, err := Load(writeTestConfig(t, dir, reloadBase))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	path := writeTestConfig(t, dir, `
+collections:
+  - {name: notes, path: /data/notes, tier: 1}
+  - {name: notes, path: /data/other, tier: 2}
+`)
+	if _, _, err := Reload(path, cur); !errors.Is(err, ErrRejected) || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate collection error, got %v", err)
+	}
+	if len(cur.Collections) != 2 || cur.Collections[1].Name != "archive" {
+		t.Fatalf("running config must be untouched, got %+v", cur.Collections)
+	}
+}
@@ -7,17 +7,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"qmdsr/config"
+	"qmdsr/metrics"
 	"qmdsr/model"
+	"qmdsr/tracing"
 )
 
 const defaultTimeout = 30 * time.Second
@@ -29,22 +36,23 @@ type CLIExecutor struct {
 	lowResource      bool
 	cpuDeep          bool
 	cpuVSearch       bool
-	queryTimeout     time.Duration
-	queryTokens      chan struct{}
+	queries          *QueryLimiter
+	bgMu             sync.RWMutex
+	bgNice           int
+	bgIOClass        string
 	contextRemoveCmd string
 }
 
 func NewCLI(cfg *config.Config, logger *slog.Logger) (*CLIExecutor, error) {
 	e := &CLIExecutor{
-		bin:          cfg.QMD.Bin,
-		log:          logger,
-		lowResource:  cfg.Runtime.LowResourceMode,
-		cpuDeep:      cfg.Runtime.AllowCPUDeepQuery,
-		cpuVSearch:   cfg.Runtime.AllowCPUVSearch,
-		queryTimeout: cfg.Runtime.QueryTimeout,
-	}
-	if cfg.Runtime.QueryMaxConcurrency > 0 {
-		e.queryTokens = make(chan struct{}, cfg.Runtime.QueryMaxConcurrency)
+		bin:         cfg.QMD.Bin,
+		log:         logger,
+		lowResource: cfg.Runtime.LowResourceMode,
+		cpuDeep:     cfg.Runtime.AllowCPUDeepQuery,
+		cpuVSearch:  cfg.Runtime.AllowCPUVSearch,
+		queries:     NewQueryLimiter(cfg),
+		bgNice:      cfg.Scheduler.NiceLevel(),
+		bgIOClass:   cfg.Scheduler.IONiceClass,
 	}
 	if err := e.probe(context.Background()); err != nil {
 		return nil, err
@@ -52,6 +60,20 @@ func NewCLI(cfg *config.Config, logger *slog.Logger) (*CLIExecutor, error) {
 	return e, nil
 }
 
+// QueryLimiter returns the deep query limiter, for the MCP executor to share.
+func (e *CLIExecutor) QueryLimiter() *QueryLimiter {
+	return e.queries
+}
+
+// ApplyConfig picks up reloaded background priorities. Deep query limits
+// live in the shared QueryLimiter, which is reloaded on its own.
+func (e *CLIExecutor) ApplyConfig(cfg *config.Config) {
+	e.bgMu.Lock()
+	defer e.bgMu.Unlock()
+	e.bgNice = cfg.Scheduler.NiceLevel()
+	e.bgIOClass = cfg.Scheduler.IONiceClass
+}
+
 func (e *CLIExecutor) probe(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -80,6 +102,9 @@ func (e *CLIExecutor) probe(ctx context.Context) error {
 	if _, err := e.run(ctx, "status", "--help"); err == nil {
 		e.caps.Status = true
 	}
+	if out, err := e.run(ctx, "embed", "--help"); err == nil && helpHasFlag(out, "--collection") {
+		e.caps.CollectionEmbed = true
+	}
 	e.contextRemoveCmd = e.probeContextRemoveSubcommand(ctx)
 	if e.contextRemoveCmd != "" {
 		e.log.Info("detected qmd context remove subcommand", "subcommand", e.contextRemoveCmd)
@@ -118,6 +143,7 @@ func (e *CLIExecutor) probe(ctx context.Context) error {
 		"deep_query", e.caps.DeepQuery,
 		"mcp", e.caps.MCP,
 		"status", e.caps.Status,
+		"collection_embed", e.caps.CollectionEmbed,
 	)
 	return nil
 }
@@ -132,6 +158,8 @@ func (e *CLIExecutor) HasCapability(cap string) bool {
 		return e.caps.MCP
 	case "status":
 		return e.caps.Status
+	case "collection_embed":
+		return e.caps.CollectionEmbed
 	default:
 		return false
 	}
@@ -157,20 +185,18 @@ func (e *CLIExecutor) Query(ctx context.Context, query string, opts SearchOpts)
 		return nil, fmt.Errorf("query not available")
 	}
 
-	timeout := e.queryTimeout
-	if timeout <= 0 {
-		timeout = 120 * time.Second
-	}
+	timeout := e.queries.Timeout()
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
-	if err := e.acquireQuerySlot(ctx); err != nil {
+	tokens, err := e.queries.acquire(ctx)
+	if err != nil {
 		return nil, err
 	}
-	defer e.releaseQuerySlot()
+	defer releaseQuerySlot(tokens)
 
 	args := []string{"query", query, "--json"}
 	args = appendSearchArgs(args, opts)
@@ -219,6 +245,21 @@ func (e *CLIExecutor) CollectionAdd(ctx context.Context, path, name, mask string
 	return err
 }
 
+// CollectionRemove unregisters a collection and drops its documents from the
+// index. Older qmd builds only know the "rm" alias.
+func (e *CLIExecutor) CollectionRemove(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	_, err := e.run(ctx, "collection", "remove", name)
+	if err == nil {
+		return nil
+	}
+	if _, rmErr := e.run(ctx, "collection", "rm", name); rmErr == nil {
+		return nil
+	}
+	return err
+}
+
 func (e *CLIExecutor) CollectionList(ctx context.Context) ([]model.CollectionInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
@@ -259,6 +300,36 @@ func (e *CLIExecutor) Embed(ctx context.Context, force bool) error {
 	return err
 }
 
+// EmbedCollection embeds a single collection. qmd builds without
+// --collection support get an error so callers can fall back to a global
+// embed.
+func (e *CLIExecutor) EmbedCollection(ctx context.Context, name string, force bool) error {
+	if !e.caps.CollectionEmbed {
+		return fmt.Errorf("per-collection embed not available")
+	}
+	args := []string{"embed", "--collection", name}
+	if force {
+		args = append(args, "-f")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+	_, err := e.run(ctx, args...)
+	return err
+}
+
+// helpHasFlag reports whether --help output documents flag itself, not just
+// a longer flag starting with it.
+func helpHasFlag(help, flag string) bool {
+	for _, field := range strings.FieldsFunc(help, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",=[]<>|", r)
+	}) {
+		if field == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *CLIExecutor) ContextAdd(ctx context.Context, path, description string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
@@ -391,7 +462,12 @@ func (e *CLIExecutor) Version(ctx context.Context) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
-func (e *CLIExecutor) run(ctx context.Context, args ...string) (string, error) {
+func (e *CLIExecutor) run(ctx context.Context, args ...string) (out string, err error) {
+	ctx, span := tracing.Start(ctx, "qmd "+args[0],
+		attribute.StringSlice("process.command_args", append([]string{e.bin}, args...)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	cmd := exec.Command(e.bin, args...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	if e.shouldDisableVulkan(args) {
@@ -404,19 +480,31 @@ func (e *CLIExecutor) run(ctx context.Context, args ...string) (string, error) {
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if w := stderrFromContext(ctx); w != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, w)
+	}
 
 	e.log.Debug("exec qmd", "args", args)
 	if err := cmd.Start(); err != nil {
 		return stdout.String(), fmt.Errorf("qmd %s: %w", strings.Join(args, " "), err)
 	}
+	inFlight := metrics.QMDInFlight.WithLabelValues(args[0])
+	inFlight.Inc()
+	defer inFlight.Dec()
 
 	done := make(chan error, 1)
 	go func() {
 		done <- cmd.Wait()
 	}()
 
+	span.SetAttributes(attribute.Int("process.pid", cmd.Process.Pid))
+	if isBackgroundCommand(args[0]) {
+		e.lowerPriority(cmd.Process.Pid)
+	}
+
 	select {
 	case err := <-done:
+		span.SetAttributes(attribute.Int("process.exit.code", cmd.ProcessState.ExitCode()))
 		if err != nil {
 			e.log.Debug("exec qmd failed", "args", args, "stderr", stderr.String(), "err", err)
 			return stdout.String(), fmt.Errorf("qmd %s: %w: %s", strings.Join(args, " "), err, stderr.String())
@@ -424,8 +512,10 @@ func (e *CLIExecutor) run(ctx context.Context, args ...string) (string, error) {
 		return stdout.String(), nil
 	case <-ctx.Done():
 		e.killProcessGroup(cmd.Process)
+		span.SetAttributes(attribute.Bool("qmdsr.killed", true))
 		select {
 		case <-done:
+			span.SetAttributes(attribute.Int("process.exit.code", cmd.ProcessState.ExitCode()))
 		case <-time.After(2 * time.Second):
 			e.log.Warn("qmd process did not exit promptly after timeout kill", "args", args)
 		}
@@ -440,6 +530,23 @@ func (e *CLIExecutor) run(ctx context.Context, args ...string) (string, error) {
 	}
 }
 
+// isBackgroundCommand reports whether a qmd subcommand is an index
+// maintenance job that should yield to interactive searches.
+func isBackgroundCommand(name string) bool {
+	return name == "update" || name == "embed"
+}
+
+func (e *CLIExecutor) lowerPriority(pid int) {
+	e.bgMu.RLock()
+	nice, ioClass := e.bgNice, e.bgIOClass
+	e.bgMu.RUnlock()
+	// Setpgid makes the qmd process its own group leader, so its pid is the
+	// group id and children it spawns later inherit the priority.
+	if err := lowerPriority(pid, nice, ioClass); err != nil {
+		e.log.Warn("failed to lower qmd background priority", "pid", pid, "nice", nice, "ionice_class", ioClass, "err", err)
+	}
+}
+
 func (e *CLIExecutor) killProcessGroup(proc *os.Process) {
 	if proc == nil {
 		return
@@ -600,25 +707,3 @@ func appendSearchArgs(args []string, opts SearchOpts) []string {
 	}
 	return args
 }
-
-func (e *CLIExecutor) acquireQuerySlot(ctx context.Context) error {
-	if e.queryTokens == nil {
-		return nil
-	}
-	select {
-	case e.queryTokens <- struct{}{}:
-		return nil
-	case <-ctx.Done():
-		return fmt.Errorf("query queue busy: %w", ctx.Err())
-	}
-}
-
-func (e *CLIExecutor) releaseQuerySlot() {
-	if e.queryTokens == nil {
-		return
-	}
-	select {
-	case <-e.queryTokens:
-	default:
-	}
-}
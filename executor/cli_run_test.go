@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestCLIRun_CopiesStderrAndKillsOnCancel(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "qmd")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\necho progress >&2\nsleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	e := &CLIExecutor{bin: bin, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	var stderr lockedBuffer
+	ctx, cancel := context.WithCancel(WithStderr(context.Background(), &stderr))
+	go func() {
+		for !strings.Contains(stderr.String(), "progress") {
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := e.run(ctx, "search", "plan")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("process group was not killed, run took %v", elapsed)
+	}
+}
+
+// fakeQMD writes a qmd stand-in that logs its arguments and prints
+// embedHelp for "embed --help".
+func fakeQMD(t *testing.T, embedHelp string) (bin, argsLog string) {
+	t.Helper()
+	dir := t.TempDir()
+	bin = filepath.Join(dir, "qmd")
+	argsLog = filepath.Join(dir, "args.log")
+	script := "#!/bin/sh\necho \"$@\" >> " + argsLog + "\n" +
+		"if [ \"$1 $2\" = \"embed --help\" ]; then\ncat <<'HELP'\n" + embedHelp + "\nHELP\nfi\n"
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return bin, argsLog
+}
+
+func TestProbe_DetectsCollectionEmbedByItsLongFlag(t *testing.T) {
+	cases := []struct {
+		help string
+		want bool
+	}{
+		{"Usage: qmd embed [options]\n  --collection <name>  embed one collection\n  -f, --force", true},
+		{"Usage: qmd embed [options]\n  -c, --collection=<name>", true},
+		{"Usage: qmd embed [options]\n  -c, --chunk-size <n>\n  --collections-file <path>", false},
+	}
+	for _, tc := range cases {
+		bin, argsLog := fakeQMD(t, tc.help)
+		e := &CLIExecutor{bin: bin, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+		if err := e.probe(context.Background()); err != nil {
+			t.Fatalf("probe: %v", err)
+		}
+		if e.caps.CollectionEmbed != tc.want {
+			t.Errorf("CollectionEmbed = %t for help %q, want %t", e.caps.CollectionEmbed, tc.help, tc.want)
+			continue
+		}
+		if !tc.want {
+			continue
+		}
+		if err := e.EmbedCollection(context.Background(), "notes", true); err != nil {
+			t.Fatalf("EmbedCollection: %v", err)
+		}
+		data, err := os.ReadFile(argsLog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "embed --collection notes -f\n") {
+			t.Errorf("EmbedCollection ran %q, want embed --collection notes -f", data)
+		}
+	}
+}
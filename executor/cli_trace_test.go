@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCLIRun_RecordsProcessSpan(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	bin := filepath.Join(t.TempDir(), "qmd")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\necho boom >&2\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	e := &CLIExecutor{bin: bin, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	if _, err := e.run(context.Background(), "search", "plan", "-c", "notes"); err == nil {
+		t.Fatalf("expected exit error")
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, a := range spans[0].Attributes {
+		attrs[a.Key] = a.Value
+	}
+	if got := attrs["process.exit.code"].AsInt64(); got != 3 {
+		t.Fatalf("expected exit code 3, got %d", got)
+	}
+	argv := attrs["process.command_args"].AsStringSlice()
+	if len(argv) != 5 || argv[0] != bin || argv[1] != "search" || argv[4] != "notes" {
+		t.Fatalf("unexpected argv %v", argv)
+	}
+	if spans[0].Status.Code.String() != "Error" {
+		t.Fatalf("expected error status, got %v", spans[0].Status)
+	}
+}
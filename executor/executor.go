@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"io"
 
 	"qmdsr/model"
 )
@@ -29,9 +30,11 @@ type Executor interface {
 	MultiGet(ctx context.Context, pattern string, maxBytes int) ([]model.Document, error)
 
 	CollectionAdd(ctx context.Context, path, name, mask string) error
+	CollectionRemove(ctx context.Context, name string) error
 	CollectionList(ctx context.Context) ([]model.CollectionInfo, error)
 	Update(ctx context.Context) error
 	Embed(ctx context.Context, force bool) error
+	EmbedCollection(ctx context.Context, name string, force bool) error
 
 	ContextAdd(ctx context.Context, path, description string) error
 	ContextList(ctx context.Context) ([]model.PathContext, error)
@@ -52,4 +55,19 @@ type Capabilities struct {
 	DeepQuery bool
 	MCP       bool
 	Status    bool
+	// CollectionEmbed: qmd embed accepts --collection <collection>.
+	CollectionEmbed bool
+}
+
+type stderrKey struct{}
+
+// WithStderr returns a context under which CLI commands also copy their
+// stderr to w, so callers can follow a long-running qmd command.
+func WithStderr(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, stderrKey{}, w)
+}
+
+func stderrFromContext(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(stderrKey{}).(io.Writer)
+	return w
 }
@@ -0,0 +1,505 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/internal/version"
+	"qmdsr/model"
+)
+
+const mcpProtocolVersion = "2025-03-26"
+
+var errMCPUnsupported = errors.New("operation not supported over mcp")
+
+// errMCPSessionExpired is returned when the daemon no longer knows our session
+// (typically after a restart by the guardian); callers re-initialize once.
+var errMCPSessionExpired = errors.New("mcp session expired")
+
+// MCPExecutor serves search and document reads through the long-running
+// `qmd mcp --http` daemon so that models stay loaded between requests.
+// Index maintenance and collection management are not exposed over MCP and
+// must go through the CLI executor.
+type MCPExecutor struct {
+	endpoint string
+	client   *http.Client
+	log      *slog.Logger
+	queries  *QueryLimiter
+
+	mu        sync.Mutex
+	sessionID string
+	ready     bool
+	nextID    atomic.Int64
+}
+
+// NewMCP creates an executor for the MCP daemon. queries is shared with the
+// CLI executor so deep queries that fall back to the CLI stay within one
+// concurrency bound.
+func NewMCP(cfg *config.Config, queries *QueryLimiter, logger *slog.Logger) *MCPExecutor {
+	endpoint := fmt.Sprintf("http://127.0.0.1:%d%s", cfg.QMD.MCPPort, cfg.QMD.MCPPath)
+	return newMCPWithEndpoint(endpoint, queries, logger)
+}
+
+func newMCPWithEndpoint(endpoint string, queries *QueryLimiter, logger *slog.Logger) *MCPExecutor {
+	return &MCPExecutor{
+		endpoint: endpoint,
+		client:   &http.Client{},
+		log:      logger,
+		queries:  queries,
+	}
+}
+
+type mcpRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *mcpError       `json:"error"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mcpToolResult struct {
+	Content           []mcpContent    `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent"`
+	IsError           bool            `json:"isError"`
+}
+
+type mcpContent struct {
+	Type     string       `json:"type"`
+	Text     string       `json:"text"`
+	Resource *mcpResource `json:"resource"`
+}
+
+type mcpResource struct {
+	URI      string `json:"uri"`
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+func (e *MCPExecutor) Search(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return e.searchTool(ctx, "search", query, opts)
+}
+
+func (e *MCPExecutor) VSearch(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return e.searchTool(ctx, "vsearch", query, opts)
+}
+
+func (e *MCPExecutor) Query(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.queries.Timeout())
+		defer cancel()
+	}
+
+	tokens, err := e.queries.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseQuerySlot(tokens)
+
+	return e.searchTool(ctx, "query", query, opts)
+}
+
+func (e *MCPExecutor) searchTool(ctx context.Context, tool, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	if opts.All {
+		return nil, fmt.Errorf("mcp %s: --all: %w", tool, errMCPUnsupported)
+	}
+	args := map[string]any{"query": query}
+	if opts.N > 0 {
+		args["limit"] = opts.N
+	}
+	if opts.MinScore > 0 {
+		args["minScore"] = opts.MinScore
+	}
+	if opts.Collection != "" {
+		args["collection"] = opts.Collection
+	}
+
+	res, err := e.callTool(ctx, tool, args)
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseMCPSearchResult(res)
+	if err != nil {
+		return nil, fmt.Errorf("mcp %s: %w", tool, err)
+	}
+	if opts.FilesOnly {
+		results = filesOnlyResults(results)
+	}
+	return results, nil
+}
+
+func (e *MCPExecutor) Get(ctx context.Context, docRef string, opts GetOpts) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	args := map[string]any{"file": docRef}
+	if opts.LineNumbers {
+		args["lineNumbers"] = true
+	}
+	res, err := e.callTool(ctx, "get", args)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, c := range res.Content {
+		switch {
+		case c.Resource != nil:
+			b.WriteString(c.Resource.Text)
+		case c.Type == "text":
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String(), nil
+}
+
+func (e *MCPExecutor) MultiGet(ctx context.Context, pattern string, maxBytes int) ([]model.Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	args := map[string]any{"pattern": pattern}
+	if maxBytes > 0 {
+		args["maxBytes"] = maxBytes
+	}
+	res, err := e.callTool(ctx, "multi_get", args)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]model.Document, 0, len(res.Content))
+	for _, c := range res.Content {
+		// Plain text items carry skip notices ("file too large", ...), not documents.
+		if c.Resource == nil {
+			continue
+		}
+		file := c.Resource.URI
+		if file == "" {
+			file = c.Resource.Name
+		}
+		docs = append(docs, model.Document{File: file, Content: c.Resource.Text})
+	}
+	return docs, nil
+}
+
+func (e *MCPExecutor) Status(ctx context.Context) (*model.IndexStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	res, err := e.callTool(ctx, "status", map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	raw := string(res.StructuredContent)
+	if strings.TrimSpace(raw) == "" || raw == "null" {
+		raw = firstTextContent(res)
+	}
+	status, err := parseStatusJSON(raw)
+	if err != nil {
+		return &model.IndexStatus{Raw: raw}, fmt.Errorf("mcp status: %w", err)
+	}
+	status.Raw = raw
+	return status, nil
+}
+
+func (e *MCPExecutor) MCPHealth(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := e.ensureSession(ctx)
+	return err
+}
+
+func (e *MCPExecutor) CollectionAdd(context.Context, string, string, string) error {
+	return errMCPUnsupported
+}
+
+func (e *MCPExecutor) CollectionRemove(context.Context, string) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) CollectionList(context.Context) ([]model.CollectionInfo, error) {
+	return nil, errMCPUnsupported
+}
+
+func (e *MCPExecutor) Update(context.Context) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) Embed(context.Context, bool) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) EmbedCollection(context.Context, string, bool) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) ContextAdd(context.Context, string, string) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) ContextList(context.Context) ([]model.PathContext, error) {
+	return nil, errMCPUnsupported
+}
+
+func (e *MCPExecutor) ContextRemove(context.Context, string) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) MCPStart(context.Context) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) MCPStop(context.Context) error { return errMCPUnsupported }
+
+func (e *MCPExecutor) Version(context.Context) (string, error) { return "", errMCPUnsupported }
+
+func (e *MCPExecutor) HasCapability(cap string) bool {
+	switch cap {
+	case "vector", "deep_query", "mcp", "status":
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *MCPExecutor) callTool(ctx context.Context, name string, args map[string]any) (*mcpToolResult, error) {
+	params := map[string]any{"name": name, "arguments": args}
+
+	raw, err := e.call(ctx, "tools/call", params)
+	if errors.Is(err, errMCPSessionExpired) {
+		e.log.Info("mcp session expired, re-initializing")
+		raw, err = e.call(ctx, "tools/call", params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mcp %s: %w", name, err)
+	}
+
+	var res mcpToolResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("mcp %s: parse tool result: %w", name, err)
+	}
+	if res.IsError {
+		return nil, fmt.Errorf("mcp %s: %s", name, firstTextContent(&res))
+	}
+	return &res, nil
+}
+
+func (e *MCPExecutor) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	session, err := e.ensureSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := e.nextID.Add(1)
+	raw, _, err := e.post(ctx, session, mcpRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if errors.Is(err, errMCPSessionExpired) {
+		e.resetSession(session)
+	}
+	return raw, err
+}
+
+func (e *MCPExecutor) ensureSession(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ready {
+		return e.sessionID, nil
+	}
+
+	id := e.nextID.Add(1)
+	_, session, err := e.post(ctx, "", mcpRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  "initialize",
+		Params: map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "qmdsr", "version": version.Version},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("mcp initialize: %w", err)
+	}
+	if _, _, err := e.post(ctx, session, mcpRequest{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		return "", fmt.Errorf("mcp initialized notification: %w", err)
+	}
+
+	e.sessionID = session
+	e.ready = true
+	e.log.Debug("mcp session established", "endpoint", e.endpoint, "session", session)
+	return session, nil
+}
+
+func (e *MCPExecutor) resetSession(session string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.sessionID == session {
+		e.ready = false
+		e.sessionID = ""
+	}
+}
+
+// post sends one JSON-RPC message and returns the result payload for requests
+// (nil for notifications) along with the session id announced by the server.
+func (e *MCPExecutor) post(ctx context.Context, session string, msg mcpRequest) (json.RawMessage, string, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if msg.Method != "initialize" {
+		req.Header.Set("MCP-Protocol-Version", mcpProtocolVersion)
+	}
+	if session != "" {
+		req.Header.Set("Mcp-Session-Id", session)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if newSession := resp.Header.Get("Mcp-Session-Id"); newSession != "" {
+		session = newSession
+	}
+
+	if resp.StatusCode == http.StatusNotFound && msg.Method != "initialize" {
+		io.Copy(io.Discard, resp.Body)
+		return nil, session, errMCPSessionExpired
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, session, fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	if msg.ID == 0 {
+		io.Copy(io.Discard, resp.Body)
+		return nil, session, nil
+	}
+
+	var rpc *mcpResponse
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		rpc, err = readMCPEventStream(resp.Body, msg.ID)
+	} else {
+		rpc = &mcpResponse{}
+		err = json.NewDecoder(resp.Body).Decode(rpc)
+	}
+	if err != nil {
+		return nil, session, fmt.Errorf("decode %s response: %w", msg.Method, err)
+	}
+	if rpc.Error != nil {
+		return nil, session, fmt.Errorf("%s: rpc error %d: %s", msg.Method, rpc.Error.Code, rpc.Error.Message)
+	}
+	return rpc.Result, session, nil
+}
+
+// readMCPEventStream scans SSE frames until the JSON-RPC response with the
+// given id shows up; progress notifications and other messages are skipped.
+func readMCPEventStream(r io.Reader, id int64) (*mcpResponse, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	wantID := strconv.FormatInt(id, 10)
+	var data strings.Builder
+	flush := func() (*mcpResponse, bool) {
+		defer data.Reset()
+		if data.Len() == 0 {
+			return nil, false
+		}
+		var msg mcpResponse
+		if err := json.Unmarshal([]byte(data.String()), &msg); err != nil {
+			return nil, false
+		}
+		if strings.Trim(string(msg.ID), `"`) != wantID {
+			return nil, false
+		}
+		return &msg, true
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if msg, ok := flush(); ok {
+				return msg, nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "data:") {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if msg, ok := flush(); ok {
+		return msg, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("event stream closed without response")
+}
+
+func parseMCPSearchResult(res *mcpToolResult) ([]model.SearchResult, error) {
+	if len(res.StructuredContent) > 0 && string(res.StructuredContent) != "null" {
+		var structured struct {
+			Results []model.SearchResult `json:"results"`
+		}
+		if err := json.Unmarshal(res.StructuredContent, &structured); err == nil && structured.Results != nil {
+			return fillCollections(structured.Results), nil
+		}
+	}
+	results, err := parseSearchOutput(firstTextContent(res))
+	if err != nil {
+		return nil, err
+	}
+	return fillCollections(results), nil
+}
+
+func fillCollections(results []model.SearchResult) []model.SearchResult {
+	for i := range results {
+		if results[i].Collection == "" {
+			results[i].Collection = extractCollectionFromURI(results[i].File)
+		}
+	}
+	return results
+}
+
+// filesOnlyResults mirrors `qmd search --files`: one row per file, no snippet.
+func filesOnlyResults(results []model.SearchResult) []model.SearchResult {
+	seen := make(map[string]struct{}, len(results))
+	out := make([]model.SearchResult, 0, len(results))
+	for _, r := range results {
+		if _, ok := seen[r.File]; ok {
+			continue
+		}
+		seen[r.File] = struct{}{}
+		r.Snippet = ""
+		out = append(out, r)
+	}
+	return out
+}
+
+func firstTextContent(res *mcpToolResult) string {
+	for _, c := range res.Content {
+		if c.Type == "text" {
+			return c.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,340 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/model"
+)
+
+// fakeMCPServer is a minimal stand-in for `qmd mcp --http` that speaks the
+// streamable HTTP transport: initialize, notifications/initialized, tools/call.
+type fakeMCPServer struct {
+	mu         sync.Mutex
+	sse        bool
+	sessions   map[string]bool
+	nextSess   int
+	initCalls  int
+	toolCalls  []string
+	toolArgs   []map[string]any
+	toolResult func(name string, args map[string]any) any
+}
+
+func newFakeMCPServer(t *testing.T) (*fakeMCPServer, *httptest.Server) {
+	t.Helper()
+	f := &fakeMCPServer{sessions: make(map[string]bool)}
+	srv := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeMCPServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int64  `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Method == "initialize" {
+		f.initCalls++
+		f.nextSess++
+		sess := fmt.Sprintf("sess-%d", f.nextSess)
+		f.sessions[sess] = true
+		w.Header().Set("Mcp-Session-Id", sess)
+		f.reply(w, req.ID, map[string]any{"protocolVersion": mcpProtocolVersion})
+		return
+	}
+	if !f.sessions[r.Header.Get("Mcp-Session-Id")] {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+	if req.Method == "notifications/initialized" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	f.toolCalls = append(f.toolCalls, req.Params.Name)
+	f.toolArgs = append(f.toolArgs, req.Params.Arguments)
+	f.reply(w, req.ID, f.toolResult(req.Params.Name, req.Params.Arguments))
+}
+
+func (f *fakeMCPServer) reply(w http.ResponseWriter, id int64, result any) {
+	payload, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "result": result})
+	if f.sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", payload)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload)
+}
+
+func (f *fakeMCPServer) expireSessions() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = make(map[string]bool)
+}
+
+func searchToolResult(string, map[string]any) any {
+	return map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": "Found 1 result"}},
+		"structuredContent": map[string]any{
+			"results": []any{map[string]any{
+				"docid":   "#abc123",
+				"file":    "qmd://claw-memory/gtd.md",
+				"title":   "GTD",
+				"score":   0.82,
+				"snippet": "weekly review",
+			}},
+		},
+	}
+}
+
+func testMCPLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestMCP(endpoint string) *MCPExecutor {
+	return newMCPWithEndpoint(endpoint, nil, testMCPLogger())
+}
+
+func TestMCPExecutor_SearchUsesToolCall(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = searchToolResult
+	e := newTestMCP(srv.URL)
+
+	results, err := e.Search(context.Background(), "weekly review", SearchOpts{Collection: "claw-memory", N: 5, MinScore: 0.3})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].DocID != "#abc123" || results[0].Collection != "claw-memory" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(fake.toolCalls) != 1 || fake.toolCalls[0] != "search" {
+		t.Fatalf("unexpected tool calls: %v", fake.toolCalls)
+	}
+	args := fake.toolArgs[0]
+	if args["collection"] != "claw-memory" || args["limit"] != float64(5) || args["minScore"] != 0.3 {
+		t.Fatalf("unexpected tool args: %v", args)
+	}
+}
+
+func TestMCPExecutor_ParsesEventStreamResponses(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.sse = true
+	fake.toolResult = searchToolResult
+	e := newTestMCP(srv.URL)
+
+	results, err := e.VSearch(context.Background(), "weekly review", SearchOpts{})
+	if err != nil {
+		t.Fatalf("VSearch failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "GTD" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestMCPExecutor_ReinitializesExpiredSession(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = searchToolResult
+	e := newTestMCP(srv.URL)
+
+	if _, err := e.Search(context.Background(), "a", SearchOpts{}); err != nil {
+		t.Fatalf("first Search failed: %v", err)
+	}
+	fake.expireSessions()
+	if _, err := e.Search(context.Background(), "b", SearchOpts{}); err != nil {
+		t.Fatalf("Search after session expiry failed: %v", err)
+	}
+	if fake.initCalls != 2 {
+		t.Fatalf("expected two initialize calls, got %d", fake.initCalls)
+	}
+}
+
+func TestMCPExecutor_GetAndMultiGetReadResources(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = func(name string, _ map[string]any) any {
+		if name == "get" {
+			return map[string]any{"content": []any{map[string]any{
+				"type":     "resource",
+				"resource": map[string]any{"uri": "qmd://claw-memory/gtd.md", "text": "# GTD"},
+			}}}
+		}
+		return map[string]any{"content": []any{
+			map[string]any{"type": "resource", "resource": map[string]any{"uri": "qmd://claw-memory/a.md", "text": "A"}},
+			map[string]any{"type": "text", "text": "skipped b.md: too large"},
+		}}
+	}
+	e := newTestMCP(srv.URL)
+
+	content, err := e.Get(context.Background(), "qmd://claw-memory/gtd.md", GetOpts{Full: true})
+	if err != nil || content != "# GTD" {
+		t.Fatalf("unexpected Get result: content=%q err=%v", content, err)
+	}
+	docs, err := e.MultiGet(context.Background(), "claw-memory/*.md", 0)
+	if err != nil {
+		t.Fatalf("MultiGet failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].File != "qmd://claw-memory/a.md" {
+		t.Fatalf("unexpected MultiGet docs: %+v", docs)
+	}
+}
+
+func TestMCPExecutor_ToolErrorSurfaces(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = func(string, map[string]any) any {
+		return map[string]any{"isError": true, "content": []any{map[string]any{"type": "text", "text": "collection not found"}}}
+	}
+	e := newTestMCP(srv.URL)
+
+	if _, err := e.Search(context.Background(), "x", SearchOpts{}); err == nil {
+		t.Fatalf("expected tool error")
+	}
+}
+
+type fakeCLI struct {
+	Executor
+	caps        map[string]bool
+	searchCalls int
+	queryCalls  int
+}
+
+func (f *fakeCLI) Search(context.Context, string, SearchOpts) ([]model.SearchResult, error) {
+	f.searchCalls++
+	return []model.SearchResult{{File: "cli.md"}}, nil
+}
+
+func (f *fakeCLI) Query(context.Context, string, SearchOpts) ([]model.SearchResult, error) {
+	f.queryCalls++
+	return []model.SearchResult{{File: "cli.md"}}, nil
+}
+
+func (f *fakeCLI) HasCapability(cap string) bool { return f.caps[cap] }
+
+func TestRoutedExecutor_PrefersMCPUntilCLIMode(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = searchToolResult
+	cli := &fakeCLI{}
+	cliMode := false
+	r := NewRouted(cli, newTestMCP(srv.URL), func() bool { return !cliMode }, testMCPLogger())
+
+	results, err := r.Search(context.Background(), "q", SearchOpts{})
+	if err != nil || len(results) != 1 || results[0].File != "qmd://claw-memory/gtd.md" {
+		t.Fatalf("expected MCP result, got %+v err=%v", results, err)
+	}
+	if cli.searchCalls != 0 {
+		t.Fatalf("did not expect cli search while MCP healthy")
+	}
+
+	cliMode = true
+	results, err = r.Search(context.Background(), "q", SearchOpts{})
+	if err != nil || len(results) != 1 || results[0].File != "cli.md" {
+		t.Fatalf("expected CLI result in cli mode, got %+v err=%v", results, err)
+	}
+}
+
+func TestRoutedExecutor_FallsBackToCLIOnMCPFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cli := &fakeCLI{}
+	r := NewRouted(cli, newTestMCP(srv.URL), func() bool { return true }, testMCPLogger())
+
+	results, err := r.Search(context.Background(), "q", SearchOpts{})
+	if err != nil || len(results) != 1 || results[0].File != "cli.md" {
+		t.Fatalf("expected CLI fallback result, got %+v err=%v", results, err)
+	}
+	if cli.searchCalls != 1 {
+		t.Fatalf("expected one cli search, got %d", cli.searchCalls)
+	}
+}
+
+func TestRoutedExecutor_RespectsCLIQueryCapability(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	fake.toolResult = searchToolResult
+	r := NewRouted(&fakeCLI{}, newTestMCP(srv.URL), func() bool { return true }, testMCPLogger())
+
+	if _, err := r.Query(context.Background(), "q", SearchOpts{}); err == nil {
+		t.Fatalf("expected query to be unavailable when cli capability is off")
+	}
+	if len(fake.toolCalls) != 0 {
+		t.Fatalf("did not expect MCP tool calls, got %v", fake.toolCalls)
+	}
+}
+
+func TestQueryLimiter_SharedBetweenMCPAndCLI(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	entered, release := make(chan struct{}), make(chan struct{})
+	fake.toolResult = func(name string, args map[string]any) any {
+		close(entered)
+		<-release
+		return searchToolResult(name, args)
+	}
+	queries := NewQueryLimiter(&config.Config{Runtime: config.RuntimeConfig{QueryMaxConcurrency: 1}})
+	mcp := newMCPWithEndpoint(srv.URL, queries, testMCPLogger())
+	cli := &CLIExecutor{bin: "qmd-not-installed", caps: Capabilities{DeepQuery: true}, queries: queries, log: testMCPLogger()}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := mcp.Query(context.Background(), "weekly review", SearchOpts{})
+		done <- err
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cli.Query(ctx, "weekly review", SearchOpts{}); err == nil || !strings.Contains(err.Error(), "query queue busy") {
+		t.Fatalf("expected the CLI fallback to wait for the slot the MCP query holds, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("MCP query failed: %v", err)
+	}
+}
+
+func TestMCPExecutor_QueryFollowsReloadedLimits(t *testing.T) {
+	fake, srv := newFakeMCPServer(t)
+	release := make(chan struct{})
+	defer close(release)
+	fake.toolResult = func(name string, args map[string]any) any {
+		<-release
+		return searchToolResult(name, args)
+	}
+	queries := NewQueryLimiter(&config.Config{Runtime: config.RuntimeConfig{QueryTimeout: time.Minute, QueryMaxConcurrency: 4}})
+	e := newMCPWithEndpoint(srv.URL, queries, testMCPLogger())
+
+	queries.ApplyConfig(&config.Config{Runtime: config.RuntimeConfig{QueryTimeout: 50 * time.Millisecond, QueryMaxConcurrency: 1}})
+	start := time.Now()
+	if _, err := e.Query(context.Background(), "weekly review", SearchOpts{}); err == nil {
+		t.Fatalf("expected the query to hit the reloaded timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("query ran for %v, the reloaded timeout was not applied", elapsed)
+	}
+	if got := cap(queries.tokens); got != 1 {
+		t.Fatalf("query slots = %d, want the reloaded 1", got)
+	}
+}
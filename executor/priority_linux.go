@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+const (
+	ioprioWhoPgrp    = 2
+	ioprioClassShift = 13
+	ioprioClassBE    = 2
+	ioprioClassIdle  = 3
+	ioprioLowestBE   = 7
+)
+
+// lowerPriority renices the process group of a background qmd command and
+// moves it to a lower I/O scheduling class.
+func lowerPriority(pgid, nice int, ioClass string) error {
+	var errs []error
+	if nice > 0 {
+		if err := syscall.Setpriority(syscall.PRIO_PGRP, pgid, nice); err != nil {
+			errs = append(errs, fmt.Errorf("setpriority: %w", err))
+		}
+	}
+
+	var prio int
+	switch ioClass {
+	case "idle":
+		prio = ioprioClassIdle << ioprioClassShift
+	case "best-effort":
+		prio = ioprioClassBE<<ioprioClassShift | ioprioLowestBE
+	}
+	if prio != 0 {
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoPgrp, uintptr(pgid), uintptr(prio)); errno != 0 {
+			errs = append(errs, fmt.Errorf("ioprio_set: %w", errno))
+		}
+	}
+	return errors.Join(errs...)
+}
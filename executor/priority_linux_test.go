@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLIRun_LowersBackgroundPriority(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "qmd")
+	script := "#!/bin/sh\nsleep 0.3\ncut -d' ' -f19 /proc/$$/stat\n"
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	e := &CLIExecutor{
+		bin:       bin,
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		bgNice:    10,
+		bgIOClass: "idle",
+	}
+
+	out, err := e.run(context.Background(), "update")
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if got := strings.TrimSpace(out); got != "10" {
+		t.Fatalf("expected update to run at nice 10, got %q", got)
+	}
+
+	out, err = e.run(context.Background(), "search", "plan")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := strings.TrimSpace(out); got == "10" {
+		t.Fatalf("search must keep the default priority, got nice %q", got)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package executor
+
+import "syscall"
+
+// lowerPriority renices the process group of a background qmd command. I/O
+// priority classes are Linux-only.
+func lowerPriority(pgid, nice int, _ string) error {
+	if nice > 0 {
+		return syscall.Setpriority(syscall.PRIO_PGRP, pgid, nice)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/tracing"
+)
+
+const defaultQueryTimeout = 120 * time.Second
+
+// QueryLimiter holds the deep query timeout and bounds how many deep queries
+// run at once. The CLI and MCP executors share one, so a query that falls
+// back from MCP to the CLI still counts against runtime.query_max_concurrency.
+// A nil limiter allows unlimited queries with the default timeout.
+type QueryLimiter struct {
+	mu      sync.RWMutex
+	timeout time.Duration
+	tokens  chan struct{}
+}
+
+func NewQueryLimiter(cfg *config.Config) *QueryLimiter {
+	l := &QueryLimiter{}
+	l.ApplyConfig(cfg)
+	return l
+}
+
+// ApplyConfig picks up a reloaded query timeout and concurrency. Queries in
+// flight keep the slot they hold in the previous queue.
+func (l *QueryLimiter) ApplyConfig(cfg *config.Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeout = cfg.Runtime.QueryTimeout
+	if cfg.Runtime.QueryMaxConcurrency != cap(l.tokens) {
+		l.tokens = newQueryTokens(cfg.Runtime.QueryMaxConcurrency)
+	}
+}
+
+func newQueryTokens(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
+// Timeout returns the configured deep query timeout.
+func (l *QueryLimiter) Timeout() time.Duration {
+	if l == nil {
+		return defaultQueryTimeout
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.timeout <= 0 {
+		return defaultQueryTimeout
+	}
+	return l.timeout
+}
+
+// acquire waits for a query slot and returns the queue to release it to.
+func (l *QueryLimiter) acquire(ctx context.Context) (chan struct{}, error) {
+	if l == nil {
+		return nil, nil
+	}
+	l.mu.RLock()
+	tokens := l.tokens
+	l.mu.RUnlock()
+	if tokens == nil {
+		return nil, nil
+	}
+	_, span := tracing.Start(ctx, "executor.acquire_query_slot")
+	select {
+	case tokens <- struct{}{}:
+		span.End()
+		return tokens, nil
+	case <-ctx.Done():
+		err := fmt.Errorf("query queue busy: %w", ctx.Err())
+		tracing.End(span, err)
+		return nil, err
+	}
+}
+
+func releaseQuerySlot(tokens chan struct{}) {
+	if tokens == nil {
+		return
+	}
+	select {
+	case <-tokens:
+	default:
+	}
+}
@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"qmdsr/model"
+)
+
+// RoutedExecutor sends search and document reads to the MCP daemon while it
+// is healthy and falls back to forking the CLI otherwise. Everything else
+// (index maintenance, collections, contexts, MCP lifecycle) always uses the CLI.
+type RoutedExecutor struct {
+	cli    Executor
+	mcp    Executor
+	useMCP func() bool
+	log    *slog.Logger
+}
+
+func NewRouted(cli, mcp Executor, useMCP func() bool, logger *slog.Logger) *RoutedExecutor {
+	return &RoutedExecutor{
+		cli:    cli,
+		mcp:    mcp,
+		useMCP: useMCP,
+		log:    logger,
+	}
+}
+
+func (r *RoutedExecutor) preferMCP() bool {
+	return r.mcp != nil && (r.useMCP == nil || r.useMCP())
+}
+
+func (r *RoutedExecutor) fallback(ctx context.Context, op string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	r.log.Warn("mcp call failed, falling back to cli", "op", op, "err", err)
+	return true
+}
+
+func (r *RoutedExecutor) Search(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	if r.preferMCP() && !opts.All {
+		results, err := r.mcp.Search(ctx, query, opts)
+		if err == nil || !r.fallback(ctx, "search", err) {
+			return results, err
+		}
+	}
+	return r.cli.Search(ctx, query, opts)
+}
+
+func (r *RoutedExecutor) VSearch(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	if !r.cli.HasCapability("vector") {
+		return nil, fmt.Errorf("vsearch not available")
+	}
+	if r.preferMCP() && !opts.All {
+		results, err := r.mcp.VSearch(ctx, query, opts)
+		if err == nil || !r.fallback(ctx, "vsearch", err) {
+			return results, err
+		}
+	}
+	return r.cli.VSearch(ctx, query, opts)
+}
+
+func (r *RoutedExecutor) Query(ctx context.Context, query string, opts SearchOpts) ([]model.SearchResult, error) {
+	if !r.cli.HasCapability("deep_query") {
+		return nil, fmt.Errorf("query not available")
+	}
+	if r.preferMCP() && !opts.All {
+		results, err := r.mcp.Query(ctx, query, opts)
+		if err == nil || !r.fallback(ctx, "query", err) {
+			return results, err
+		}
+	}
+	return r.cli.Query(ctx, query, opts)
+}
+
+func (r *RoutedExecutor) Get(ctx context.Context, docRef string, opts GetOpts) (string, error) {
+	if r.preferMCP() {
+		content, err := r.mcp.Get(ctx, docRef, opts)
+		if err == nil || !r.fallback(ctx, "get", err) {
+			return content, err
+		}
+	}
+	return r.cli.Get(ctx, docRef, opts)
+}
+
+func (r *RoutedExecutor) MultiGet(ctx context.Context, pattern string, maxBytes int) ([]model.Document, error) {
+	if r.preferMCP() {
+		docs, err := r.mcp.MultiGet(ctx, pattern, maxBytes)
+		if err == nil || !r.fallback(ctx, "multi_get", err) {
+			return docs, err
+		}
+	}
+	return r.cli.MultiGet(ctx, pattern, maxBytes)
+}
+
+func (r *RoutedExecutor) CollectionAdd(ctx context.Context, path, name, mask string) error {
+	return r.cli.CollectionAdd(ctx, path, name, mask)
+}
+
+func (r *RoutedExecutor) CollectionRemove(ctx context.Context, name string) error {
+	return r.cli.CollectionRemove(ctx, name)
+}
+
+func (r *RoutedExecutor) CollectionList(ctx context.Context) ([]model.CollectionInfo, error) {
+	return r.cli.CollectionList(ctx)
+}
+
+func (r *RoutedExecutor) Update(ctx context.Context) error {
+	return r.cli.Update(ctx)
+}
+
+func (r *RoutedExecutor) Embed(ctx context.Context, force bool) error {
+	return r.cli.Embed(ctx, force)
+}
+
+func (r *RoutedExecutor) EmbedCollection(ctx context.Context, name string, force bool) error {
+	return r.cli.EmbedCollection(ctx, name, force)
+}
+
+func (r *RoutedExecutor) ContextAdd(ctx context.Context, path, description string) error {
+	return r.cli.ContextAdd(ctx, path, description)
+}
+
+func (r *RoutedExecutor) ContextList(ctx context.Context) ([]model.PathContext, error) {
+	return r.cli.ContextList(ctx)
+}
+
+func (r *RoutedExecutor) ContextRemove(ctx context.Context, path string) error {
+	return r.cli.ContextRemove(ctx, path)
+}
+
+func (r *RoutedExecutor) Status(ctx context.Context) (*model.IndexStatus, error) {
+	return r.cli.Status(ctx)
+}
+
+func (r *RoutedExecutor) MCPStart(ctx context.Context) error {
+	return r.cli.MCPStart(ctx)
+}
+
+func (r *RoutedExecutor) MCPStop(ctx context.Context) error {
+	return r.cli.MCPStop(ctx)
+}
+
+func (r *RoutedExecutor) MCPHealth(ctx context.Context) error {
+	return r.cli.MCPHealth(ctx)
+}
+
+func (r *RoutedExecutor) Version(ctx context.Context) (string, error) {
+	return r.cli.Version(ctx)
+}
+
+func (r *RoutedExecutor) HasCapability(cap string) bool {
+	return r.cli.HasCapability(cap)
+}
@@ -9,6 +9,7 @@ import (
 
 	"qmdsr/config"
 	"qmdsr/executor"
+	"qmdsr/metrics"
 	"qmdsr/model"
 )
 
@@ -81,7 +82,8 @@ func (g *Guardian) Health() (model.HealthLevel, string) {
 	return g.health, msg
 }
 
-// IsCLIMode is kept for future runtime status exposure (CLI vs MCP mode).
+// IsCLIMode reports whether the MCP daemon is considered unusable, in which
+// case searches must be served by forking the qmd CLI.
 func (g *Guardian) IsCLIMode() bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
@@ -188,5 +190,10 @@ func (g *Guardian) restartMCP(ctx context.Context) error {
 	_ = g.exec.MCPStop(ctx)
 	time.Sleep(1 * time.Second)
 
-	return g.startMCP(ctx)
+	if err := g.startMCP(ctx); err != nil {
+		metrics.GuardianRestarts.WithLabelValues("failed").Inc()
+		return err
+	}
+	metrics.GuardianRestarts.WithLabelValues("ok").Inc()
+	return nil
 }
@@ -3,6 +3,7 @@ package heartbeat
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
@@ -17,6 +18,10 @@ type Heartbeat struct {
 	log      *slog.Logger
 	interval time.Duration
 	cancel   context.CancelFunc
+	rem      remediator
+
+	listenMu  sync.Mutex
+	listeners []func(*model.SystemHealth)
 }
 
 func New(interval time.Duration, logger *slog.Logger) *Heartbeat {
@@ -25,6 +30,10 @@ func New(interval time.Duration, logger *slog.Logger) *Heartbeat {
 		health:   NewSystemHealthTracker(),
 		log:      logger,
 		interval: interval,
+		rem: remediator{
+			byComp: make(map[string][]Remediation),
+			state:  make(map[string]*remediationState),
+		},
 	}
 }
 
@@ -32,6 +41,14 @@ func (h *Heartbeat) Register(name string, checker ComponentChecker) {
 	h.checkers[name] = checker
 }
 
+// OnCheck registers fn to receive the system health after every round of
+// checks.
+func (h *Heartbeat) OnCheck(fn func(*model.SystemHealth)) {
+	h.listenMu.Lock()
+	h.listeners = append(h.listeners, fn)
+	h.listenMu.Unlock()
+}
+
 func (h *Heartbeat) Start(ctx context.Context) {
 	ctx, h.cancel = context.WithCancel(ctx)
 	go h.loop(ctx)
@@ -74,6 +91,15 @@ func (h *Heartbeat) runChecks(ctx context.Context) {
 		if prev != level {
 			h.logTransition(name, prev, level, msg)
 		}
+		h.remediate(ctx, name, prev, level, msg)
+	}
+
+	h.listenMu.Lock()
+	listeners := slices.Clone(h.listeners)
+	h.listenMu.Unlock()
+	health := h.GetHealth()
+	for _, fn := range listeners {
+		fn(health)
 	}
 }
 
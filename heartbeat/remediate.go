@@ -0,0 +1,166 @@
+package heartbeat
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"qmdsr/metrics"
+	"qmdsr/model"
+)
+
+// maxRemediationAudit bounds the remediation attempts Remediations returns.
+const maxRemediationAudit = 50
+
+// Remediation is an action run when a component reaches Level with a
+// message containing Match.
+type Remediation struct {
+	Name      string
+	Component string
+	Level     model.HealthLevel
+	Match     string
+	Action    func(ctx context.Context) error
+}
+
+// RemediationPolicy rate limits remediations. After an attempt the same
+// remediation waits MinInterval; each consecutive failure doubles the wait up
+// to MaxBackoff. After MaxAttempts consecutive failures it stops until the
+// component recovers.
+type RemediationPolicy struct {
+	MinInterval time.Duration
+	MaxBackoff  time.Duration
+	MaxAttempts int
+}
+
+// RemediationAttempt is one audited run of a remediation.
+type RemediationAttempt struct {
+	Remediation string
+	Component   string
+	From        model.HealthLevel
+	To          model.HealthLevel
+	Message     string
+	At          time.Time
+	Duration    time.Duration
+	Err         string
+}
+
+type remediationState struct {
+	running     bool
+	failures    int
+	nextAllowed time.Time
+	gaveUp      bool
+}
+
+type remediator struct {
+	mu      sync.Mutex
+	policy  RemediationPolicy
+	byComp  map[string][]Remediation
+	state   map[string]*remediationState
+	audit   []RemediationAttempt
+	running sync.WaitGroup
+}
+
+// SetRemediationPolicy sets the rate limits applied to every remediation.
+func (h *Heartbeat) SetRemediationPolicy(p RemediationPolicy) {
+	h.rem.mu.Lock()
+	h.rem.policy = p
+	h.rem.mu.Unlock()
+}
+
+// Remediate registers an action for a component health transition.
+func (h *Heartbeat) Remediate(r Remediation) {
+	h.rem.mu.Lock()
+	defer h.rem.mu.Unlock()
+	h.rem.byComp[r.Component] = append(h.rem.byComp[r.Component], r)
+	h.rem.state[r.Name] = &remediationState{}
+}
+
+// Remediations returns recent remediation attempts, newest first.
+func (h *Heartbeat) Remediations() []RemediationAttempt {
+	h.rem.mu.Lock()
+	defer h.rem.mu.Unlock()
+	out := slices.Clone(h.rem.audit)
+	slices.Reverse(out)
+	return out
+}
+
+// remediate starts the remediations matching a component's new health. Each
+// remediation runs at most once at a time, in the background.
+func (h *Heartbeat) remediate(ctx context.Context, name string, from, to model.HealthLevel, msg string) {
+	h.rem.mu.Lock()
+	defer h.rem.mu.Unlock()
+	now := time.Now()
+	for _, r := range h.rem.byComp[name] {
+		st := h.rem.state[r.Name]
+		if to != r.Level || !strings.Contains(msg, r.Match) {
+			if to == model.Healthy {
+				*st = remediationState{running: st.running}
+			}
+			continue
+		}
+		if st.running || now.Before(st.nextAllowed) || st.gaveUp {
+			continue
+		}
+		if limit := h.rem.policy.MaxAttempts; limit > 0 && st.failures >= limit {
+			st.gaveUp = true
+			h.log.Error("remediation gave up until component recovers", "remediation", r.Name, "component", name, "failures", st.failures)
+			continue
+		}
+		st.running = true
+		h.log.Warn("running remediation", "remediation", r.Name, "component", name, "level", to.String(), "message", msg)
+		h.rem.running.Add(1)
+		go h.runRemediation(ctx, r, from, to, msg)
+	}
+}
+
+func (h *Heartbeat) runRemediation(ctx context.Context, r Remediation, from, to model.HealthLevel, msg string) {
+	defer h.rem.running.Done()
+	start := time.Now()
+	err := r.Action(ctx)
+	attempt := RemediationAttempt{
+		Remediation: r.Name,
+		Component:   r.Component,
+		From:        from,
+		To:          to,
+		Message:     msg,
+		At:          start,
+		Duration:    time.Since(start),
+	}
+	result := "ok"
+	if err != nil {
+		attempt.Err = err.Error()
+		result = "failed"
+	}
+	metrics.SelfHealActions.WithLabelValues(r.Name, result).Inc()
+
+	h.rem.mu.Lock()
+	st := h.rem.state[r.Name]
+	st.running = false
+	wait := h.rem.policy.MinInterval
+	if err != nil {
+		st.failures++
+		for range st.failures {
+			wait *= 2
+			if b := h.rem.policy.MaxBackoff; b > 0 && wait >= b {
+				wait = b
+				break
+			}
+		}
+	} else {
+		st.failures = 0
+	}
+	st.nextAllowed = time.Now().Add(wait)
+	h.rem.audit = append(h.rem.audit, attempt)
+	if over := len(h.rem.audit) - maxRemediationAudit; over > 0 {
+		h.rem.audit = slices.Delete(h.rem.audit, 0, over)
+	}
+	h.rem.mu.Unlock()
+
+	if err != nil {
+		h.log.Error("remediation failed", "remediation", r.Name, "component", r.Component, "elapsed", attempt.Duration, "retry_after", wait, "err", err)
+		return
+	}
+	h.log.Info("remediation finished", "remediation", r.Name, "component", r.Component, "elapsed", attempt.Duration)
+}
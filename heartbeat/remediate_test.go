@@ -0,0 +1,170 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/model"
+)
+
+type fakeHealExec struct {
+	executor.Executor
+
+	mu       sync.Mutex
+	vectors  int
+	updates  int
+	embeds   int
+	embedErr error
+	indexDB  string
+}
+
+func (f *fakeHealExec) HasCapability(string) bool { return true }
+
+func (f *fakeHealExec) Status(context.Context) (*model.IndexStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &model.IndexStatus{Vectors: f.vectors}, nil
+}
+
+func (f *fakeHealExec) Update(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates++
+	return os.WriteFile(f.indexDB, []byte("sqlite"), 0o644)
+}
+
+func (f *fakeHealExec) Embed(context.Context, bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.embeds++
+	if f.embedErr != nil {
+		return f.embedErr
+	}
+	f.vectors = 42
+	return nil
+}
+
+func (f *fakeHealExec) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.updates, f.embeds
+}
+
+func newHealTest(t *testing.T, policy RemediationPolicy) (*Heartbeat, *fakeHealExec) {
+	t.Helper()
+	indexDB := filepath.Join(t.TempDir(), "index.sqlite")
+	if err := os.WriteFile(indexDB, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		QMD:         config.QMDConfig{IndexDB: indexDB},
+		Collections: []config.CollectionCfg{{Name: "notes", Path: "/notes", Tier: 1, Embed: true}},
+	}
+	exec := &fakeHealExec{indexDB: indexDB}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	healer := NewSelfHealer(cfg, exec, logger)
+
+	h := New(time.Minute, logger)
+	h.Register("index_db", healer.CheckIndexDB)
+	h.Register("embeddings", healer.CheckEmbeddings)
+	h.SetRemediationPolicy(policy)
+	for _, r := range healer.Remediations(HealActions{
+		Update: exec.Update,
+		Embed:  func(ctx context.Context) error { return exec.Embed(ctx, false) },
+	}) {
+		if r.Action != nil {
+			h.Remediate(r)
+		}
+	}
+	return h, exec
+}
+
+// check runs one heartbeat round and waits for the remediations it started.
+func check(h *Heartbeat) {
+	h.runChecks(context.Background())
+	h.rem.running.Wait()
+}
+
+func TestRemediate_RepairsEmptyIndexAndMissingVectors(t *testing.T) {
+	h, exec := newHealTest(t, RemediationPolicy{MinInterval: time.Hour, MaxBackoff: time.Hour})
+
+	check(h)
+	if updates, embeds := exec.counts(); updates != 1 || embeds != 1 {
+		t.Fatalf("updates=%d embeds=%d, want 1 each", updates, embeds)
+	}
+	audit := h.Remediations()
+	if len(audit) != 2 {
+		t.Fatalf("audit = %+v, want 2 attempts", audit)
+	}
+	for _, a := range audit {
+		if a.Err != "" || a.From != model.Healthy {
+			t.Errorf("unexpected attempt %+v", a)
+		}
+	}
+
+	check(h)
+	health := h.GetHealth()
+	for _, name := range []string{"index_db", "embeddings"} {
+		if lvl := health.Components[name].Level; lvl != model.Healthy {
+			t.Errorf("%s = %s after remediation, want healthy", name, lvl)
+		}
+	}
+	if updates, embeds := exec.counts(); updates != 1 || embeds != 1 {
+		t.Errorf("healthy components remediated again: updates=%d embeds=%d", updates, embeds)
+	}
+}
+
+func TestRemediate_BacksOffAndGivesUp(t *testing.T) {
+	h, exec := newHealTest(t, RemediationPolicy{MinInterval: 20 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, MaxAttempts: 2})
+	exec.embedErr = errors.New("embed failed")
+
+	check(h)
+	check(h)
+	if _, embeds := exec.counts(); embeds != 1 {
+		t.Fatalf("embeds = %d, want 1 while backing off", embeds)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	check(h)
+	if _, embeds := exec.counts(); embeds != 2 {
+		t.Fatalf("embeds = %d, want 2 after backoff", embeds)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	check(h)
+	if _, embeds := exec.counts(); embeds != 2 {
+		t.Fatalf("embeds = %d, want no attempt after max_attempts failures", embeds)
+	}
+
+	var failed int
+	for _, a := range h.Remediations() {
+		if a.Remediation == "embed_missing_vectors" && a.Err == "embed failed" {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Errorf("audited %d failed embeds, want 2", failed)
+	}
+
+	// Recovery resets the failure count.
+	exec.mu.Lock()
+	exec.vectors, exec.embedErr = 1, nil
+	exec.mu.Unlock()
+	check(h)
+	exec.mu.Lock()
+	exec.vectors = 0
+	exec.mu.Unlock()
+	check(h)
+	if _, embeds := exec.counts(); embeds != 3 {
+		t.Errorf("embeds = %d, want a new attempt after recovery", embeds)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"slices"
 
 	"qmdsr/config"
 	"qmdsr/executor"
@@ -53,6 +54,9 @@ func (s *SelfHealer) CheckEmbeddings(ctx context.Context) (model.HealthLevel, st
 	if s.cfg.Runtime.LowResourceMode {
 		return model.Healthy, "embeddings check disabled in low_resource_mode"
 	}
+	if !slices.ContainsFunc(s.cfg.Collections, func(c config.CollectionCfg) bool { return c.Embed }) {
+		return model.Healthy, "no collection has embed: true, skipping embed check"
+	}
 	if !s.exec.HasCapability("status") {
 		return model.Healthy, "status capability not available, skipping embed check"
 	}
@@ -65,3 +69,21 @@ func (s *SelfHealer) CheckEmbeddings(ctx context.Context) (model.HealthLevel, st
 	}
 	return model.Healthy, ""
 }
+
+// HealActions are the operations the default remediations run.
+type HealActions struct {
+	Update            func(ctx context.Context) error
+	Embed             func(ctx context.Context) error
+	EnsureCollections func(ctx context.Context) error
+}
+
+// Remediations maps the failures found by the checks to the action that
+// repairs them: an empty index is rebuilt with qmd update, missing vectors
+// are embedded and collections that disappeared from qmd are re-added.
+func (s *SelfHealer) Remediations(a HealActions) []Remediation {
+	return []Remediation{
+		{Name: "update_empty_index", Component: "index_db", Level: model.Critical, Match: "index database is empty", Action: a.Update},
+		{Name: "embed_missing_vectors", Component: "embeddings", Level: model.Degraded, Match: "no embeddings found", Action: a.Embed},
+		{Name: "ensure_missing_collections", Component: "collections", Level: model.Degraded, Match: "(missing)", Action: a.EnsureCollections},
+	}
+}
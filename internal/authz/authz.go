@@ -0,0 +1,174 @@
+package authz
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Principal is the identity bound to one bearer token and what it may touch.
+// Empty Collections or Tiers mean "no restriction"; empty RPCs means every
+// QueryService method but no AdminService method.
+type Principal struct {
+	Name        string   `yaml:"name"`
+	Token       string   `yaml:"token"`
+	Collections []string `yaml:"collections"`
+	Tiers       []int    `yaml:"tiers"`
+	RPCs        []string `yaml:"rpcs"`
+}
+
+// Restricted reports whether the principal is limited to a subset of collections.
+func (p *Principal) Restricted() bool {
+	if len(p.Tiers) > 0 {
+		return true
+	}
+	for _, c := range p.Collections {
+		if c == "*" {
+			return false
+		}
+	}
+	return len(p.Collections) > 0
+}
+
+func (p *Principal) AllowsCollection(name string, tier int) bool {
+	if len(p.Collections) > 0 {
+		ok := false
+		for _, c := range p.Collections {
+			if c == "*" || c == name {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(p.Tiers) > 0 {
+		for _, t := range p.Tiers {
+			if t == tier {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// AllowsRPC matches a gRPC full method name such as
+// "/qmdsr.v1.AdminService/Reindex" against the principal's rpc patterns.
+// Patterns use the short "Service/Method" form and path.Match wildcards,
+// e.g. "QueryService/*" or "AdminService/CacheClear"; "*" allows everything.
+func (p *Principal) AllowsRPC(fullMethod string) bool {
+	name := ShortMethod(fullMethod)
+	patterns := p.RPCs
+	if len(patterns) == 0 {
+		patterns = []string{"QueryService/*"}
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" {
+			return true
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ShortMethod turns "/pkg.Service/Method" into "Service/Method".
+func ShortMethod(fullMethod string) string {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return fullMethod
+	}
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		service = service[i+1:]
+	}
+	return service + "/" + method
+}
+
+type TokenStore struct {
+	byHash map[[sha256.Size]byte]*Principal
+}
+
+type tokenFile struct {
+	Tokens []Principal `yaml:"tokens"`
+}
+
+func LoadTokenFile(file string) (*TokenStore, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read token file %s: %w", file, err)
+	}
+	return ParseTokenFile(data)
+}
+
+func ParseTokenFile(data []byte) (*TokenStore, error) {
+	var tf tokenFile
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("parse token file: %w", err)
+	}
+	if len(tf.Tokens) == 0 {
+		return nil, fmt.Errorf("token file defines no tokens")
+	}
+
+	store := &TokenStore{byHash: make(map[[sha256.Size]byte]*Principal, len(tf.Tokens))}
+	names := make(map[string]struct{}, len(tf.Tokens))
+	for i := range tf.Tokens {
+		p := tf.Tokens[i]
+		p.Name = strings.TrimSpace(p.Name)
+		p.Token = strings.TrimSpace(p.Token)
+		if p.Name == "" {
+			return nil, fmt.Errorf("token #%d: name is required", i+1)
+		}
+		if p.Token == "" {
+			return nil, fmt.Errorf("token %s: token is required", p.Name)
+		}
+		if _, dup := names[p.Name]; dup {
+			return nil, fmt.Errorf("token %s: duplicate name", p.Name)
+		}
+		for _, pattern := range p.RPCs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("token %s: invalid rpc pattern %q", p.Name, pattern)
+			}
+		}
+		sum := sha256.Sum256([]byte(p.Token))
+		if _, dup := store.byHash[sum]; dup {
+			return nil, fmt.Errorf("token %s: duplicate token value", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		store.byHash[sum] = &p
+	}
+	return store, nil
+}
+
+// Lookup returns the principal owning token. Tokens are compared by digest so
+// lookup time does not depend on how many leading bytes match.
+func (s *TokenStore) Lookup(token string) (*Principal, bool) {
+	if s == nil || token == "" {
+		return nil, false
+	}
+	p, ok := s.byHash[sha256.Sum256([]byte(token))]
+	return p, ok
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated principal, if any. Requests served
+// under loopback_trust carry none and are unrestricted.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,78 @@
+package authz
+
+import "testing"
+
+const testTokenFile = `
+tokens:
+  - name: owner
+    token: owner-secret
+    rpcs: ["*"]
+  - name: helper
+    token: helper-secret
+    tiers: [1, 2]
+  - name: ops
+    token: ops-secret
+    collections: [digital]
+    rpcs: ["QueryService/Search", "AdminService/CacheClear"]
+`
+
+func TestParseTokenFile_Lookup(t *testing.T) {
+	store, err := ParseTokenFile([]byte(testTokenFile))
+	if err != nil {
+		t.Fatalf("ParseTokenFile failed: %v", err)
+	}
+	p, ok := store.Lookup("helper-secret")
+	if !ok || p.Name != "helper" {
+		t.Fatalf("expected helper principal, got %+v ok=%v", p, ok)
+	}
+	if _, ok := store.Lookup("nope"); ok {
+		t.Fatalf("did not expect unknown token to resolve")
+	}
+}
+
+func TestParseTokenFile_RejectsDuplicates(t *testing.T) {
+	data := `
+tokens:
+  - name: a
+    token: same
+  - name: b
+    token: same
+`
+	if _, err := ParseTokenFile([]byte(data)); err == nil {
+		t.Fatalf("expected duplicate token value to be rejected")
+	}
+}
+
+func TestPrincipal_AllowsCollection(t *testing.T) {
+	store, _ := ParseTokenFile([]byte(testTokenFile))
+	owner, _ := store.Lookup("owner-secret")
+	helper, _ := store.Lookup("helper-secret")
+	ops, _ := store.Lookup("ops-secret")
+
+	if !owner.AllowsCollection("personal", 99) || owner.Restricted() {
+		t.Fatalf("owner should be unrestricted")
+	}
+	if helper.AllowsCollection("personal", 99) || !helper.AllowsCollection("digital", 1) {
+		t.Fatalf("helper should be limited to tiers 1 and 2")
+	}
+	if ops.AllowsCollection("claw-memory", 1) || !ops.AllowsCollection("digital", 1) {
+		t.Fatalf("ops should be limited to the digital collection")
+	}
+}
+
+func TestPrincipal_AllowsRPC(t *testing.T) {
+	store, _ := ParseTokenFile([]byte(testTokenFile))
+	owner, _ := store.Lookup("owner-secret")
+	helper, _ := store.Lookup("helper-secret")
+	ops, _ := store.Lookup("ops-secret")
+
+	if !owner.AllowsRPC("/qmdsr.v1.AdminService/Reindex") {
+		t.Fatalf("owner should reach admin RPCs")
+	}
+	if !helper.AllowsRPC("/qmdsr.v1.QueryService/Get") || helper.AllowsRPC("/qmdsr.v1.AdminService/Reindex") {
+		t.Fatalf("helper should default to query RPCs only")
+	}
+	if !ops.AllowsRPC("/qmdsr.v1.AdminService/CacheClear") || ops.AllowsRPC("/qmdsr.v1.QueryService/Get") {
+		t.Fatalf("ops rpc list not applied")
+	}
+}
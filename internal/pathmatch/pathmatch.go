@@ -0,0 +1,97 @@
+package pathmatch
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMask is the qmd default used when a collection has no mask.
+const DefaultMask = "**/*.md"
+
+// MatchMask reports whether relPath (slash separated, relative to the
+// collection root) matches a qmd collection mask. Besides path.Match syntax
+// it supports "**" for any number of directories and one level of {a,b}
+// alternatives, which covers masks like "**/*.{md,markdown}".
+func MatchMask(mask, relPath string) bool {
+	if mask == "" {
+		mask = DefaultMask
+	}
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+	for _, m := range expandBraces(mask) {
+		if matchSegments(strings.Split(m, "/"), strings.Split(relPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func expandBraces(pattern string) []string {
+	open := strings.IndexByte(pattern, '{')
+	if open < 0 {
+		return []string{pattern}
+	}
+	closing := strings.IndexByte(pattern[open:], '}')
+	if closing < 0 {
+		return []string{pattern}
+	}
+	closing += open
+	prefix, suffix := pattern[:open], pattern[closing+1:]
+	var out []string
+	for _, alt := range strings.Split(pattern[open+1:closing], ",") {
+		out = append(out, expandBraces(prefix+alt+suffix)...)
+	}
+	return out
+}
+
+// Excluded applies collection exclude patterns to relPath. A pattern matches
+// the path itself, any parent directory written with a trailing slash
+// ("drafts/"), or a whole subtree when written as "dir/**".
+func Excluded(patterns []string, relPath string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	relPath = filepath.Clean(relPath)
+
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, relPath); err == nil && matched {
+			return true
+		}
+		dir := filepath.Dir(relPath)
+		for dir != "." && dir != "/" {
+			if matched, err := filepath.Match(pattern, dir+"/"); err == nil && matched {
+				return true
+			}
+			dir = filepath.Dir(dir)
+		}
+		if strings.HasSuffix(pattern, "/**") {
+			prefix := strings.TrimSuffix(pattern, "/**")
+			if strings.HasPrefix(relPath, prefix+"/") || relPath == prefix {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package pathmatch
+
+import "testing"
+
+func TestMatchMask(t *testing.T) {
+	cases := []struct {
+		mask, path string
+		want       bool
+	}{
+		{"", "a.md", true},
+		{"", "deep/nested/a.md", true},
+		{"", "a.txt", false},
+		{"*.md", "sub/a.md", false},
+		{"**/*.{md,markdown}", "x/y.markdown", true},
+		{"journal/**/*.md", "journal/2024/01.md", true},
+		{"journal/**/*.md", "other/01.md", false},
+	}
+	for _, tc := range cases {
+		if got := MatchMask(tc.mask, tc.path); got != tc.want {
+			t.Fatalf("MatchMask(%q, %q) = %v, want %v", tc.mask, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	patterns := []string{"drafts/", "archive/**", "*.tmp.md"}
+	for path, want := range map[string]bool{
+		"drafts/a.md":     true,
+		"archive/x/y.md":  true,
+		"scratch.tmp.md":  true,
+		"notes/drafts.md": false,
+		"notes/a.md":      false,
+	} {
+		if got := Excluded(patterns, path); got != want {
+			t.Fatalf("Excluded(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronNext(t *testing.T) {
+	cases := []struct {
+		expr, from, want string
+	}{
+		{"30 3 * * 0", "2026-10-16 12:00", "2026-10-18 03:30"},
+		{"*/15 * * * *", "2026-10-16 12:07", "2026-10-16 12:15"},
+		{"0 2 * * 1-5", "2026-10-16 02:00", "2026-10-19 02:00"},
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"0 0 1,15 * *", "2026-10-02 00:00", "2026-10-15 00:00"},
+		// Both day fields restricted: either matches.
+		{"0 0 13 * 5", "2026-10-10 00:00", "2026-10-13 00:00"},
+		{"0 4 * * 7", "2026-10-16 00:00", "2026-10-18 04:00"},
+	}
+	for _, tc := range cases {
+		c, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tc.expr, err)
+		}
+		if got := c.Next(at(tc.from)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q from %s = %s, want %s", tc.expr, tc.from, got.Format("2006-01-02 15:04"), tc.want)
+		}
+	}
+
+	c, _ := ParseCron("0 0 30 2 *")
+	if got := c.Next(at("2026-01-01 00:00")); !got.IsZero() {
+		t.Errorf("impossible schedule should never fire, got %s", got)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "x * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
+
+func TestWindowsUntil(t *testing.T) {
+	ws, err := ParseWindows("09:00-18:00, 22:00-00:00,00:00-06:30")
+	if err != nil {
+		t.Fatalf("ParseWindows: %v", err)
+	}
+	cases := []struct {
+		now    string
+		inside bool
+		until  string
+	}{
+		{"2026-10-16 08:59", false, ""},
+		{"2026-10-16 09:00", true, "2026-10-16 18:00"},
+		{"2026-10-16 18:00", false, ""},
+		{"2026-10-16 23:10", true, "2026-10-17 06:30"},
+		{"2026-10-17 03:00", true, "2026-10-17 06:30"},
+	}
+	for _, tc := range cases {
+		until, inside := Until(ws, at(tc.now))
+		if inside != tc.inside || (inside && !until.Equal(at(tc.until))) {
+			t.Errorf("Until(%s) = %s %v, want %s %v", tc.now, until.Format("2006-01-02 15:04"), inside, tc.until, tc.inside)
+		}
+	}
+
+	for _, s := range []string{"9-18", "09:00-09:00", "25:00-01:00"} {
+		if _, err := ParseWindows(s); err == nil {
+			t.Errorf("ParseWindows(%q) should fail", s)
+		}
+	}
+}
+
+func TestWindowsOverlap(t *testing.T) {
+	ws, err := ParseWindows("09:00-18:00,22:00-06:00")
+	if err != nil {
+		t.Fatalf("ParseWindows: %v", err)
+	}
+	cases := []struct {
+		from, to string
+		want     time.Duration
+	}{
+		{"2026-10-16 07:00", "2026-10-16 08:00", 0},
+		{"2026-10-16 17:00", "2026-10-16 23:00", 2 * time.Hour},
+		{"2026-10-16 03:00", "2026-10-16 10:00", 4 * time.Hour},
+		{"2026-10-16 00:00", "2026-10-17 00:00", 17 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := Overlap(ws, at(tc.from), at(tc.to)); got != tc.want {
+			t.Errorf("Overlap(%s, %s) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily local-time range such as 09:00-18:00. A window whose end
+// is not after its start wraps past midnight (22:00-07:00).
+type Window struct {
+	start, end time.Duration
+}
+
+// ParseWindows parses a comma separated list of HH:MM-HH:MM ranges. An empty
+// string yields no windows.
+func ParseWindows(s string) ([]Window, error) {
+	var out []Window
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		a, b, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("window %q: expected HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(a)
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %w", part, err)
+		}
+		end, err := parseClock(b)
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %w", part, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("window %q: start equals end", part)
+		}
+		out = append(out, Window{start: start, end: end})
+	}
+	return out, nil
+}
+
+// Until reports whether t falls inside one of the windows and, if so, when
+// that window (and any window overlapping its end) closes.
+func Until(windows []Window, t time.Time) (time.Time, bool) {
+	end, inside := t, false
+	// Chained windows (22:00-00:00,00:00-06:00) are followed to the last end.
+	for range len(windows) + 1 {
+		advanced := false
+		for _, w := range windows {
+			if e, ok := w.until(end); ok && e.After(end) {
+				end, inside, advanced = e, true, true
+			}
+		}
+		if !advanced {
+			break
+		}
+	}
+	return end, inside
+}
+
+func (w Window) until(t time.Time) (time.Time, bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	clock := t.Sub(midnight)
+	switch {
+	case w.start < w.end && clock >= w.start && clock < w.end:
+		return midnight.Add(w.end), true
+	case w.start > w.end && clock >= w.start:
+		return midnight.AddDate(0, 0, 1).Add(w.end), true
+	case w.start > w.end && clock < w.end:
+		return midnight.Add(w.end), true
+	}
+	return time.Time{}, false
+}
+
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Overlap returns how much of [from, to) falls inside the windows.
+// Time covered by two overlapping windows counts twice.
+func Overlap(windows []Window, from, to time.Time) time.Duration {
+	var total time.Duration
+	if !to.After(from) {
+		return 0
+	}
+	// Wrapping windows that start the day before from still reach into it.
+	day := time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, w := range windows {
+			start, end := day.Add(w.start), day.Add(w.end)
+			if w.end <= w.start {
+				end = day.AddDate(0, 0, 1).Add(w.end)
+			}
+			start, end = maxTime(start, from), minTime(end, to)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
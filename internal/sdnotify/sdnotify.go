@@ -0,0 +1,44 @@
+// Package sdnotify implements the systemd notify protocol used by
+// Type=notify services and WatchdogSec.
+package sdnotify
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notify sends state (for example "READY=1" or "STATUS=...") to the socket
+// in NOTIFY_SOCKET. It reports false without error when qmdsr is not running
+// under systemd notify.
+func Notify(state string) (bool, error) {
+	sock := os.Getenv("NOTIFY_SOCKET")
+	if sock == "" {
+		return false, nil
+	}
+	// A leading @ names an abstract socket, which net handles itself.
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns how often to send WATCHDOG=1: half the timeout
+// systemd passes in WATCHDOG_USEC, or 0 when no watchdog is configured for
+// this process.
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
@@ -13,13 +13,7 @@ func DedupSortLimit(results []model.SearchResult, topK int) []model.SearchResult
 	seen := make(map[string]struct{}, len(results))
 	deduped := make([]model.SearchResult, 0, len(results))
 	for _, r := range results {
-		key := strings.TrimSpace(r.DocID)
-		if key == "" {
-			key = strings.TrimSpace(r.File)
-		}
-		if key == "" {
-			key = fmt.Sprintf("%s|%s|%0.4f", r.Title, r.Snippet, r.Score)
-		}
+		key := resultKey(r)
 		if _, ok := seen[key]; ok {
 			continue
 		}
@@ -54,3 +48,92 @@ func DedupSortLimit(results []model.SearchResult, topK int) []model.SearchResult
 	}
 	return deduped
 }
+
+// FuseRRF merges ranked lists with weighted reciprocal rank fusion
+// (score = sum of weight/(k+rank)). Only the best rank of a document within
+// each list counts. Fused scores are normalized so that a document ranked
+// first in every list scores 1.0; the hit payload comes from the first list
+// that contains the document.
+func FuseRRF(lists [][]model.SearchResult, weights []float64, k int) []model.SearchResult {
+	if k <= 0 {
+		k = 60
+	}
+
+	maxScore := 0.0
+	for i := range lists {
+		maxScore += listWeight(weights, i) / float64(k+1)
+	}
+	if maxScore <= 0 {
+		return nil
+	}
+
+	type fused struct {
+		hit   model.SearchResult
+		score float64
+		order int
+	}
+	byKey := make(map[string]*fused)
+	order := 0
+	for i, list := range lists {
+		w := listWeight(weights, i)
+		seenInList := make(map[string]struct{}, len(list))
+		ranked := make([]model.SearchResult, len(list))
+		copy(ranked, list)
+		sort.SliceStable(ranked, func(a, b int) bool {
+			return ranked[a].Score > ranked[b].Score
+		})
+		rank := 0
+		for _, r := range ranked {
+			key := resultKey(r)
+			if _, ok := seenInList[key]; ok {
+				continue
+			}
+			seenInList[key] = struct{}{}
+			rank++
+			f, ok := byKey[key]
+			if !ok {
+				f = &fused{hit: r, order: order}
+				byKey[key] = f
+				order++
+			}
+			f.score += w / float64(k+rank)
+		}
+	}
+
+	out := make([]*fused, 0, len(byKey))
+	for _, f := range byKey {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		return out[i].order < out[j].order
+	})
+
+	results := make([]model.SearchResult, 0, len(out))
+	for _, f := range out {
+		hit := f.hit
+		hit.Score = f.score / maxScore
+		results = append(results, hit)
+	}
+	return results
+}
+
+func listWeight(weights []float64, i int) float64 {
+	if i < len(weights) && weights[i] >= 0 {
+		return weights[i]
+	}
+	return 1
+}
+
+func resultKey(r model.SearchResult) string {
+	key := strings.TrimSpace(r.DocID)
+	if key == "" {
+		key = strings.TrimSpace(r.File)
+	}
+	if key == "" {
+		key = fmt.Sprintf("%s|%s|%0.4f", r.Title, r.Snippet, r.Score)
+	}
+	return key
+}
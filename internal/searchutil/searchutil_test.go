@@ -43,3 +43,48 @@ func TestDedupSortLimit_StillDedupsWhenTopKUnlimited(t *testing.T) {
 		t.Fatalf("expected 2 deduped results, got %d", len(out))
 	}
 }
+
+func TestFuseRRF_DocumentInBothListsWins(t *testing.T) {
+	bm25 := []model.SearchResult{
+		{DocID: "exact", File: "exact.md", Score: 0.95, Snippet: "bm25 snippet"},
+		{DocID: "both", File: "both.md", Score: 0.70},
+	}
+	vector := []model.SearchResult{
+		{DocID: "para", File: "para.md", Score: 0.88},
+		{DocID: "both", File: "both.md", Score: 0.80, Snippet: "vector snippet"},
+	}
+
+	out := FuseRRF([][]model.SearchResult{bm25, vector}, []float64{1, 1}, 60)
+	if len(out) != 3 {
+		t.Fatalf("expected 3 fused results, got %d", len(out))
+	}
+	if out[0].DocID != "both" {
+		t.Fatalf("expected doc present in both lists first, got %s", out[0].DocID)
+	}
+	if out[0].Score <= 0 || out[0].Score > 1 {
+		t.Fatalf("expected normalized score in (0,1], got %f", out[0].Score)
+	}
+	if out[1].DocID != "exact" {
+		t.Fatalf("expected earlier list to win ties, got %s", out[1].DocID)
+	}
+}
+
+func TestFuseRRF_WeightsShiftRanking(t *testing.T) {
+	bm25 := []model.SearchResult{{DocID: "a", Score: 0.9}}
+	vector := []model.SearchResult{{DocID: "b", Score: 0.9}}
+
+	out := FuseRRF([][]model.SearchResult{bm25, vector}, []float64{0.5, 2}, 60)
+	if len(out) != 2 || out[0].DocID != "b" {
+		t.Fatalf("expected vector-weighted doc first, got %+v", out)
+	}
+}
+
+func TestFuseRRF_TopInEveryListScoresOne(t *testing.T) {
+	a := []model.SearchResult{{DocID: "x", Score: 0.4}, {DocID: "x", Score: 0.3}}
+	b := []model.SearchResult{{DocID: "x", Score: 0.5}}
+
+	out := FuseRRF([][]model.SearchResult{a, b}, nil, 60)
+	if len(out) != 1 || out[0].Score < 0.9999 {
+		t.Fatalf("expected single fused hit with score 1.0, got %+v", out)
+	}
+}
@@ -0,0 +1,119 @@
+package segment
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"qmdsr/internal/textutil"
+)
+
+// stopwords are question words, pronouns, fillers and function words that
+// carry no weight in a keyword query. CJK entries are matched against
+// segmented words, English ones against lowercased words.
+var stopwords = toSet(
+	// Chinese
+	"我", "我们", "你", "你们", "您", "他", "她", "它", "他们", "咱们", "自己",
+	"这", "那", "这个", "那个", "这些", "那些", "这样", "那样", "这里", "那里", "一个", "一些", "一下",
+	"什么", "怎么", "如何", "怎样", "为什么", "为何", "哪些", "哪个", "哪里", "是否", "能不能", "多少", "有没有",
+	"可以", "应该", "能", "会", "要", "想", "想要", "请", "请问", "帮", "帮我", "告诉", "知道", "觉得", "一起",
+	"有", "没有", "不", "还是", "但是", "因为", "所以", "如果", "然后", "已经", "可能", "就是", "其中", "以及",
+	"关于", "进行", "通过", "时候", "之前", "之后", "以前", "最近", "上次", "现在", "比较", "非常", "特别",
+	"想知道", "看看", "上面", "下面", "里面", "东西", "事情", "情况", "地方", "方面", "一点", "一样", "当时",
+	// English
+	"a", "an", "the", "and", "or", "but", "if", "then", "else", "of", "to", "in", "on", "at", "for", "with",
+	"by", "from", "about", "as", "into", "over", "under", "between", "up", "down", "out", "off",
+	"is", "are", "was", "were", "be", "been", "being", "am", "do", "does", "did", "done", "have", "has", "had",
+	"i", "me", "my", "we", "us", "our", "you", "your", "he", "him", "his", "she", "her", "it", "its",
+	"they", "them", "their", "this", "that", "these", "those", "there", "here",
+	"what", "which", "who", "whom", "whose", "when", "where", "why", "how",
+	"can", "could", "should", "would", "will", "shall", "may", "might", "must",
+	"please", "tell", "explain", "describe", "show", "give", "find", "know", "want", "need", "help",
+	"any", "some", "all", "more", "most", "other", "such", "not", "no", "so", "than", "too", "very", "just",
+	"also", "get", "got", "like", "ok", "okay", "thing", "things", "way", "ways", "there's", "it's", "i'm",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// CountWords counts the words of text as Cut splits them.
+func (s *Segmenter) CountWords(text string) int {
+	return len(s.Cut(text))
+}
+
+// Keywords returns up to limit salient words of query in query order.
+// Stopwords, particles, lone unknown CJK characters and duplicates are
+// dropped; when more words remain than limit, the rarest ones are kept.
+func (s *Segmenter) Keywords(query string, limit int) []string {
+	type candidate struct {
+		word     string
+		salience float64
+		pos      int
+	}
+	var cands []candidate
+	seen := make(map[string]bool)
+	for _, w := range s.Cut(query) {
+		lower := strings.ToLower(strings.Trim(w, ".-_"))
+		if lower == "" || seen[lower] || stopwords[lower] || particles[lower] {
+			continue
+		}
+		seen[lower] = true
+		sal, ok := s.salience(lower)
+		if !ok {
+			continue
+		}
+		cands = append(cands, candidate{word: lower, salience: sal, pos: len(cands)})
+	}
+	if limit > 0 && len(cands) > limit {
+		sort.SliceStable(cands, func(i, j int) bool { return cands[i].salience > cands[j].salience })
+		cands = cands[:limit]
+		sort.Slice(cands, func(i, j int) bool { return cands[i].pos < cands[j].pos })
+	}
+	out := make([]string, len(cands))
+	for i, c := range cands {
+		out[i] = c.word
+	}
+	return out
+}
+
+// salience rates how specific a word is: user dictionary terms rank first,
+// then words the dictionary lacks (longer first), then dictionary words by
+// rarity. Numbers rank last. Lone CJK characters outside the dictionary and
+// single letters are not keywords.
+func (s *Segmenter) salience(word string) (float64, bool) {
+	if s.user[word] {
+		return 1 - s.unknown, true
+	}
+	if p, ok := s.logp[word]; ok {
+		return -p, true
+	}
+	r, size := utf8.DecodeRuneInString(word)
+	if size == len(word) && (textutil.IsCJK(r) || unicode.IsLetter(r)) {
+		return 0, false
+	}
+	if strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return 0, true
+	}
+	return -s.unknown + 0.01*float64(utf8.RuneCountInString(word)), true
+}
+
+// KeywordQueries turns a verbose query into BM25 queries: one with all
+// salient words and, when there are more than three, one with the three
+// rarest. It returns nil when the query has no keywords.
+func (s *Segmenter) KeywordQueries(query string, limit int) []string {
+	words := s.Keywords(query, limit)
+	if len(words) == 0 {
+		return nil
+	}
+	queries := []string{strings.Join(words, " ")}
+	if len(words) > 3 {
+		queries = append(queries, strings.Join(s.Keywords(strings.Join(words, " "), 3), " "))
+	}
+	return queries
+}
@@ -0,0 +1,42 @@
+package segment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywords_DropsStopwordsAndKeepsSalientTerms(t *testing.T) {
+	s := New(Dict{"流量整形": userFreq})
+	cases := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"我想知道之前我们讨论过的关于软路由上面如何配置流量整形的结论是什么", 0, []string{"讨论", "软路由", "配置", "流量整形", "结论"}},
+		{"Can you please explain how we should configure traffic shaping on the OpenWrt router", 0, []string{"configure", "traffic", "shaping", "openwrt", "router"}},
+		// The user term outranks dictionary words, numbers rank last.
+		{"请问 2024 年 cake 和 流量整形 的 配置 方案 有什么 区别", 3, []string{"cake", "流量整形", "配置"}},
+		{"如何 为什么 what is it", 0, []string{}},
+		{"Redis redis REDIS 缓存 缓存", 0, []string{"redis", "缓存"}},
+	}
+	for _, tc := range cases {
+		if got := s.Keywords(tc.query, tc.limit); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Keywords(%q, %d) = %q, want %q", tc.query, tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestKeywordQueries(t *testing.T) {
+	s := New(Dict{"流量整形": userFreq})
+	got := s.KeywordQueries("Can you please explain how we should configure the traffic shaping on the OpenWrt router to fix video call lag", 6)
+	want := []string{"configure traffic shaping openwrt router video", "configure traffic shaping"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("KeywordQueries = %q, want %q", got, want)
+	}
+	if got := s.KeywordQueries("软路由 流量整形", 6); !reflect.DeepEqual(got, []string{"软路由 流量整形"}) {
+		t.Errorf("short keyword list = %q, want a single query", got)
+	}
+	if got := s.KeywordQueries("what is it?", 6); got != nil {
+		t.Errorf("stopwords only = %q, want nil", got)
+	}
+}
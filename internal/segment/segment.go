@@ -0,0 +1,204 @@
+package segment
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"qmdsr/internal/textutil"
+)
+
+//go:embed dict.txt
+var baseDict []byte
+
+// userFreq is the frequency of user dictionary entries without one. It is
+// high enough for a domain term to win over splitting it into common words.
+const userFreq = 20000
+
+// particles are single-character function words that only add noise to a
+// BM25 query.
+var particles = map[string]bool{
+	"的": true, "了": true, "吗": true, "呢": true, "吧": true, "啊": true,
+	"着": true, "过": true, "是": true, "在": true, "和": true, "与": true,
+	"及": true, "或": true, "把": true, "被": true, "对": true, "从": true,
+	"到": true, "给": true, "让": true, "也": true, "都": true, "就": true,
+	"还": true, "之": true, "而": true, "并": true, "个": true,
+}
+
+// Dict maps words to their frequencies.
+type Dict map[string]int
+
+// ParseDict decodes a dictionary with one "word [frequency]" entry per line.
+// Blank lines and lines starting with # are skipped.
+func ParseDict(data []byte) (Dict, error) {
+	d := Dict{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: want \"word [frequency]\"", n)
+		}
+		word := fields[0]
+		for _, r := range word {
+			if !textutil.IsCJK(r) {
+				return nil, fmt.Errorf("line %d: %q is not a Chinese word", n, word)
+			}
+		}
+		freq := userFreq
+		if len(fields) == 2 {
+			f, err := strconv.Atoi(fields[1])
+			if err != nil || f <= 0 {
+				return nil, fmt.Errorf("line %d: invalid frequency %q", n, fields[1])
+			}
+			freq = f
+		}
+		d[word] = freq
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// LoadDict reads and parses a user dictionary file.
+func LoadDict(path string) (Dict, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dictionary %s: %w", path, err)
+	}
+	d, err := ParseDict(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse dictionary %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// Segmenter splits CJK runs into the most probable sequence of dictionary
+// words.
+type Segmenter struct {
+	logp    map[string]float64
+	user    map[string]bool
+	unknown float64
+	maxLen  int
+}
+
+// New builds a segmenter from the built-in dictionary extended with user
+// words. User entries override built-in frequencies.
+func New(user Dict) *Segmenter {
+	words, err := ParseDict(baseDict)
+	if err != nil {
+		panic(err)
+	}
+	for w, f := range user {
+		words[w] = f
+	}
+	var total float64
+	for _, f := range words {
+		total += float64(f)
+	}
+	s := &Segmenter{logp: make(map[string]float64, len(words)), user: make(map[string]bool, len(user)), unknown: math.Log(1 / total)}
+	for w := range user {
+		s.user[w] = true
+	}
+	for w, f := range words {
+		s.logp[w] = math.Log(float64(f) / total)
+		s.maxLen = max(s.maxLen, len([]rune(w)))
+	}
+	return s
+}
+
+// Cut splits text into words. CJK runs are segmented with the dictionary,
+// other letters and digits are kept as whole words, and whitespace and
+// punctuation are dropped.
+func (s *Segmenter) Cut(text string) []string {
+	var out []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch r := runes[i]; {
+		case textutil.IsCJK(r):
+			for j < len(runes) && textutil.IsCJK(runes[j]) {
+				j++
+			}
+			out = append(out, s.cutCJK(runes[i:j])...)
+		case isWordRune(r):
+			for j < len(runes) && isWordRune(runes[j]) && !textutil.IsCJK(runes[j]) {
+				j++
+			}
+			out = append(out, string(runes[i:j]))
+		}
+		i = j
+	}
+	return out
+}
+
+// cutCJK picks the segmentation with the highest unigram probability.
+func (s *Segmenter) cutCJK(runes []rune) []string {
+	n := len(runes)
+	score := make([]float64, n+1)
+	next := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		score[i] = math.Inf(-1)
+		for j := i + 1; j <= n && j-i <= s.maxLen; j++ {
+			p, ok := s.logp[string(runes[i:j])]
+			if !ok {
+				if j > i+1 {
+					continue
+				}
+				p = s.unknown
+			}
+			if p+score[j] >= score[i] {
+				score[i], next[i] = p+score[j], j
+			}
+		}
+	}
+	words := make([]string, 0, n)
+	for i := 0; i < n; i = next[i] {
+		words = append(words, string(runes[i:next[i]]))
+	}
+	return words
+}
+
+// BM25Query rewrites a query containing CJK text into space-separated words
+// without particles, which BM25 matches far better than one long run.
+// Quoted phrases are kept verbatim and queries without CJK text are returned
+// unchanged.
+func (s *Segmenter) BM25Query(query string) string {
+	if textutil.CountCJK(query) == 0 {
+		return query
+	}
+	var terms []string
+	parts := strings.Split(query, `"`)
+	for i, part := range parts {
+		// An unbalanced trailing quote does not open a phrase.
+		if i%2 == 1 && i < len(parts)-1 {
+			if part = strings.TrimSpace(part); part != "" {
+				terms = append(terms, `"`+part+`"`)
+			}
+			continue
+		}
+		for _, w := range s.Cut(part) {
+			if !particles[w] {
+				terms = append(terms, w)
+			}
+		}
+	}
+	if len(terms) == 0 {
+		return query
+	}
+	return strings.Join(terms, " ")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
@@ -0,0 +1,214 @@
+package segment
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func loadTestDict(t *testing.T) Dict {
+	t.Helper()
+	d, err := LoadDict(filepath.Join("testdata", "user.dict"))
+	if err != nil {
+		t.Fatalf("LoadDict: %v", err)
+	}
+	return d
+}
+
+func TestCut_UserDictionaryKeepsDomainTerms(t *testing.T) {
+	base := New(nil)
+	if got, want := base.Cut("配置流量整形"), []string{"配置", "流量", "整形"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("built-in Cut = %v, want %v", got, want)
+	}
+
+	s := New(loadTestDict(t))
+	cases := map[string][]string{
+		"如何配置流量整形":          {"如何", "配置", "流量整形"},
+		"旁路网关宕机":            {"旁路网关", "宕机"},
+		"OpenWrt的SQM配置v1.2": {"OpenWrt", "的", "SQM", "配置", "v1.2"},
+		"  ，。!":             nil,
+	}
+	for in, want := range cases {
+		if got := s.Cut(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Cut(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+// The built-in dictionary is small: words outside it and the user
+// dictionary fall apart into single characters.
+func TestCut_OutOfDictionaryWordsFallApart(t *testing.T) {
+	if got, want := New(nil).Cut("空腹抽血报告"), []string{"空", "腹", "抽", "血", "报告"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("built-in Cut = %v, want %v", got, want)
+	}
+	if got, want := New(Dict{"空腹": userFreq, "抽血": userFreq}).Cut("空腹抽血报告"), []string{"空腹", "抽血", "报告"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cut with user words = %v, want %v", got, want)
+	}
+}
+
+func TestBM25Query(t *testing.T) {
+	s := New(loadTestDict(t))
+	cases := map[string]string{
+		"如何配置软路由的流量整形":      "如何 配置 软路由 流量整形",
+		`"流量整形" 的参数`:        `"流量整形" 参数`,
+		"grpc retry policy": "grpc retry policy",
+		`unbalanced "流量整形`:  "unbalanced 流量整形",
+		"的":                 "的",
+		"cake 做流量整形 上行带宽限制": "cake 做 流量整形 上行 带宽 限制",
+	}
+	for in, want := range cases {
+		if got := s.BM25Query(in); got != want {
+			t.Errorf("BM25Query(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseDict_Invalid(t *testing.T) {
+	for _, data := range []string{"流量整形 high", "流量整形 0", "流量 整形 5", "QoS 10"} {
+		if _, err := ParseDict([]byte(data)); err == nil {
+			t.Errorf("ParseDict(%q): expected an error", data)
+		}
+	}
+}
+
+type recallQuery struct {
+	query string
+	want  string
+}
+
+// TestBM25Query_RecallOnFixtureCorpus scores the fixture notes with BM25 over
+// substring term frequencies, which is how an FTS index that does not
+// segment Chinese can still match words inside a CJK run, and compares
+// recall@3 of the raw and the segmented queries.
+func TestBM25Query_RecallOnFixtureCorpus(t *testing.T) {
+	docs := loadCorpus(t)
+	s := New(loadTestDict(t))
+	queries := []recallQuery{
+		{"如何配置软路由的流量整形", "traffic-shaping.md"},
+		{"旁路网关宕机怎么恢复", "bypass-gateway.md"},
+		{"向量检索召回效果不好", "vector-search.md"},
+		{"中文分词导致全文检索召回差", "bm25-tokenizer.md"},
+		{"缓存什么时候失效", "cache-design.md"},
+		{"数据库备份多久一次", "backup-plan.md"},
+		{"证书续期失败怎么办", "cert-renewal.md"},
+		{"体检需要预约吗", "health-checkup.md"},
+		{"孩子学校家长会时间", "kid-school.md"},
+		{"集群升级之前要备份什么", "k8s-upgrade.md"},
+		{"延迟超过阈值的告警", "monitoring.md"},
+		{"上行带宽限流丢包", "qos-notes.md"},
+	}
+
+	raw, segmented := recallAt3(t, docs, s, queries)
+	t.Logf("recall@3: raw=%.2f segmented=%.2f", raw, segmented)
+	if segmented < 0.9 || segmented <= raw {
+		t.Errorf("recall@3 raw=%.2f segmented=%.2f, want segmented >= 0.90 and above raw", raw, segmented)
+	}
+
+	// These queries use words missing from both dictionaries (空腹, 抽血,
+	// 拨号, 设置, 优先级, 关联, 失败, 采集, 段落), which are cut into single
+	// characters. The substring scorer still matches single characters, so
+	// this only guards against segmentation doing worse than the raw query;
+	// qmd's FTS index, which keeps a CJK run as one token, would not.
+	oov := []recallQuery{
+		{"空腹抽血", "health-checkup.md"},
+		{"抽血报告", "health-checkup.md"},
+		{"主路由拨号设置", "bypass-gateway.md"},
+		{"拨号上网", "bypass-gateway.md"},
+		{"设备优先级怎么划分", "qos-notes.md"},
+		{"按请求关联日志", "monitoring.md"},
+		{"续期失败", "cert-renewal.md"},
+		{"采集服务指标", "monitoring.md"},
+		{"按段落切分", "vector-search.md"},
+	}
+	raw, segmented = recallAt3(t, docs, s, oov)
+	t.Logf("recall@3 outside the dictionary: raw=%.2f segmented=%.2f", raw, segmented)
+	if segmented < raw {
+		t.Errorf("recall@3 outside the dictionary raw=%.2f segmented=%.2f, want segmented >= raw", raw, segmented)
+	}
+}
+
+func recallAt3(t *testing.T, docs map[string]string, s *Segmenter, queries []recallQuery) (raw, segmented float64) {
+	t.Helper()
+	for _, q := range queries {
+		if slices.Contains(rankBM25(docs, strings.Fields(q.query), 3), q.want) {
+			raw++
+		}
+		terms := strings.Fields(s.BM25Query(q.query))
+		if slices.Contains(rankBM25(docs, terms, 3), q.want) {
+			segmented++
+		} else {
+			t.Logf("missed %s for %q (terms %v)", q.want, q.query, terms)
+		}
+	}
+	return raw / float64(len(queries)), segmented / float64(len(queries))
+}
+
+func loadCorpus(t *testing.T) map[string]string {
+	t.Helper()
+	dir := filepath.Join("testdata", "corpus")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := make(map[string]string, len(entries))
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs[e.Name()] = strings.ToLower(string(data))
+	}
+	return docs
+}
+
+func rankBM25(docs map[string]string, terms []string, k int) []string {
+	const k1, b = 1.2, 0.75
+	var avgLen float64
+	for _, d := range docs {
+		avgLen += float64(len([]rune(d)))
+	}
+	avgLen /= float64(len(docs))
+
+	scores := make(map[string]float64, len(docs))
+	for _, term := range terms {
+		term = strings.ToLower(strings.Trim(term, `"`))
+		df := 0
+		for _, d := range docs {
+			if strings.Contains(d, term) {
+				df++
+			}
+		}
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(len(docs))-float64(df)+0.5)/(float64(df)+0.5))
+		for name, d := range docs {
+			tf := float64(strings.Count(d, term))
+			if tf == 0 {
+				continue
+			}
+			norm := 1 - b + b*float64(len([]rune(d)))/avgLen
+			scores[name] += idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+
+	ranked := make([]string, 0, len(scores))
+	for name := range scores {
+		ranked = append(ranked, name)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > k {
+		ranked = ranked[:k]
+	}
+	return ranked
+}
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCorrupt is returned by Read when a snapshot cannot be trusted: it is
+// truncated, fails its checksum, or belongs to another store or format
+// version. Callers should start empty rather than fail.
+var ErrCorrupt = errors.New("snapshot corrupt")
+
+type envelope struct {
+	Kind     string          `json:"kind"`
+	Version  int             `json:"version"`
+	SavedAt  time.Time       `json:"saved_at"`
+	Checksum string          `json:"checksum"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// Write stores payload under path atomically: the data is written to a
+// temporary file in the same directory, synced, then renamed into place.
+func Write(path, kind string, version int, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s snapshot: %w", kind, err)
+	}
+	sum := sha256.Sum256(data)
+	out, err := json.Marshal(envelope{
+		Kind:     kind,
+		Version:  version,
+		SavedAt:  time.Now(),
+		Checksum: hex.EncodeToString(sum[:]),
+		Payload:  data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s snapshot: %w", kind, err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read decodes the snapshot at path into dst. A missing file is reported as
+// os.ErrNotExist; anything unreadable wraps ErrCorrupt.
+func Read(path, kind string, version int, dst any) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if env.Kind != kind {
+		return time.Time{}, fmt.Errorf("%w: kind %q, want %q", ErrCorrupt, env.Kind, kind)
+	}
+	if env.Version != version {
+		return time.Time{}, fmt.Errorf("%w: format version %d, want %d", ErrCorrupt, env.Version, version)
+	}
+	sum := sha256.Sum256(env.Payload)
+	if hex.EncodeToString(sum[:]) != env.Checksum {
+		return time.Time{}, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	if err := json.Unmarshal(env.Payload, dst); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return env.SavedAt, nil
+}
+
+// Quarantine moves a corrupt snapshot aside so it is kept for inspection but
+// not read again on the next start.
+func Quarantine(path string) error {
+	return os.Rename(path, path+".corrupt")
+}
@@ -0,0 +1,62 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type payload struct {
+	Items map[string]int `json:"items"`
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "store.json")
+	if err := Write(path, "store", 1, payload{Items: map[string]int{"a": 1}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var got payload
+	if _, err := Read(path, "store", 1, &got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if got.Items["a"] != 1 {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+}
+
+func TestRead_RejectsCorruptOrForeignSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+	if err := Write(path, "store", 1, payload{Items: map[string]int{"a": 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got payload
+	if _, err := Read(path, "store", 2, &got); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected format version mismatch to be corrupt, got %v", err)
+	}
+	if _, err := Read(path, "other", 1, &got); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected kind mismatch to be corrupt, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, data[:len(data)/2], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, "store", 1, &got); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected truncated snapshot to be corrupt, got %v", err)
+	}
+
+	tampered := []byte(`{"kind":"store","version":1,"checksum":"00","payload":{"items":{"a":2}}}`)
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, "store", 1, &got); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected checksum mismatch to be corrupt, got %v", err)
+	}
+
+	if _, err := Read(filepath.Join(dir, "missing.json"), "store", 1, &got); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing snapshot to be ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package synonym
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"qmdsr/internal/pathmatch"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxFrontmatterLines bounds how far a note is read looking for the end of
+// its frontmatter.
+const maxFrontmatterLines = 200
+
+// HarvestAliases collects the Obsidian aliases of the notes under root that
+// match mask and are not excluded. Each note with aliases yields a group of
+// its title (the file name without extension) and its aliases. Hidden
+// directories such as .obsidian are skipped.
+func HarvestAliases(root, mask string, exclude []string) (Dict, error) {
+	root = filepath.Clean(root)
+	var d Dict
+	err := filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if p != root && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if !pathmatch.MatchMask(mask, rel) || pathmatch.Excluded(exclude, rel) {
+			return nil
+		}
+		aliases := readAliases(p)
+		if len(aliases) == 0 {
+			return nil
+		}
+		title := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if group := normalizeGroup(append([]string{title}, aliases...)); len(group) > 1 {
+			d = append(d, group)
+		}
+		return nil
+	})
+	return d, err
+}
+
+// readAliases returns the aliases (or legacy alias) frontmatter entry of a
+// note, which may be a list or a single string.
+func readAliases(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != "---" {
+		return nil
+	}
+	var fm strings.Builder
+	closed := false
+	for i := 0; i < maxFrontmatterLines && sc.Scan(); i++ {
+		line := sc.Text()
+		if t := strings.TrimSpace(line); t == "---" || t == "..." {
+			closed = true
+			break
+		}
+		fm.WriteString(line)
+		fm.WriteByte('\n')
+	}
+	if !closed {
+		return nil
+	}
+
+	var meta struct {
+		Aliases yaml.Node `yaml:"aliases"`
+		Alias   yaml.Node `yaml:"alias"`
+	}
+	if err := yaml.Unmarshal([]byte(fm.String()), &meta); err != nil {
+		return nil
+	}
+	return append(nodeStrings(meta.Aliases), nodeStrings(meta.Alias)...)
+}
+
+func nodeStrings(n yaml.Node) []string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Value != "" && n.Tag != "!!null" {
+			return strings.Split(n.Value, ",")
+		}
+	case yaml.SequenceNode:
+		var out []string
+		for _, item := range n.Content {
+			if item.Kind == yaml.ScalarNode && item.Value != "" {
+				out = append(out, item.Value)
+			}
+		}
+		return out
+	}
+	return nil
+}
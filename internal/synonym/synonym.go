@@ -0,0 +1,169 @@
+package synonym
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"qmdsr/internal/textutil"
+)
+
+// Dict is a list of synonym groups. Every term of a group can stand in for
+// the others.
+type Dict [][]string
+
+// Parse decodes a synonym file with one comma-separated group per line, e.g.
+// "k8s, Kubernetes, 集群". Blank lines and lines starting with # are
+// skipped. Terms are matched case-insensitively.
+func Parse(data []byte) (Dict, error) {
+	var d Dict
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		group := normalizeGroup(strings.Split(line, ","))
+		if len(group) < 2 {
+			return nil, fmt.Errorf("line %d: a group needs at least two terms", n)
+		}
+		d = append(d, group)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Load reads and parses a synonym file.
+func Load(path string) (Dict, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read synonyms %s: %w", path, err)
+	}
+	d, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse synonyms %s: %w", path, err)
+	}
+	return d, nil
+}
+
+func normalizeGroup(terms []string) []string {
+	var group []string
+	for _, t := range terms {
+		t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if t != "" && !slices.Contains(group, t) {
+			group = append(group, t)
+		}
+	}
+	return group
+}
+
+// Expansion records the synonyms a query term was expanded with.
+type Expansion struct {
+	Term     string
+	Synonyms []string
+}
+
+func (e Expansion) String() string {
+	return e.Term + "->" + strings.Join(e.Synonyms, ",")
+}
+
+// Expander looks up synonyms across a merged set of dictionaries.
+type Expander struct {
+	groups  [][]string
+	byTerm  map[string][]int
+	maxSpan int
+}
+
+// NewExpander merges dicts. A term listed in several groups gets the
+// synonyms of all of them.
+func NewExpander(dicts ...Dict) *Expander {
+	e := &Expander{byTerm: make(map[string][]int)}
+	for _, d := range dicts {
+		for _, group := range d {
+			id := len(e.groups)
+			e.groups = append(e.groups, group)
+			for _, t := range group {
+				e.byTerm[t] = append(e.byTerm[t], id)
+				e.maxSpan = max(e.maxSpan, span(t))
+			}
+		}
+	}
+	return e
+}
+
+// span is how many BM25 query terms t may cover: one per word, and one per
+// character for CJK terms the segmenter may have split.
+func span(t string) int {
+	if textutil.CountCJK(t) > 0 {
+		return utf8.RuneCountInString(strings.ReplaceAll(t, " ", ""))
+	}
+	return len(strings.Fields(t))
+}
+
+// Expand finds dictionary terms among the space-separated terms of a BM25
+// query and returns up to limit variants of the query, each with one matched
+// term replaced by a synonym, along with the expansions used. CJK terms also
+// match when they were split across consecutive query terms.
+func (e *Expander) Expand(query string, limit int) ([]string, []Expansion) {
+	if e == nil || len(e.groups) == 0 || limit <= 0 {
+		return nil, nil
+	}
+	terms := strings.Fields(query)
+	var variants []string
+	var expansions []Expansion
+	for i := 0; i < len(terms) && len(variants) < limit; {
+		term, n := e.match(terms[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		exp := Expansion{Term: term}
+		for _, syn := range e.synonyms(term) {
+			if len(variants) == limit {
+				break
+			}
+			variant := slices.Concat(terms[:i], []string{syn}, terms[i+n:])
+			variants = append(variants, strings.Join(variant, " "))
+			exp.Synonyms = append(exp.Synonyms, syn)
+		}
+		expansions = append(expansions, exp)
+		i += n
+	}
+	return variants, expansions
+}
+
+// match returns the longest dictionary term at the start of terms and how
+// many terms it covers.
+func (e *Expander) match(terms []string) (string, int) {
+	for n := min(e.maxSpan, len(terms)); n > 0; n-- {
+		words := make([]string, n)
+		for i, t := range terms[:n] {
+			words[i] = strings.ToLower(t)
+		}
+		if key := strings.Join(words, " "); e.byTerm[key] != nil {
+			return key, n
+		}
+		if key := strings.Join(words, ""); n > 1 && textutil.CountCJK(key) > 0 && e.byTerm[key] != nil {
+			return key, n
+		}
+	}
+	return "", 0
+}
+
+func (e *Expander) synonyms(term string) []string {
+	var out []string
+	for _, id := range e.byTerm[term] {
+		for _, t := range e.groups[id] {
+			if t != term && !slices.Contains(out, t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
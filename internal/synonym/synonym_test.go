@@ -0,0 +1,73 @@
+package synonym
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte("# aliases\nk8s, Kubernetes , 集群\n\nGTD,待办, gtd\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := Dict{{"k8s", "kubernetes", "集群"}, {"gtd", "待办"}}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Parse = %q, want %q", d, want)
+	}
+	for _, data := range []string{"k8s", "gtd, GTD"} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q): expected an error", data)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	e := NewExpander(
+		Dict{{"k8s", "kubernetes", "集群"}, {"traffic shaping", "qos"}},
+		Dict{{"待办", "gtd"}, {"k8s", "k3s"}},
+	)
+	cases := []struct {
+		query      string
+		limit      int
+		variants   []string
+		expansions []string
+	}{
+		{"K8s 升级", 10, []string{"kubernetes 升级", "集群 升级", "k3s 升级"}, []string{"k8s->kubernetes,集群,k3s"}},
+		{"openwrt traffic shaping cake", 10, []string{"openwrt qos cake"}, []string{"traffic shaping->qos"}},
+		// The segmenter split the CJK term across two query terms.
+		{"整理 待 办 清单", 10, []string{"整理 gtd 清单"}, []string{"待办->gtd"}},
+		{"k8s 待办", 2, []string{"kubernetes 待办", "集群 待办"}, []string{"k8s->kubernetes,集群"}},
+		{"nothing here", 10, nil, nil},
+	}
+	for _, tc := range cases {
+		variants, expansions := e.Expand(tc.query, tc.limit)
+		var got []string
+		for _, x := range expansions {
+			got = append(got, x.String())
+		}
+		if !reflect.DeepEqual(variants, tc.variants) || !reflect.DeepEqual(got, tc.expansions) {
+			t.Errorf("Expand(%q) = %q %q, want %q %q", tc.query, variants, got, tc.variants, tc.expansions)
+		}
+	}
+
+	var nilExpander *Expander
+	if v, x := nilExpander.Expand("k8s", 4); v != nil || x != nil {
+		t.Errorf("nil expander expanded to %q %v", v, x)
+	}
+}
+
+func TestHarvestAliases(t *testing.T) {
+	d, err := HarvestAliases(filepath.Join("testdata", "vault"), "", []string{"drafts/"})
+	if err != nil {
+		t.Fatalf("HarvestAliases: %v", err)
+	}
+	want := Dict{
+		{"kubernetes", "k8s", "集群"},
+		{"gtd", "待办", "getting things done"},
+		{"legacy", "okr", "目标管理"},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("HarvestAliases = %q, want %q", d, want)
+	}
+}
@@ -18,9 +18,12 @@ import (
 	"qmdsr/guardian"
 	"qmdsr/heartbeat"
 	"qmdsr/internal/version"
+	"qmdsr/metrics"
 	"qmdsr/model"
 	"qmdsr/orchestrator"
 	"qmdsr/scheduler"
+	"qmdsr/tracing"
+	"qmdsr/watcher"
 )
 
 func main() {
@@ -83,31 +86,82 @@ func main() {
 	}
 	logger.Info("qmdsr starting", startupAttrs...)
 
-	exec, err := executor.NewCLI(cfg, logger.With("component", "executor"))
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, logger.With("component", "tracing"))
+	if err != nil {
+		logger.Error("tracing setup failed, continuing without export", "err", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+
+	cliExec, err := executor.NewCLI(cfg, logger.With("component", "executor"))
 	if err != nil {
 		logger.Error("failed to initialize executor", "err", err)
 		os.Exit(1)
 	}
-	if !exec.HasCapability("deep_query") && !exec.HasCapability("vector") {
+	if !cliExec.HasCapability("deep_query") && !cliExec.HasCapability("vector") {
 		logger.Warn("both deep_query and vector are disabled; running in BM25-only mode")
 	}
 
+	guard := guardian.New(cfg, cliExec, logger.With("component", "guardian"))
+
+	var exec executor.Executor = cliExec
+	if cfg.QMD.SearchViaMCP && cliExec.HasCapability("mcp") {
+		mcpExec := executor.NewMCP(cfg, cliExec.QueryLimiter(), logger.With("component", "mcp_executor"))
+		exec = executor.NewRouted(cliExec, mcpExec, func() bool { return !guard.IsCLIMode() }, logger.With("component", "executor"))
+		logger.Info("searches routed through MCP daemon", "port", cfg.QMD.MCPPort, "path", cfg.QMD.MCPPath)
+	}
+
 	c := cache.New(&cfg.Cache)
 
 	orch := orchestrator.New(cfg, exec, c, logger.With("component", "orchestrator"))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+
+	var state *stateStore
+	if cfg.Cache.Persist {
+		state = &stateStore{cfg: cfg, cache: c, orch: orch, log: logger.With("component", "state")}
+		state.restore()
+		go state.loop(ctx)
+	}
+
 	orch.Start(ctx)
 
+	notify(logger, "STATUS=registering collections")
 	if err := orch.EnsureCollections(ctx); err != nil {
 		logger.Error("failed to ensure collections", "err", err)
 	}
+	if cfg.QMD.Reconcile != "off" {
+		report, err := orch.ReconcileCollections(ctx, cfg.QMD.Reconcile == "repair", false)
+		switch {
+		case err != nil:
+			logger.Error("collection reconcile failed", "err", err)
+		case len(report.Drift) > 0 && !report.Applied:
+			logger.Warn("collection drift detected, run AdminService.ReconcileCollections with repair=true or set qmd.reconcile: repair",
+				"drift", report.Drift)
+		case report.Applied:
+			logger.Info("collection drift repaired", "actions", report.Actions)
+		}
+	}
 
-	sched := scheduler.New(cfg, exec, c, orch.CleanupDeepNegativeCache, logger.With("component", "scheduler"))
+	sched := scheduler.New(cfg, exec, c, orch, orch.CleanupDeepNegativeCache, logger.With("component", "scheduler"))
+	if state != nil {
+		// Notes edited while qmdsr was down must not be served from the
+		// restored snapshot.
+		sched.SyncCacheVersions()
+	}
+	sched.OnIndexRefresh(orch.RefreshAliases)
 	sched.Start(ctx)
+	go orch.RefreshAliases(ctx, nil)
+
+	var fsWatcher *watcher.Watcher
+	if cfg.Watcher.Enabled {
+		fsWatcher = watcher.New(cfg, sched.ReindexCollections, logger.With("component", "watcher"))
+		if err := fsWatcher.Start(ctx); err != nil {
+			logger.Error("failed to start watcher, relying on scheduled index refresh", "err", err)
+			fsWatcher = nil
+		}
+	}
 
-	guard := guardian.New(cfg, exec, logger.With("component", "guardian"))
 	guard.Start(ctx)
 
 	healer := heartbeat.NewSelfHealer(cfg, exec, logger.With("component", "selfheal"))
@@ -122,42 +176,91 @@ func main() {
 		}
 		return model.Unhealthy, "cache unhealthy"
 	})
+	hb.Register("collections", orch.CheckCollections)
+	hb.Register("scheduler", sched.CheckStaleness)
 	hb.Register("mcp_daemon", func(_ context.Context) (model.HealthLevel, string) {
 		return guard.Health()
 	})
+	if cfg.SelfHeal.Enabled {
+		hb.SetRemediationPolicy(heartbeat.RemediationPolicy{
+			MinInterval: cfg.SelfHeal.MinInterval,
+			MaxBackoff:  cfg.SelfHeal.MaxBackoff,
+			MaxAttempts: cfg.SelfHeal.MaxAttempts,
+		})
+		for _, r := range healer.Remediations(heartbeat.HealActions{
+			Update:            sched.TriggerReindex,
+			Embed:             func(ctx context.Context) error { return sched.TriggerEmbed(ctx, false) },
+			EnsureCollections: orch.EnsureCollections,
+		}) {
+			hb.Remediate(r)
+		}
+	}
+	hb.OnCheck(func(h *model.SystemHealth) { notify(logger, "STATUS="+healthStatus(h)) })
 	hb.Start(ctx)
 
+	rl := &reloader{
+		path:    *configPath,
+		log:     logger.With("component", "reload"),
+		cache:   c,
+		exec:    cliExec,
+		queries: cliExec.QueryLimiter(),
+		orch:    orch,
+		sched:   sched,
+		watcher: fsWatcher,
+		cfg:     cfg,
+	}
+
 	srv := api.NewServer(api.Deps{
-		Config:       cfg,
-		Orchestrator: orch,
-		Executor:     exec,
-		Scheduler:    sched,
-		Guardian:     guard,
-		Heartbeat:    hb,
-		Logger:       logger.With("component", "api"),
+		Config:        cfg,
+		Orchestrator:  orch,
+		Executor:      exec,
+		Scheduler:     sched,
+		Guardian:      guard,
+		Heartbeat:     hb,
+		Logger:        logger.With("component", "api"),
+		ConfigManager: rl,
 	})
+	rl.srv = srv
 
 	if err := srv.Start(); err != nil {
 		logger.Error("gRPC server start failed", "err", err)
 		os.Exit(1)
 	}
 
-	go watchdog()
+	metrics.RegisterCache(c.Stats)
+	metrics.RegisterCPU(orch.CPUSnapshot)
+	var metricsSrv *metrics.Server
+	if cfg.Server.MetricsListen != "" {
+		metricsSrv, err = metrics.Serve(cfg.Server.MetricsListen, logger.With("component", "metrics"))
+		if err != nil {
+			logger.Error("metrics listener failed, continuing without /metrics", "err", err)
+		}
+	}
+
+	go watchdog(ctx, orch.Ping, hb, logger.With("component", "watchdog"))
+	go rl.watchSignals(ctx)
 
 	logger.Info("qmdsr ready",
 		"grpc_listen", cfg.Server.GRPCListen,
 		"pid", os.Getpid(),
 	)
+	notify(logger, "READY=1\nSTATUS="+healthStatus(hb.GetHealth()))
 
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 	<-sig
 
 	logger.Info("shutting down...")
+	notify(logger, "STOPPING=1\nSTATUS=shutting down")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
+	if fsWatcher != nil {
+		fsWatcher.Stop()
+	}
+	// Stop waits for running tasks, which update cache versions and
+	// scheduler state, so the flush below sees their final writes.
 	sched.Stop()
 	guard.Stop()
 	hb.Stop()
@@ -165,6 +268,18 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("gRPC server shutdown error", "err", err)
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("metrics server shutdown error", "err", err)
+		}
+	}
+
+	if state != nil {
+		state.save()
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("tracing shutdown error", "err", err)
+	}
 
 	cancel()
 	logger.Info("qmdsr stopped")
@@ -199,23 +314,3 @@ func setupLogger(cfg *config.Config) *slog.Logger {
 
 	return slog.New(slog.NewJSONHandler(os.Stderr, opts))
 }
-
-func watchdog() {
-	sock := os.Getenv("NOTIFY_SOCKET")
-	if sock == "" {
-		return
-	}
-
-	ticker := time.NewTicker(60 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		conn, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM, 0)
-		if err != nil {
-			continue
-		}
-		sa := &syscall.SockaddrUnix{Name: sock}
-		_ = syscall.Sendmsg(conn, []byte("WATCHDOG=1"), nil, sa, 0)
-		syscall.Close(conn)
-	}
-}
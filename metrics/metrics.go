@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"qmdsr/internal/resourceguard"
+)
+
+const namespace = "qmdsr"
+
+// Registry holds every qmdsr metric. It is separate from the default
+// registry so tests and embedders do not pick up unrelated collectors.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	SearchLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "search_latency_seconds",
+		Help:      "Search latency by served mode, cache hits excluded.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+	}, []string{"mode"})
+
+	SearchHits = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "search_hits",
+		Help:      "Number of results returned per search by served mode.",
+		Buckets:   []float64{0, 1, 3, 8, 20},
+	}, []string{"mode"})
+
+	SearchDegraded = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "search_degraded_total",
+		Help:      "Degraded searches by degrade reason.",
+	}, []string{"reason"})
+
+	DeepNegativeMarks = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deep_negative_marks_total",
+		Help:      "Deep queries recorded in the deep negative cache.",
+	})
+
+	DeepNegativeHits = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deep_negative_hits_total",
+		Help:      "Deep queries skipped by the deep negative cache, by match kind (exact or scope).",
+	}, []string{"kind"})
+
+	SchedulerTaskDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_task_duration_seconds",
+		Help:      "Duration of scheduler task runs, retries included.",
+		Buckets:   []float64{0.1, 1, 5, 15, 60, 300, 900, 1800},
+	}, []string{"task"})
+
+	SchedulerTaskFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduler_task_failures_total",
+		Help:      "Scheduler task runs that failed after all retries.",
+	}, []string{"task"})
+
+	GuardianRestarts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "guardian_mcp_restarts_total",
+		Help:      "MCP daemon restarts attempted by the guardian, by result.",
+	}, []string{"result"})
+
+	SelfHealActions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "selfheal_actions_total",
+		Help:      "Heartbeat remediations run, by remediation and result.",
+	}, []string{"action", "result"})
+
+	QMDInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "qmd_subprocesses_in_flight",
+		Help:      "qmd CLI subprocesses currently running, by subcommand.",
+	}, []string{"command"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// CacheStats matches cache.Cache.Stats.
+type CacheStats func() (size int, hits, misses int64)
+
+// The cache and CPU gauges are registered once and read whichever source was
+// registered last, so registering again replaces the source instead of
+// panicking on a duplicate collector.
+var (
+	cacheOnce   sync.Once
+	cacheSource atomic.Pointer[CacheStats]
+	cpuOnce     sync.Once
+	cpuSource   atomic.Pointer[func() resourceguard.CPUSnapshot]
+)
+
+// RegisterCache exposes result cache size and lookup counters.
+func RegisterCache(stats CacheStats) {
+	cacheSource.Store(&stats)
+	cacheOnce.Do(func() {
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_entries",
+			Help:      "Entries currently held by the result cache.",
+		}, func() float64 {
+			size, _, _ := cacheStats()
+			return float64(size)
+		})
+		factory.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Result cache lookups that returned an entry.",
+		}, func() float64 {
+			_, hits, _ := cacheStats()
+			return float64(hits)
+		})
+		factory.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Result cache lookups that missed or found a stale entry.",
+		}, func() float64 {
+			_, _, misses := cacheStats()
+			return float64(misses)
+		})
+	})
+}
+
+func cacheStats() (int, int64, int64) {
+	return (*cacheSource.Load())()
+}
+
+// RegisterCPU exposes the CPU overload monitor state.
+func RegisterCPU(snapshot func() resourceguard.CPUSnapshot) {
+	cpuSource.Store(&snapshot)
+	cpuOnce.Do(func() {
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cpu_usage_percent",
+			Help:      "System CPU usage last sampled by the overload monitor.",
+		}, func() float64 { return cpuSnapshot().UsagePct })
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cpu_overloaded",
+			Help:      "1 while CPU overload protection is active.",
+		}, func() float64 { return boolGauge(cpuSnapshot().Overloaded) })
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cpu_critical_overloaded",
+			Help:      "1 while CPU critical overload protection is active.",
+		}, func() float64 { return boolGauge(cpuSnapshot().CriticalOverloaded) })
+	})
+}
+
+func cpuSnapshot() resourceguard.CPUSnapshot {
+	return (*cpuSource.Load())()
+}
+
+// ObserveSearch records one completed, uncached search.
+func ObserveSearch(mode string, latency time.Duration, hits int, degradeReason string) {
+	SearchLatency.WithLabelValues(mode).Observe(latency.Seconds())
+	SearchHits.WithLabelValues(mode).Observe(float64(hits))
+	if degradeReason != "" {
+		SearchDegraded.WithLabelValues(degradeReason).Inc()
+	}
+}
+
+// Server is the optional HTTP listener serving /metrics.
+type Server struct {
+	http *http.Server
+	addr string
+	log  *slog.Logger
+}
+
+// Serve starts the metrics listener on addr.
+func Serve(addr string, logger *slog.Logger) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen metrics %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry}))
+
+	s := &Server{
+		http: &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		addr: ln.Addr().String(),
+		log:  logger,
+	}
+	go func() {
+		if err := s.http.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("metrics server stopped", "err", err)
+		}
+	}()
+	s.log.Info("metrics listening", "addr", s.addr)
+	return s, nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"qmdsr/internal/resourceguard"
+)
+
+func TestServe_ExposesRegisteredMetrics(t *testing.T) {
+	SearchLatency.Reset()
+	SearchHits.Reset()
+	SearchDegraded.Reset()
+	QMDInFlight.Reset()
+	// A second registration replaces the source instead of panicking.
+	RegisterCache(func() (int, int64, int64) { return 0, 0, 0 })
+	RegisterCache(func() (int, int64, int64) { return 3, 7, 2 })
+	RegisterCPU(func() resourceguard.CPUSnapshot {
+		return resourceguard.CPUSnapshot{Overloaded: true, UsagePct: 91.5}
+	})
+	ObserveSearch("search", 120*time.Millisecond, 4, "")
+	ObserveSearch("query", 2*time.Second, 0, "deep_failed_fallback_broad")
+	QMDInFlight.WithLabelValues("query").Inc()
+	defer QMDInFlight.WithLabelValues("query").Dec()
+
+	srv, err := Serve("127.0.0.1:0", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + srv.addr + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	for _, want := range []string{
+		`qmdsr_search_latency_seconds_count{mode="query"} 1`,
+		`qmdsr_search_hits_bucket{mode="search",le="8"} 1`,
+		`qmdsr_search_degraded_total{reason="deep_failed_fallback_broad"} 1`,
+		`qmdsr_cache_entries 3`,
+		`qmdsr_cache_hits_total 7`,
+		`qmdsr_cpu_overloaded 1`,
+		`qmdsr_cpu_usage_percent 91.5`,
+		`qmdsr_qmd_subprocesses_in_flight{command="query"} 1`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in scrape output:\n%s", want, text)
+		}
+	}
+}
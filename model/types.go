@@ -21,6 +21,24 @@ type SearchMeta struct {
 	DegradeReason       string   `json:"degrade_reason,omitempty"`
 	TraceID             string   `json:"trace_id,omitempty"`
 	LatencyMs           int64    `json:"latency_ms"`
+	// BM25Queries are the keyword queries a downgraded verbose query was
+	// rewritten into.
+	BM25Queries []string `json:"bm25_queries,omitempty"`
+	// Expansions are the synonym expansions of the BM25 queries, as
+	// "term->synonym,...".
+	Expansions []string `json:"expansions,omitempty"`
+	// Cascade lists the tiers a tier-wide search went through, in order.
+	Cascade []TierStep `json:"cascade,omitempty"`
+}
+
+// TierStep is one tier of a cascading search. FallThrough names the policy
+// that sent the search on to the next tier, empty for the last tier run.
+type TierStep struct {
+	Tier        int      `json:"tier"`
+	Collections []string `json:"collections"`
+	Hits        int      `json:"hits"`
+	TopScore    float64  `json:"top_score"`
+	FallThrough string   `json:"fall_through,omitempty"`
 }
 
 type SearchResponse struct {
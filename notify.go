@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"qmdsr/heartbeat"
+	"qmdsr/internal/sdnotify"
+	"qmdsr/model"
+)
+
+// notify sends state to systemd when running as a Type=notify service.
+func notify(logger *slog.Logger, state string) {
+	if _, err := sdnotify.Notify(state); err != nil {
+		logger.Warn("systemd notify failed", "state", state, "err", err)
+	}
+}
+
+// healthStatus is the STATUS= line shown by systemctl status.
+func healthStatus(h *model.SystemHealth) string {
+	var bad []string
+	for name, comp := range h.Components {
+		if comp.Level > model.Healthy {
+			bad = append(bad, name+" "+comp.LevelStr)
+		}
+	}
+	if len(bad) == 0 {
+		return h.OverallStr + ", mode " + h.Mode
+	}
+	slices.Sort(bad)
+	return fmt.Sprintf("%s, mode %s: %s", h.OverallStr, h.Mode, strings.Join(bad, ", "))
+}
+
+// watchdog pings the systemd watchdog only while the liveness probe through
+// the orchestrator succeeds, so systemd restarts a wedged process instead of
+// keeping it alive.
+func watchdog(ctx context.Context, probe func(context.Context) error, hb *heartbeat.Heartbeat, logger *slog.Logger) {
+	interval := sdnotify.WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := probeWithin(ctx, probe, interval/2); err != nil {
+			logger.Error("liveness probe failed, withholding watchdog ping", "err", err)
+			notify(logger, "STATUS=liveness probe failed: "+err.Error())
+			continue
+		}
+		notify(logger, "WATCHDOG=1\nSTATUS="+healthStatus(hb.GetHealth()))
+	}
+}
+
+// probeWithin fails a probe that has not returned after timeout, even one
+// stuck on a lock that ignores ctx.
+func probeWithin(ctx context.Context, probe func(context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- probe(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("no answer within %s", timeout)
+	}
+}
@@ -0,0 +1,208 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/internal/authz"
+	"qmdsr/model"
+)
+
+// authorizeCollection checks an explicitly requested collection against the
+// caller's token and the collection's own confirm requirement.
+func (o *Orchestrator) authorizeCollection(ctx context.Context, name string, confirm bool) error {
+	colCfg := o.findCollection(name)
+	if colCfg == nil {
+		return fmt.Errorf("collection %q not found", name)
+	}
+	return checkCollectionAccess(ctx, colCfg, confirm)
+}
+
+func checkCollectionAccess(ctx context.Context, col *config.CollectionCfg, confirm bool) error {
+	if p, ok := authz.FromContext(ctx); ok && !p.AllowsCollection(col.Name, col.Tier) {
+		return fmt.Errorf("%w: token %q may not access collection %q", authz.ErrPermissionDenied, p.Name, col.Name)
+	}
+	if requiresConfirm(col) && !confirm {
+		return fmt.Errorf("collection %q requires confirm=true", col.Name)
+	}
+	return nil
+}
+
+func requiresConfirm(col *config.CollectionCfg) bool {
+	return col.RequireExplicit && col.SafetyPrompt
+}
+
+func (o *Orchestrator) hasConfirmProtectedCollections() bool {
+	cfg := o.config()
+	for i := range cfg.Collections {
+		if requiresConfirm(&cfg.Collections[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// permittedCollections drops collections the caller's token may not search.
+func (o *Orchestrator) permittedCollections(ctx context.Context, cols []config.CollectionCfg) []config.CollectionCfg {
+	p, ok := authz.FromContext(ctx)
+	if !ok {
+		return cols
+	}
+	out := make([]config.CollectionCfg, 0, len(cols))
+	for _, c := range cols {
+		if p.AllowsCollection(c.Name, c.Tier) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ImplicitCollections keeps the collections a search may reach without the
+// caller naming them, e.g. those picked by a routing rule. Like the default
+// tier scope it skips require_explicit collections and those the caller's
+// token may not search.
+func (o *Orchestrator) ImplicitCollections(ctx context.Context, names []string) []string {
+	var cols []config.CollectionCfg
+	for _, name := range names {
+		if col := o.findCollection(name); col != nil && !col.RequireExplicit {
+			cols = append(cols, *col)
+		}
+	}
+	cols = o.permittedCollections(ctx, cols)
+	out := make([]string, len(cols))
+	for i, col := range cols {
+		out[i] = col.Name
+	}
+	return out
+}
+
+// scopedCacheKey keeps cached tier-wide results from leaking between tokens
+// with different collection sets.
+func scopedCacheKey(ctx context.Context, key string) string {
+	if p, ok := authz.FromContext(ctx); ok {
+		return key + "|principal=" + p.Name
+	}
+	return key
+}
+
+// resolveDocCollection maps a document reference or glob pattern to the
+// configured collection it lives in. It understands qmd://<collection>/...
+// URIs, absolute paths under a collection path and "<collection>/..."
+// relative paths; "." and ".." segments are resolved first, so
+// qmd://notes/../personal/x.md belongs to personal. Docids and bare file
+// names resolve to nil.
+func (o *Orchestrator) resolveDocCollection(ref string) *config.CollectionCfg {
+	cfg := o.config()
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil
+	}
+
+	if rest, ok := strings.CutPrefix(ref, "qmd://"); ok {
+		name, _, _ := strings.Cut(path.Clean(rest), "/")
+		return o.findCollection(name)
+	}
+
+	if filepath.IsAbs(ref) {
+		clean := filepath.Clean(ref)
+		var best *config.CollectionCfg
+		for i := range cfg.Collections {
+			col := &cfg.Collections[i]
+			if col.Path == "" {
+				continue
+			}
+			if clean != col.Path && !strings.HasPrefix(clean, strings.TrimSuffix(col.Path, "/")+"/") {
+				continue
+			}
+			if best == nil || len(col.Path) > len(best.Path) {
+				best = col
+			}
+		}
+		return best
+	}
+
+	if name, _, ok := strings.Cut(path.Clean(filepath.ToSlash(ref)), "/"); ok {
+		return o.findCollection(name)
+	}
+	return nil
+}
+
+// escapesRoot reports whether a qmd:// or relative reference climbs above
+// its root, e.g. qmd://notes/../../etc/passwd.
+func escapesRoot(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	rest, ok := strings.CutPrefix(ref, "qmd://")
+	if !ok {
+		if filepath.IsAbs(ref) {
+			return false
+		}
+		rest = filepath.ToSlash(ref)
+	}
+	clean := path.Clean(rest)
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+// authorizeDocRef applies the search rules to a single document reference.
+// A reference whose collection cannot be resolved (docid, bare file name) may
+// live in a protected collection, so it needs confirm=true whenever one is
+// configured, and is refused outright for collection-restricted tokens.
+func (o *Orchestrator) authorizeDocRef(ctx context.Context, ref string, confirm bool) error {
+	if escapesRoot(ref) {
+		return fmt.Errorf("%w: document %q escapes its collection", authz.ErrPermissionDenied, ref)
+	}
+	col := o.resolveDocCollection(ref)
+	if col != nil {
+		return checkCollectionAccess(ctx, col, confirm)
+	}
+	if p, ok := authz.FromContext(ctx); ok && p.Restricted() {
+		return fmt.Errorf("%w: token %q may not read %q outside a known collection", authz.ErrPermissionDenied, p.Name, ref)
+	}
+	if !confirm && o.hasConfirmProtectedCollections() {
+		return fmt.Errorf("document %q has no known collection and requires confirm=true", ref)
+	}
+	return nil
+}
+
+func (o *Orchestrator) Get(ctx context.Context, docRef string, opts executor.GetOpts, confirm bool) (string, error) {
+	if err := o.authorizeDocRef(ctx, docRef, confirm); err != nil {
+		return "", err
+	}
+	return o.exec.Get(ctx, docRef, opts)
+}
+
+// MultiGet rejects patterns aimed at a forbidden or unconfirmed collection and
+// filters the remaining documents one by one, since a glob can span several
+// collections.
+func (o *Orchestrator) MultiGet(ctx context.Context, pattern string, maxBytes int, confirm bool) ([]model.Document, error) {
+	if escapesRoot(pattern) {
+		return nil, fmt.Errorf("%w: pattern %q escapes its collection", authz.ErrPermissionDenied, pattern)
+	}
+	if col := o.resolveDocCollection(pattern); col != nil {
+		if err := checkCollectionAccess(ctx, col, confirm); err != nil {
+			return nil, err
+		}
+	}
+
+	docs, err := o.exec.MultiGet(ctx, pattern, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]model.Document, 0, len(docs))
+	for _, doc := range docs {
+		if err := o.authorizeDocRef(ctx, doc.File, confirm); err != nil {
+			o.log.Debug("multi_get dropped document", "file", doc.File, "err", err)
+			continue
+		}
+		allowed = append(allowed, doc)
+	}
+	if dropped := len(docs) - len(allowed); dropped > 0 {
+		o.log.Info("multi_get filtered protected documents", "pattern", pattern, "dropped", dropped)
+	}
+	return allowed, nil
+}
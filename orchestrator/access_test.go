@@ -0,0 +1,238 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/internal/authz"
+	"qmdsr/model"
+)
+
+type fakeAccessExec struct {
+	*fakeEnsureExec
+
+	mu        sync.Mutex
+	searched  []string
+	gets      []string
+	multiDocs []model.Document
+}
+
+func (f *fakeAccessExec) Search(_ context.Context, _ string, opts executor.SearchOpts) ([]model.SearchResult, error) {
+	f.mu.Lock()
+	f.searched = append(f.searched, opts.Collection)
+	f.mu.Unlock()
+	return []model.SearchResult{{File: "qmd://" + opts.Collection + "/hit.md", Collection: opts.Collection, Score: 0.9}}, nil
+}
+
+func (f *fakeAccessExec) Get(_ context.Context, ref string, _ executor.GetOpts) (string, error) {
+	f.mu.Lock()
+	f.gets = append(f.gets, ref)
+	f.mu.Unlock()
+	return "content", nil
+}
+
+func (f *fakeAccessExec) MultiGet(context.Context, string, int) ([]model.Document, error) {
+	return f.multiDocs, nil
+}
+
+func newAccessTestOrchestrator(exec *fakeAccessExec) *Orchestrator {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/data/notes", Tier: 1},
+			{Name: "personal", Path: "/data/personal", Tier: 99, RequireExplicit: true, SafetyPrompt: true},
+		},
+		Search: config.SearchConfig{TopK: 5},
+	}
+	return New(cfg, exec, nil, testLogger())
+}
+
+func helperCtx() context.Context {
+	return authz.WithPrincipal(context.Background(), &authz.Principal{Name: "helper", Tiers: []int{1, 2}})
+}
+
+func TestSearch_TokenCannotSearchForbiddenCollection(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	o := newAccessTestOrchestrator(exec)
+
+	_, err := o.Search(helperCtx(), SearchParams{Query: "diary", Mode: "search", Collection: "personal", Confirm: true})
+	if !errors.Is(err, authz.ErrPermissionDenied) {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+	if len(exec.searched) != 0 {
+		t.Fatalf("did not expect qmd search, got %v", exec.searched)
+	}
+}
+
+func TestSearch_TierWideResultsScopedPerToken(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/data/notes", Tier: 1},
+			{Name: "work", Path: "/data/work", Tier: 1},
+		},
+		Search: config.SearchConfig{TopK: 5},
+		Cache:  config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10},
+	}
+	o := New(cfg, exec, nil, testLogger())
+
+	owner := authz.WithPrincipal(context.Background(), &authz.Principal{Name: "owner"})
+	res, err := o.Search(owner, SearchParams{Query: "plan", Mode: "search"})
+	if err != nil || len(res.Results) != 2 {
+		t.Fatalf("expected owner to see both collections, got %+v err=%v", res, err)
+	}
+
+	notesOnly := authz.WithPrincipal(context.Background(), &authz.Principal{Name: "notes-only", Collections: []string{"notes"}})
+	res, err = o.Search(notesOnly, SearchParams{Query: "plan", Mode: "search"})
+	if err != nil {
+		t.Fatalf("restricted search failed: %v", err)
+	}
+	if res.Meta.CacheHit || len(res.Results) != 1 || res.Results[0].Collection != "notes" {
+		t.Fatalf("expected uncached notes-only results, got %+v", res)
+	}
+}
+
+func TestGet_TokenRestrictedByCollection(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	o := newAccessTestOrchestrator(exec)
+
+	for _, ref := range []string{"qmd://personal/diary.md", "/data/personal/diary.md", "personal/diary.md", "#abc123"} {
+		if _, err := o.Get(helperCtx(), ref, executor.GetOpts{}, true); !errors.Is(err, authz.ErrPermissionDenied) {
+			t.Fatalf("expected permission denied for %q, got %v", ref, err)
+		}
+	}
+	if _, err := o.Get(helperCtx(), "qmd://notes/todo.md", executor.GetOpts{}, true); err != nil {
+		t.Fatalf("expected permitted get, got %v", err)
+	}
+	if len(exec.gets) != 1 {
+		t.Fatalf("expected exactly one executor get, got %v", exec.gets)
+	}
+}
+
+func TestMultiGet_FiltersForbiddenDocuments(t *testing.T) {
+	exec := &fakeAccessExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		multiDocs: []model.Document{
+			{File: "qmd://notes/a.md", Content: "A"},
+			{File: "qmd://personal/b.md", Content: "B"},
+		},
+	}
+	o := newAccessTestOrchestrator(exec)
+
+	docs, err := o.MultiGet(helperCtx(), "*/*.md", 0, true)
+	if err != nil {
+		t.Fatalf("MultiGet failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].File != "qmd://notes/a.md" {
+		t.Fatalf("expected only permitted document, got %+v", docs)
+	}
+
+	if _, err := o.MultiGet(helperCtx(), "personal/*.md", 0, true); !errors.Is(err, authz.ErrPermissionDenied) {
+		t.Fatalf("expected pattern aimed at forbidden collection to be rejected, got %v", err)
+	}
+}
+
+func TestGet_ProtectedCollectionRequiresConfirm(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	o := newAccessTestOrchestrator(exec)
+	ctx := context.Background()
+
+	refs := []string{
+		"qmd://personal/diary.md",
+		"/data/personal/2024/diary.md",
+		"personal/diary.md",
+		"#abc123",
+	}
+	for _, ref := range refs {
+		_, err := o.Get(ctx, ref, executor.GetOpts{}, false)
+		if err == nil || !strings.Contains(err.Error(), "requires confirm=true") {
+			t.Fatalf("expected confirm requirement for %q, got %v", ref, err)
+		}
+	}
+	if len(exec.gets) != 0 {
+		t.Fatalf("did not expect executor gets without confirm, got %v", exec.gets)
+	}
+
+	for _, ref := range refs {
+		if _, err := o.Get(ctx, ref, executor.GetOpts{}, true); err != nil {
+			t.Fatalf("expected confirmed get of %q to pass, got %v", ref, err)
+		}
+	}
+	if _, err := o.Get(ctx, "/data/notes/todo.md", executor.GetOpts{}, false); err != nil {
+		t.Fatalf("expected unprotected absolute path to pass, got %v", err)
+	}
+}
+
+func TestGet_DocidAllowedWithoutProtectedCollections(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	cfg := &config.Config{Collections: []config.CollectionCfg{{Name: "notes", Path: "/data/notes", Tier: 1}}}
+	o := New(cfg, exec, nil, testLogger())
+
+	if _, err := o.Get(context.Background(), "#abc123", executor.GetOpts{}, false); err != nil {
+		t.Fatalf("expected docid get to pass, got %v", err)
+	}
+}
+
+func TestMultiGet_ProtectedDocumentsNeedConfirm(t *testing.T) {
+	exec := &fakeAccessExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		multiDocs: []model.Document{
+			{File: "qmd://notes/a.md", Content: "A"},
+			{File: "qmd://personal/b.md", Content: "B"},
+			{File: "/data/personal/c.md", Content: "C"},
+		},
+	}
+	o := newAccessTestOrchestrator(exec)
+	ctx := context.Background()
+
+	for _, pattern := range []string{"personal/*.md", "qmd://personal/**/*.md", "/data/personal/*.md"} {
+		_, err := o.MultiGet(ctx, pattern, 0, false)
+		if err == nil || !strings.Contains(err.Error(), "requires confirm=true") {
+			t.Fatalf("expected confirm requirement for pattern %q, got %v", pattern, err)
+		}
+	}
+
+	docs, err := o.MultiGet(ctx, "**/*.md", 0, false)
+	if err != nil {
+		t.Fatalf("MultiGet failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].File != "qmd://notes/a.md" {
+		t.Fatalf("expected protected documents filtered, got %+v", docs)
+	}
+
+	docs, err = o.MultiGet(ctx, "**/*.md", 0, true)
+	if err != nil || len(docs) != 3 {
+		t.Fatalf("expected all documents with confirm, got %+v err=%v", docs, err)
+	}
+}
+
+func TestGet_PathTraversalResolvesTargetCollection(t *testing.T) {
+	exec := &fakeAccessExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	o := newAccessTestOrchestrator(exec)
+	notesOnly := authz.WithPrincipal(context.Background(), &authz.Principal{Name: "notes-only", Collections: []string{"notes"}})
+
+	for _, ref := range []string{"qmd://notes/../personal/diary.md", "notes/./../personal/diary.md"} {
+		if _, err := o.Get(notesOnly, ref, executor.GetOpts{}, true); !errors.Is(err, authz.ErrPermissionDenied) {
+			t.Errorf("expected permission denied for %q, got %v", ref, err)
+		}
+		if _, err := o.Get(context.Background(), ref, executor.GetOpts{}, false); err == nil || !strings.Contains(err.Error(), "requires confirm=true") {
+			t.Errorf("expected confirm requirement for %q, got %v", ref, err)
+		}
+	}
+	for _, ref := range []string{"qmd://notes/../../etc/passwd", "../personal/diary.md"} {
+		if _, err := o.Get(context.Background(), ref, executor.GetOpts{}, true); !errors.Is(err, authz.ErrPermissionDenied) {
+			t.Errorf("expected %q escaping its collection to be rejected, got %v", ref, err)
+		}
+	}
+	if _, err := o.MultiGet(notesOnly, "qmd://notes/../personal/*.md", 0, true); !errors.Is(err, authz.ErrPermissionDenied) {
+		t.Errorf("expected traversal pattern to be rejected, got %v", err)
+	}
+	if len(exec.gets) != 0 {
+		t.Fatalf("did not expect executor gets, got %v", exec.gets)
+	}
+}
@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/model"
+)
+
+type fakeTierExec struct {
+	*fakeEnsureExec
+	hits map[string]bool
+}
+
+func (f *fakeTierExec) Search(_ context.Context, _ string, opts executor.SearchOpts) ([]model.SearchResult, error) {
+	if !f.hits[opts.Collection] {
+		return nil, nil
+	}
+	return []model.SearchResult{{File: "qmd://" + opts.Collection + "/hit.md", Collection: opts.Collection, Score: 0.9}}, nil
+}
+
+func newTierCacheTest(hits map[string]bool) (*Orchestrator, *cache.Cache) {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Path: "/data/notes", Tier: 1},
+			{Name: "memory", Path: "/data/memory", Tier: 1},
+			{Name: "archive", Path: "/data/archive", Tier: 2},
+		},
+		Search: config.SearchConfig{TopK: 5, FallbackEnabled: true},
+		Cache:  config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true},
+	}
+	c := cache.New(&cfg.Cache)
+	exec := &fakeTierExec{fakeEnsureExec: newFakeEnsureExec(nil, nil), hits: hits}
+	return New(cfg, exec, c, testLogger()), c
+}
+
+func searchCached(t *testing.T, o *Orchestrator) bool {
+	t.Helper()
+	res, err := o.Search(context.Background(), SearchParams{Query: "plan", Mode: "search", Fallback: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	return res.Meta.CacheHit
+}
+
+func TestSearchCache_TierFallbackDependsOnBothTiers(t *testing.T) {
+	o, c := newTierCacheTest(map[string]bool{"archive": true})
+
+	searchCached(t, o)
+	if !searchCached(t, o) {
+		t.Fatalf("expected second search to hit cache")
+	}
+
+	c.InvalidateCollections([]string{"memory"})
+	if searchCached(t, o) {
+		t.Fatalf("tier-2 answer must be invalidated when an empty tier-1 collection changes")
+	}
+
+	c.InvalidateCollections([]string{"archive"})
+	if searchCached(t, o) {
+		t.Fatalf("tier-2 answer must be invalidated when tier 2 changes")
+	}
+}
+
+func TestSearchCache_Tier1AnswerSurvivesTier2Reindex(t *testing.T) {
+	o, c := newTierCacheTest(map[string]bool{"notes": true, "archive": true})
+
+	searchCached(t, o)
+	c.InvalidateCollections([]string{"archive"})
+	if !searchCached(t, o) {
+		t.Fatalf("tier-1 answer should survive a tier-2 reindex")
+	}
+
+	c.InvalidateCollections([]string{"memory"})
+	if searchCached(t, o) {
+		t.Fatalf("tier-1 answer must be invalidated when any tier-1 collection changes")
+	}
+}
+
+func TestApplyConfig_SearchUsesReloadedCollections(t *testing.T) {
+	o, _ := newTierCacheTest(map[string]bool{"archive": true})
+
+	next := *o.config()
+	next.Collections = []config.CollectionCfg{{Name: "archive", Path: "/data/archive", Tier: 1}}
+	next.Cache.Enabled = false
+	o.ApplyConfig(&next)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "plan", Mode: "search"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if res.Meta.FallbackTriggered || len(res.Results) != 1 || res.Results[0].Collection != "archive" {
+		t.Fatalf("expected tier-1 hit from reloaded collection, got %+v", res)
+	}
+}
+
+func TestSearchCache_CascadeAnswerDependsOnEveryTierRun(t *testing.T) {
+	o, c := newTierCacheTest(map[string]bool{"journal": true})
+	next := *o.config()
+	next.Collections = append(slices.Clone(next.Collections), config.CollectionCfg{Name: "journal", Path: "/data/journal", Tier: 3})
+	o.ApplyConfig(&next)
+
+	searchCached(t, o)
+	c.InvalidateCollections([]string{"journal"})
+	if searchCached(t, o) {
+		t.Fatalf("tier-3 answer must be invalidated when tier 3 changes")
+	}
+
+	searchCached(t, o)
+	c.InvalidateCollections([]string{"archive"})
+	if searchCached(t, o) {
+		t.Fatalf("tier-3 answer must be invalidated when an empty tier-2 collection changes")
+	}
+}
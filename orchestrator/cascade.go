@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"slices"
+
+	"qmdsr/config"
+	"qmdsr/model"
+	"qmdsr/router"
+)
+
+// cascadeResult is the outcome of a tier cascade: the merged hits of every
+// tier run, the collections that answered and one step per tier.
+type cascadeResult struct {
+	results  []model.SearchResult
+	searched []string
+	steps    []model.TierStep
+}
+
+func (c cascadeResult) fallbackTriggered() bool {
+	return len(c.steps) > 1
+}
+
+// cascade returns the tier policies of tier-wide searches. Without a
+// configured cascade every tier below 99 is searched in ascending order,
+// each falling through only when it has no hits.
+func (o *Orchestrator) cascade() []config.TierPolicy {
+	cfg := o.config()
+	if len(cfg.Search.Cascade) > 0 {
+		return cfg.Search.Cascade
+	}
+	var tiers []int
+	for _, col := range cfg.Collections {
+		if col.Tier < 99 && !col.RequireExplicit && !slices.Contains(tiers, col.Tier) {
+			tiers = append(tiers, col.Tier)
+		}
+	}
+	slices.Sort(tiers)
+	policies := make([]config.TierPolicy, len(tiers))
+	for i, tier := range tiers {
+		policies[i] = config.TierPolicy{Tier: tier}
+	}
+	return policies
+}
+
+// searchCascade searches the tiers of the cascade in order until a tier's
+// policy is satisfied. Tiers without permitted collections are skipped, and
+// only the first tier runs when fallback is off.
+func (o *Orchestrator) searchCascade(ctx context.Context, params SearchParams, mode router.Mode, logMsg string) cascadeResult {
+	var out cascadeResult
+	fallback := params.Fallback && o.config().Search.FallbackEnabled
+	for _, policy := range o.cascade() {
+		if n := len(out.steps); n > 0 && (!fallback || out.steps[n-1].FallThrough == "") {
+			break
+		}
+		cols := o.permittedCollections(ctx, o.collectionsByTier(policy.Tier))
+		if len(cols) == 0 {
+			continue
+		}
+		results, searched, _ := o.searchTierParallel(ctx, cols, mode, params, logMsg)
+		hits := o.filterMinScore(results, minScoreForMode(mode, params.MinScore))
+
+		step := model.TierStep{
+			Tier:        policy.Tier,
+			Hits:        len(hits),
+			TopScore:    topScore(hits),
+			FallThrough: fallThrough(policy, hits),
+		}
+		for _, col := range cols {
+			step.Collections = append(step.Collections, col.Name)
+		}
+		out.steps = append(out.steps, step)
+		out.results = append(out.results, scaleScores(hits, policy.ScoreMultiplier)...)
+		out.searched = append(out.searched, searched...)
+
+		stage := StageTier1
+		if out.fallbackTriggered() {
+			stage = StageTier2
+		}
+		o.emitPartial(params, stage, out.results, mode, out.searched, out.fallbackTriggered())
+	}
+	return out
+}
+
+// fallThrough names the policy that calls for the next tier after a tier
+// returned hits, or returns "" when the hits suffice.
+func fallThrough(policy config.TierPolicy, hits []model.SearchResult) string {
+	switch {
+	case policy.Always:
+		return "always"
+	case len(hits) < max(policy.MinHits, 1):
+		return "min_hits"
+	case policy.MinTopScore > 0 && topScore(hits) < policy.MinTopScore:
+		return "min_top_score"
+	}
+	return ""
+}
+
+func topScore(results []model.SearchResult) float64 {
+	top := 0.0
+	for _, r := range results {
+		top = max(top, r.Score)
+	}
+	return top
+}
+
+// scaleScores returns a copy of results with every score multiplied by m.
+// Non-positive multipliers count as 1.
+func scaleScores(results []model.SearchResult, m float64) []model.SearchResult {
+	if m <= 0 || m == 1 {
+		return results
+	}
+	scaled := make([]model.SearchResult, len(results))
+	for i, r := range results {
+		r.Score *= m
+		scaled[i] = r
+	}
+	return scaled
+}
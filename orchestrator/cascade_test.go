@@ -0,0 +1,118 @@
+package orchestrator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/model"
+)
+
+type fakeCascadeExec struct {
+	*fakeEnsureExec
+	scores map[string]float64
+
+	mu       sync.Mutex
+	searched []string
+}
+
+func (f *fakeCascadeExec) Search(_ context.Context, _ string, opts executor.SearchOpts) ([]model.SearchResult, error) {
+	f.mu.Lock()
+	f.searched = append(f.searched, opts.Collection)
+	f.mu.Unlock()
+	score, ok := f.scores[opts.Collection]
+	if !ok {
+		return nil, nil
+	}
+	return []model.SearchResult{{File: "qmd://" + opts.Collection + "/hit.md", Collection: opts.Collection, Score: score}}, nil
+}
+
+func newCascadeTestOrchestrator(cascade []config.TierPolicy, scores map[string]float64) (*Orchestrator, *fakeCascadeExec) {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "memory", Path: "/data/memory", Tier: 1},
+			{Name: "notes", Path: "/data/notes", Tier: 2},
+			{Name: "archive", Path: "/data/archive", Tier: 3},
+			{Name: "personal", Path: "/data/personal", Tier: 99},
+		},
+		Search: config.SearchConfig{TopK: 5, MinScore: 0.3, FallbackEnabled: true, Cascade: cascade},
+	}
+	exec := &fakeCascadeExec{fakeEnsureExec: newFakeEnsureExec(nil, nil), scores: scores}
+	return New(cfg, exec, nil, testLogger()), exec
+}
+
+func TestSearch_DefaultCascadeReachesEveryTierBelow99(t *testing.T) {
+	o, exec := newCascadeTestOrchestrator(nil, map[string]float64{"archive": 0.8, "personal": 0.9})
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "plan", Mode: "search", Fallback: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if want := []string{"memory", "notes", "archive"}; !reflect.DeepEqual(exec.searched, want) {
+		t.Fatalf("searched %v, want %v", exec.searched, want)
+	}
+	if len(res.Results) != 1 || res.Results[0].Collection != "archive" {
+		t.Errorf("expected the tier-3 hit, got %+v", res.Results)
+	}
+	want := []model.TierStep{
+		{Tier: 1, Collections: []string{"memory"}, FallThrough: "min_hits"},
+		{Tier: 2, Collections: []string{"notes"}, FallThrough: "min_hits"},
+		{Tier: 3, Collections: []string{"archive"}, Hits: 1, TopScore: 0.8},
+	}
+	if !reflect.DeepEqual(res.Meta.Cascade, want) {
+		t.Errorf("cascade = %+v, want %+v", res.Meta.Cascade, want)
+	}
+	if !res.Meta.FallbackTriggered || !reflect.DeepEqual(res.Meta.CollectionsSearched, []string{"memory", "notes", "archive"}) {
+		t.Errorf("unexpected meta %+v", res.Meta)
+	}
+}
+
+func TestSearch_CascadePoliciesMergeScaledTiers(t *testing.T) {
+	cascade := []config.TierPolicy{
+		{Tier: 1, MinTopScore: 0.6},
+		{Tier: 2, Always: true, ScoreMultiplier: 0.5},
+		{Tier: 3},
+	}
+	o, _ := newCascadeTestOrchestrator(cascade, map[string]float64{"memory": 0.5, "notes": 0.9, "archive": 0.4})
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "plan", Mode: "search", Fallback: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	var got []string
+	for _, r := range res.Results {
+		got = append(got, r.Collection)
+	}
+	if want := []string{"memory", "notes", "archive"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged order = %v, want %v", got, want)
+	}
+	if len(res.Results) == 3 && res.Results[1].Score != 0.45 {
+		t.Errorf("tier-2 score = %v, want 0.45 after its multiplier", res.Results[1].Score)
+	}
+	var fallThrough []string
+	for _, step := range res.Meta.Cascade {
+		fallThrough = append(fallThrough, step.FallThrough)
+	}
+	if want := []string{"min_top_score", "always", ""}; !reflect.DeepEqual(fallThrough, want) {
+		t.Errorf("fall-through reasons = %q, want %q", fallThrough, want)
+	}
+}
+
+func TestSearch_CascadeStopsAtFirstTierWithoutFallback(t *testing.T) {
+	cascade := []config.TierPolicy{{Tier: 2, MinHits: 2}, {Tier: 1}}
+	o, exec := newCascadeTestOrchestrator(cascade, map[string]float64{"notes": 0.9, "memory": 0.9})
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "plan", Mode: "search"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if !reflect.DeepEqual(exec.searched, []string{"notes"}) || res.Meta.FallbackTriggered {
+		t.Fatalf("searched %v (fallback %t), want only the first cascade tier", exec.searched, res.Meta.FallbackTriggered)
+	}
+	if len(res.Meta.Cascade) != 1 || res.Meta.Cascade[0].FallThrough != "min_hits" {
+		t.Errorf("cascade = %+v, want tier 2 asking for more hits", res.Meta.Cascade)
+	}
+}
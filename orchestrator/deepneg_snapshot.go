@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"time"
+
+	"qmdsr/internal/snapshot"
+)
+
+const (
+	deepNegSnapshotKind    = "deep_negative_cache"
+	deepNegSnapshotVersion = 1
+)
+
+type deepNegSnapshot struct {
+	Expiries   map[string]time.Time   `json:"expiries"`
+	ScopeFails map[string][]time.Time `json:"scope_fails"`
+}
+
+// SaveDeepNegativeCache snapshots unexpired deep negative markers and the
+// scope failure windows that feed the scope cooldown.
+func (o *Orchestrator) SaveDeepNegativeCache(path string) (int, error) {
+	now := time.Now()
+	snap := deepNegSnapshot{
+		Expiries:   make(map[string]time.Time),
+		ScopeFails: make(map[string][]time.Time),
+	}
+
+	o.deepNegMu.Lock()
+	for k, expiry := range o.deepNeg {
+		if expiry.After(now) {
+			snap.Expiries[k] = expiry
+		}
+	}
+	for scope, fails := range o.deepNegScopeFails {
+		if recent := recentDeepNegFailures(fails, now); len(recent) > 0 {
+			snap.ScopeFails[scope] = recent
+		}
+	}
+	o.deepNegMu.Unlock()
+
+	if err := snapshot.Write(path, deepNegSnapshotKind, deepNegSnapshotVersion, snap); err != nil {
+		return 0, err
+	}
+	return len(snap.Expiries), nil
+}
+
+// LoadDeepNegativeCache restores markers saved by SaveDeepNegativeCache,
+// dropping those that expired while the service was down.
+func (o *Orchestrator) LoadDeepNegativeCache(path string) (int, error) {
+	var snap deepNegSnapshot
+	if _, err := snapshot.Read(path, deepNegSnapshotKind, deepNegSnapshotVersion, &snap); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	o.deepNegMu.Lock()
+	defer o.deepNegMu.Unlock()
+
+	loaded := 0
+	for k, expiry := range snap.Expiries {
+		if !expiry.After(now) {
+			continue
+		}
+		if cur, ok := o.deepNeg[k]; ok && cur.After(expiry) {
+			continue
+		}
+		o.deepNeg[k] = expiry
+		loaded++
+	}
+	for scope, fails := range snap.ScopeFails {
+		if recent := recentDeepNegFailures(fails, now); len(recent) > 0 {
+			o.deepNegScopeFails[scope] = append(recent, o.deepNegScopeFails[scope]...)
+		}
+	}
+	return loaded, nil
+}
+
+func recentDeepNegFailures(fails []time.Time, now time.Time) []time.Time {
+	var out []time.Time
+	for _, ts := range fails {
+		if now.Sub(ts) <= deepNegativeScopeFailWindow {
+			out = append(out, ts)
+		}
+	}
+	return out
+}
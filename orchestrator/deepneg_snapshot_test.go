@@ -0,0 +1,32 @@
+package orchestrator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+)
+
+func TestDeepNegativeCache_SnapshotRoundTrip(t *testing.T) {
+	cfg := &config.Config{Runtime: config.RuntimeConfig{DeepNegativeTTL: time.Hour}}
+	path := filepath.Join(t.TempDir(), "deep_negative_cache.json")
+
+	o := New(cfg, newFakeEnsureExec(nil, nil), nil, testLogger())
+	o.markDeepNegative("slow abstract question", "all")
+	o.deepNegMu.Lock()
+	o.deepNeg["expired"] = time.Now().Add(-time.Minute)
+	o.deepNegMu.Unlock()
+
+	if n, err := o.SaveDeepNegativeCache(path); err != nil || n != 1 {
+		t.Fatalf("SaveDeepNegativeCache = %d, %v; want 1", n, err)
+	}
+
+	restored := New(cfg, newFakeEnsureExec(nil, nil), nil, testLogger())
+	if n, err := restored.LoadDeepNegativeCache(path); err != nil || n != 1 {
+		t.Fatalf("LoadDeepNegativeCache = %d, %v; want 1", n, err)
+	}
+	if skip, _ := restored.shouldSkipDeepByNegativeCache("slow abstract question", "all"); !skip {
+		t.Fatalf("expected restored negative marker to skip deep query")
+	}
+}
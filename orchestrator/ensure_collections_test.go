@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"slices"
 	"testing"
 
 	"qmdsr/config"
@@ -15,9 +16,10 @@ type fakeEnsureExec struct {
 	collections []model.CollectionInfo
 	contexts    map[string]string
 
-	collectionAddCalls int
-	contextAddCalls    []model.PathContext
-	contextRemoveCalls []string
+	collectionAddCalls    int
+	collectionRemoveCalls []string
+	contextAddCalls       []model.PathContext
+	contextRemoveCalls    []string
 }
 
 func newFakeEnsureExec(existingCollections []string, contexts map[string]string) *fakeEnsureExec {
@@ -65,6 +67,12 @@ func (f *fakeEnsureExec) CollectionAdd(_ context.Context, path, name, mask strin
 	return nil
 }
 
+func (f *fakeEnsureExec) CollectionRemove(_ context.Context, name string) error {
+	f.collectionRemoveCalls = append(f.collectionRemoveCalls, name)
+	f.collections = slices.DeleteFunc(f.collections, func(c model.CollectionInfo) bool { return c.Name == name })
+	return nil
+}
+
 func (f *fakeEnsureExec) CollectionList(context.Context) ([]model.CollectionInfo, error) {
 	return f.collections, nil
 }
@@ -73,6 +81,8 @@ func (f *fakeEnsureExec) Update(context.Context) error { return nil }
 
 func (f *fakeEnsureExec) Embed(context.Context, bool) error { return nil }
 
+func (f *fakeEnsureExec) EmbedCollection(context.Context, string, bool) error { return nil }
+
 func (f *fakeEnsureExec) ContextAdd(_ context.Context, path, description string) error {
 	f.contexts[path] = description
 	f.contextAddCalls = append(f.contextAddCalls, model.PathContext{Path: path, Description: description})
@@ -173,3 +183,24 @@ func TestEnsureCollections_DoesNotDoubleAddContextForNewCollection(t *testing.T)
 		t.Fatalf("unexpected context remove calls: %+v", fakeExec.contextRemoveCalls)
 	}
 }
+
+func TestUnregisterCollections_ReRegistersMovedCollection(t *testing.T) {
+	fakeExec := newFakeEnsureExec([]string{"alpha"}, map[string]string{"/a": "ctx-a"})
+	cfg := &config.Config{Collections: []config.CollectionCfg{{Name: "alpha", Path: "/a2", Context: "ctx-a", Tier: 1}}}
+	o := New(cfg, fakeExec, nil, testLogger())
+
+	old := config.CollectionCfg{Name: "alpha", Path: "/a", Context: "ctx-a", Tier: 1}
+	if err := o.UnregisterCollections(context.Background(), []config.CollectionCfg{old}); err != nil {
+		t.Fatalf("UnregisterCollections failed: %v", err)
+	}
+	if len(fakeExec.collectionRemoveCalls) != 1 || fakeExec.contexts["/a"] != "" {
+		t.Fatalf("expected collection and its context removed, got removes=%v contexts=%v", fakeExec.collectionRemoveCalls, fakeExec.contexts)
+	}
+
+	if err := o.EnsureCollections(context.Background()); err != nil {
+		t.Fatalf("EnsureCollections failed: %v", err)
+	}
+	if fakeExec.collectionAddCalls != 1 || fakeExec.collections[0].Path != "/a2" {
+		t.Fatalf("expected alpha re-added at new path, got %+v", fakeExec.collections)
+	}
+}
@@ -0,0 +1,184 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/internal/segment"
+	"qmdsr/model"
+)
+
+type fakeHybridExec struct {
+	*fakeEnsureExec
+	vector       bool
+	bm25         []model.SearchResult
+	vec          []model.SearchResult
+	vecErr       error
+	searchCalls  int
+	vsearchCalls int
+	searchQuery  string
+	vsearchQuery string
+}
+
+func (f *fakeHybridExec) Search(_ context.Context, query string, _ executor.SearchOpts) ([]model.SearchResult, error) {
+	f.searchCalls++
+	f.searchQuery = query
+	return f.bm25, nil
+}
+
+func (f *fakeHybridExec) VSearch(_ context.Context, query string, _ executor.SearchOpts) ([]model.SearchResult, error) {
+	f.vsearchCalls++
+	f.vsearchQuery = query
+	return f.vec, f.vecErr
+}
+
+func (f *fakeHybridExec) HasCapability(cap string) bool {
+	return cap == "vector" && f.vector
+}
+
+func newHybridTestOrchestrator(exec *fakeHybridExec) *Orchestrator {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{Name: "notes", Path: "/notes", Tier: 1, Embed: true}},
+		Search: config.SearchConfig{
+			TopK:               10,
+			MinScore:           0.3,
+			HybridBM25Weight:   1,
+			HybridVectorWeight: 1,
+			HybridRRFK:         60,
+		},
+	}
+	return New(cfg, exec, nil, testLogger())
+}
+
+func TestSearch_HybridFusesBothLegs(t *testing.T) {
+	exec := &fakeHybridExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		vector:         true,
+		bm25: []model.SearchResult{
+			{File: "qmd://notes/keyword.md", Score: 0.9},
+			{File: "qmd://notes/both.md", Score: 0.5},
+		},
+		vec: []model.SearchResult{
+			{File: "qmd://notes/both.md", Score: 0.7},
+			{File: "qmd://notes/semantic.md", Score: 0.6},
+		},
+	}
+	o := newHybridTestOrchestrator(exec)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "traffic shaping", Mode: "hybrid", Collection: "notes"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if exec.searchCalls != 1 || exec.vsearchCalls != 1 {
+		t.Fatalf("expected one call per leg, got search=%d vsearch=%d", exec.searchCalls, exec.vsearchCalls)
+	}
+	if res.Meta.ModeUsed != "hybrid" {
+		t.Fatalf("expected hybrid mode, got %q", res.Meta.ModeUsed)
+	}
+	if len(res.Results) != 3 || res.Results[0].File != "qmd://notes/both.md" {
+		t.Fatalf("expected doc found by both legs first, got %+v", res.Results)
+	}
+}
+
+func TestSearch_HybridSurvivesVectorLegFailure(t *testing.T) {
+	exec := &fakeHybridExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		vector:         true,
+		bm25:           []model.SearchResult{{File: "qmd://notes/keyword.md", Score: 0.9}},
+		vecErr:         errors.New("vsearch failed"),
+	}
+	o := newHybridTestOrchestrator(exec)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "traffic shaping", Mode: "hybrid", Collection: "notes"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(res.Results) != 1 || res.Results[0].File != "qmd://notes/keyword.md" {
+		t.Fatalf("expected bm25 leg results, got %+v", res.Results)
+	}
+}
+
+func TestSearch_HybridFallsBackToSearchWithoutVector(t *testing.T) {
+	exec := &fakeHybridExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		bm25:           []model.SearchResult{{File: "qmd://notes/keyword.md", Score: 0.9}},
+	}
+	o := newHybridTestOrchestrator(exec)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "traffic shaping", Mode: "hybrid", Collection: "notes"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if res.Meta.ModeUsed != "search" || exec.vsearchCalls != 0 {
+		t.Fatalf("expected plain search without vector capability, got mode=%q vsearch=%d", res.Meta.ModeUsed, exec.vsearchCalls)
+	}
+}
+
+func TestSearch_VSearchDowngradesNonEmbeddedCollections(t *testing.T) {
+	exec := &fakeHybridExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		vector:         true,
+		bm25:           []model.SearchResult{{File: "qmd://archive/a.md", Score: 0.9}},
+		vec:            []model.SearchResult{{File: "qmd://notes/n.md", Score: 0.8}},
+	}
+	o := newHybridTestOrchestrator(exec)
+	cfg := *o.config()
+	cfg.Collections = append(cfg.Collections, config.CollectionCfg{Name: "archive", Path: "/archive", Tier: 1})
+	o.ApplyConfig(&cfg)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "traffic shaping", Mode: "vsearch"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if exec.vsearchCalls != 1 || exec.searchCalls != 1 {
+		t.Fatalf("expected vsearch on notes and BM25 on archive, got search=%d vsearch=%d", exec.searchCalls, exec.vsearchCalls)
+	}
+	if len(res.Results) != 2 {
+		t.Fatalf("expected hits from both collections, got %+v", res.Results)
+	}
+
+	exec.searchCalls, exec.vsearchCalls = 0, 0
+	if _, err := o.Search(context.Background(), SearchParams{Query: "traffic shaping", Mode: "vsearch", Collection: "archive"}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if exec.vsearchCalls != 0 || exec.searchCalls != 1 {
+		t.Fatalf("expected BM25 only for archive, got search=%d vsearch=%d", exec.searchCalls, exec.vsearchCalls)
+	}
+}
+
+func TestSearch_SegmentsOnlyTheBM25Query(t *testing.T) {
+	exec := &fakeHybridExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		vector:         true,
+		bm25:           []model.SearchResult{{File: "qmd://notes/shaping.md", Score: 0.9}},
+	}
+	o := newHybridTestOrchestrator(exec)
+	o.ApplyConfig(withUserWords(o.config(), segment.Dict{"流量整形": 20000}))
+
+	query := "如何配置软路由的流量整形"
+	if _, err := o.Search(context.Background(), SearchParams{Query: query, Mode: "hybrid", Collection: "notes"}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if exec.searchQuery != "如何 配置 软路由 流量整形" {
+		t.Errorf("bm25 query = %q, want the segmented query", exec.searchQuery)
+	}
+	if exec.vsearchQuery != query {
+		t.Errorf("vsearch query = %q, want the original query", exec.vsearchQuery)
+	}
+
+	cfg := *o.config()
+	cfg.Search.Segmenter = "none"
+	o.ApplyConfig(&cfg)
+	if got := o.BM25Query(query); got != query {
+		t.Errorf("BM25Query with segmenter none = %q, want the original query", got)
+	}
+}
+
+func withUserWords(cfg *config.Config, words segment.Dict) *config.Config {
+	next := *cfg
+	next.Search.UserWords = words
+	return &next
+}
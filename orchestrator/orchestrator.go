@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
-	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,39 +18,46 @@ import (
 	"qmdsr/cache"
 	"qmdsr/config"
 	"qmdsr/executor"
+	"qmdsr/internal/authz"
+	"qmdsr/internal/pathmatch"
 	"qmdsr/internal/resourceguard"
 	"qmdsr/internal/searchutil"
+	"qmdsr/internal/segment"
+	"qmdsr/internal/synonym"
 	"qmdsr/internal/textutil"
+	"qmdsr/metrics"
 	"qmdsr/model"
 	"qmdsr/router"
+	"qmdsr/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Orchestrator struct {
+	cfgMu      sync.RWMutex
 	cfg        *config.Config
 	exec       executor.Executor
 	cache      *cache.Cache
 	log        *slog.Logger
 	cpuMonitor *resourceguard.CPUMonitor
-
-	deepNegMu          sync.Mutex
-	deepNeg            map[string]time.Time
-	deepNegScopeFails  map[string][]time.Time
-	deepNegMarkCount   int64
-	deepNegExactHitCnt int64
-	deepNegScopeHitCnt int64
-
-	obsMu          sync.Mutex
-	obsCount       int64
-	obsLatencyMSum int64
-	obsHitsSum     int64
-	obsHitZero     int64
-	obsHitLow      int64
-	obsHitMid      int64
-	obsHitHigh     int64
-	obsDegraded    int64
-	obsLastLogAt   time.Time
+	// rules is replaced with cfg when the routing rules change.
+	rules *router.Router
+	// seg segments BM25 queries; nil when search.segmenter is none.
+	seg *segment.Segmenter
+
+	synMu sync.Mutex
+	// aliases holds the frontmatter aliases harvested per collection.
+	aliases map[string]synonym.Dict
+	// expanders caches the synonym expander of each collection; it is
+	// dropped whenever synonyms or aliases change.
+	expanders map[string]*synonym.Expander
+
+	deepNegMu         sync.Mutex
+	deepNeg           map[string]time.Time
+	deepNegScopeFails map[string][]time.Time
 
 	searchTokens chan struct{}
+	inflight     atomic.Int64
 }
 
 const maxSnippetCharsPerResult = 1500
@@ -66,13 +75,10 @@ func New(cfg *config.Config, exec executor.Executor, c *cache.Cache, logger *slo
 		log:               logger,
 		deepNeg:           make(map[string]time.Time),
 		deepNegScopeFails: make(map[string][]time.Time),
-		obsLastLogAt:      time.Now(),
 	}
-	maxConcurrentSearch := cfg.Runtime.OverloadMaxConcurrentSearch
-	if maxConcurrentSearch <= 0 {
-		maxConcurrentSearch = 2
-	}
-	o.searchTokens = make(chan struct{}, maxConcurrentSearch)
+	o.searchTokens = newSearchTokens(cfg)
+	o.rules = newRouter(cfg, logger)
+	o.seg = newSegmenter(cfg)
 	o.cpuMonitor = resourceguard.NewCPUMonitor(resourceguard.CPUMonitorConfig{
 		Enabled:         cfg.Runtime.CPUOverloadProtect,
 		SampleInterval:  cfg.Runtime.CPUSampleInterval,
@@ -86,6 +92,86 @@ func New(cfg *config.Config, exec executor.Executor, c *cache.Cache, logger *slo
 	return o
 }
 
+func newSearchTokens(cfg *config.Config) chan struct{} {
+	maxConcurrentSearch := cfg.Runtime.OverloadMaxConcurrentSearch
+	if maxConcurrentSearch <= 0 {
+		maxConcurrentSearch = 2
+	}
+	return make(chan struct{}, maxConcurrentSearch)
+}
+
+func newRouter(cfg *config.Config, logger *slog.Logger) *router.Router {
+	r, err := router.New(cfg.Routing.Rules)
+	if err != nil {
+		// Load validates the rules, so only hand-built configs end up here.
+		logger.Error("invalid routing rules, using built-in rules", "err", err)
+		r, _ = router.New(router.RuleSet{})
+	}
+	return r
+}
+
+func newSegmenter(cfg *config.Config) *segment.Segmenter {
+	if cfg.Search.Segmenter == "none" {
+		return nil
+	}
+	return segment.New(cfg.Search.UserWords)
+}
+
+func (o *Orchestrator) config() *config.Config {
+	o.cfgMu.RLock()
+	defer o.cfgMu.RUnlock()
+	return o.cfg
+}
+
+func (o *Orchestrator) router() *router.Router {
+	o.cfgMu.RLock()
+	defer o.cfgMu.RUnlock()
+	return o.rules
+}
+
+// ApplyConfig switches searches to a reloaded config. Searches already
+// holding an overload token release it to the queue they took it from.
+func (o *Orchestrator) ApplyConfig(cfg *config.Config) {
+	o.cfgMu.Lock()
+	defer o.cfgMu.Unlock()
+	if cfg.Runtime.OverloadMaxConcurrentSearch != o.cfg.Runtime.OverloadMaxConcurrentSearch {
+		o.searchTokens = newSearchTokens(cfg)
+	}
+	if !reflect.DeepEqual(cfg.Routing, o.cfg.Routing) {
+		o.rules = newRouter(cfg, o.log)
+		o.log.Info("routing rules reloaded", "rules_file", cfg.Routing.RulesFile, "rules", len(cfg.Routing.Rules.Rules))
+	}
+	if synonymsChanged(o.cfg, cfg) {
+		o.resetExpanders()
+		o.ClearCache()
+		o.log.Info("synonyms reloaded, cache cleared", "synonym_groups", len(cfg.Search.Synonyms), "aliases", cfg.Search.Aliases)
+		if cfg.Search.Aliases == "frontmatter" && o.cfg.Search.Aliases != "frontmatter" {
+			defer func() { go o.RefreshAliases(context.Background(), nil) }()
+		}
+	}
+	if !reflect.DeepEqual(cfg.Search.Cascade, o.cfg.Search.Cascade) {
+		o.ClearCache()
+		o.log.Info("tier cascade changed, cache cleared", "tiers", len(cfg.Search.Cascade))
+	}
+	if cfg.Search.Segmenter != o.cfg.Search.Segmenter || !reflect.DeepEqual(cfg.Search.UserWords, o.cfg.Search.UserWords) {
+		o.seg = newSegmenter(cfg)
+		o.log.Info("query segmenter reloaded", "segmenter", cfg.Search.Segmenter, "user_words", len(cfg.Search.UserWords))
+	}
+	o.cfg = cfg
+}
+
+// BM25Query returns the query BM25 searches run with. vsearch and deep query
+// always get the original query.
+func (o *Orchestrator) BM25Query(query string) string {
+	o.cfgMu.RLock()
+	seg := o.seg
+	o.cfgMu.RUnlock()
+	if seg == nil {
+		return query
+	}
+	return seg.BM25Query(query)
+}
+
 func (o *Orchestrator) Start(ctx context.Context) {
 	if o.cpuMonitor != nil {
 		o.cpuMonitor.Start(ctx)
@@ -99,6 +185,31 @@ func (o *Orchestrator) IsOverloaded() bool {
 	return o.cpuMonitor.IsOverloaded()
 }
 
+// CPUSnapshot returns the latest CPU monitor sample.
+func (o *Orchestrator) CPUSnapshot() resourceguard.CPUSnapshot {
+	if o.cpuMonitor == nil {
+		return resourceguard.CPUSnapshot{}
+	}
+	return o.cpuMonitor.Snapshot()
+}
+
+// InFlightSearches is the number of Search calls currently running.
+func (o *Orchestrator) InFlightSearches() int {
+	return int(o.inflight.Load())
+}
+
+// Ping is the liveness probe behind the systemd watchdog. It runs the
+// in-process part of a search, routing a fixed query and taking the result
+// cache lock, without forking qmd: a slow qmd on a busy host is no reason to
+// restart qmdsr, and qmd's own health is left to the heartbeat.
+func (o *Orchestrator) Ping(ctx context.Context) error {
+	o.Route(ctx, "watchdog ping", nil)
+	if o.cache != nil && !o.cache.Healthy() {
+		return fmt.Errorf("result cache unhealthy")
+	}
+	return ctx.Err()
+}
+
 func (o *Orchestrator) IsCriticalOverloaded() bool {
 	if o.cpuMonitor == nil {
 		return false
@@ -112,7 +223,8 @@ func (o *Orchestrator) ClearCache() {
 	}
 }
 
-func (o *Orchestrator) HasCachedResult(params SearchParams) bool {
+func (o *Orchestrator) HasCachedResult(ctx context.Context, params SearchParams) bool {
+	cfg := o.config()
 	if o.cache == nil {
 		return false
 	}
@@ -122,15 +234,15 @@ func (o *Orchestrator) HasCachedResult(params SearchParams) bool {
 		if params.FilesOnly && params.FilesAll {
 			n = 0
 		} else {
-			n = o.cfg.Search.TopK
+			n = cfg.Search.TopK
 		}
 	}
 	minScore := params.MinScore
 	if minScore <= 0 {
-		minScore = o.cfg.Search.MinScore
+		minScore = cfg.Search.MinScore
 	}
 	key := cache.MakeCacheKey(params.Query, params.Mode, params.Collection, minScore, n, params.Fallback, params.FilesOnly, params.FilesAll)
-	_, ok := o.cache.Get(key)
+	_, ok := o.cache.Get(scopedCacheKey(ctx, key))
 	return ok
 }
 
@@ -146,18 +258,14 @@ func (o *Orchestrator) EnsureCollections(ctx context.Context) error {
 		existingMap[col.Name] = true
 	}
 
-	for _, col := range o.cfg.Collections {
+	for _, col := range o.config().Collections {
 		if existingMap[col.Name] {
 			o.log.Info("collection already registered", "name", col.Name)
 			continue
 		}
 
 		o.log.Info("registering collection", "name", col.Name, "path", col.Path)
-		mask := col.Mask
-		if mask == "" {
-			mask = "**/*.md"
-		}
-		if err := o.exec.CollectionAdd(ctx, col.Path, col.Name, mask); err != nil {
+		if err := o.exec.CollectionAdd(ctx, col.Path, col.Name, collectionMask(col.Mask)); err != nil {
 			o.log.Error("failed to add collection", "name", col.Name, "err", err)
 			continue
 		}
@@ -173,6 +281,25 @@ func (o *Orchestrator) EnsureCollections(ctx context.Context) error {
 	return nil
 }
 
+// UnregisterCollections removes collections, and the contexts attached to
+// their paths, from the qmd index.
+func (o *Orchestrator) UnregisterCollections(ctx context.Context, cols []config.CollectionCfg) error {
+	var errs []error
+	for _, col := range cols {
+		o.log.Info("unregistering collection", "name", col.Name, "path", col.Path)
+		if err := o.exec.CollectionRemove(ctx, col.Name); err != nil {
+			errs = append(errs, fmt.Errorf("remove collection %s: %w", col.Name, err))
+			continue
+		}
+		if strings.TrimSpace(col.Context) != "" {
+			if err := o.exec.ContextRemove(ctx, col.Path); err != nil {
+				o.log.Warn("failed to remove context", "name", col.Name, "err", err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (o *Orchestrator) syncCollectionContexts(ctx context.Context) {
 	existingContexts, err := o.exec.ContextList(ctx)
 	if err != nil {
@@ -185,7 +312,7 @@ func (o *Orchestrator) syncCollectionContexts(ctx context.Context) {
 		contextMap[c.Path] = c.Description
 	}
 
-	for _, col := range o.cfg.Collections {
+	for _, col := range o.config().Collections {
 		if strings.TrimSpace(col.Context) == "" {
 			continue
 		}
@@ -221,6 +348,18 @@ type SearchParams struct {
 	FilesAll              bool
 	DisableDeepEscalation bool
 	Confirm               bool
+	// Route is the routing decision for the request; nil routes the search
+	// on its own.
+	Route *router.Decision
+	// Downgraded marks searches the caller forced to search mode, e.g. under
+	// CPU overload, so verbose queries are rewritten like other downgrades.
+	Downgraded bool
+	// OnPartial, when set, receives intermediate result sets while later
+	// stages (tier fallback, deep query) are still running. It is never
+	// called after Search returns.
+	OnPartial func(PartialResult)
+
+	keywordQueries []string
 }
 
 type SearchResult struct {
@@ -228,17 +367,44 @@ type SearchResult struct {
 	Meta    model.SearchMeta
 }
 
+// Search stages. StageTier1 is the first tier of the cascade and StageTier2
+// every tier it falls through to.
+const (
+	StageTier1 = "tier1"
+	StageTier2 = "tier2"
+	StageDeep  = "deep"
+)
+
+// PartialResult is an intermediate, already finalized result set for one
+// search stage. Later partials and the final SearchResult supersede it.
+type PartialResult struct {
+	Stage   string
+	Results []model.SearchResult
+	Meta    model.SearchMeta
+}
+
 func (o *Orchestrator) Search(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	o.inflight.Add(1)
+	defer o.inflight.Add(-1)
+
+	cfg := o.config()
 	start := time.Now()
 
 	if params.N <= 0 {
-		params.N = o.cfg.Search.TopK
+		params.N = cfg.Search.TopK
 	}
 	if params.MinScore <= 0 {
-		params.MinScore = o.cfg.Search.MinScore
+		params.MinScore = cfg.Search.MinScore
+	}
+
+	if params.Collection != "" {
+		if err := o.authorizeCollection(ctx, params.Collection, params.Confirm); err != nil {
+			return nil, err
+		}
 	}
 
 	cacheKey := cache.MakeCacheKey(params.Query, params.Mode, params.Collection, params.MinScore, params.N, params.Fallback, params.FilesOnly, params.FilesAll)
+	cacheKey = scopedCacheKey(ctx, cacheKey)
 	if o.cache != nil {
 		if entry, ok := o.cache.Get(cacheKey); ok {
 			collections := []string{entry.Collection}
@@ -258,6 +424,9 @@ func (o *Orchestrator) Search(ctx context.Context, params SearchParams) (*Search
 					ModeUsed:            entry.Mode,
 					CollectionsSearched: collections,
 					FallbackTriggered:   entry.FallbackTriggered,
+					Cascade:             entry.Cascade,
+					BM25Queries:         entry.BM25Queries,
+					Expansions:          entry.Expansions,
 					CacheHit:            true,
 					Degraded:            entry.Degraded,
 					DegradeReason:       entry.DegradeReason,
@@ -267,8 +436,28 @@ func (o *Orchestrator) Search(ctx context.Context, params SearchParams) (*Search
 		}
 	}
 
-	mode := o.resolveMode(params.Mode, params.Query)
+	if params.Route == nil {
+		route := o.Route(ctx, params.Query, nonEmpty(params.Collection))
+		params.Route = &route
+	}
+	mode := o.resolveMode(ctx, params.Mode, params.Query, *params.Route)
+	if params.Collection != "" {
+		mode = o.collectionMode(params.Collection, mode)
+	}
+	if mode == router.ModeSearch && o.downgraded(params) {
+		params.keywordQueries = o.keywordQueries(params.Query)
+	}
+
+	res, err := o.searchMode(ctx, params, mode, cacheKey, start)
+	if err != nil {
+		return nil, err
+	}
+	res.Meta.BM25Queries = bm25Queries(params, res.Meta.ModeUsed)
+	res.Meta.Expansions = o.expansions(params, res.Meta.ModeUsed, res.Meta.CollectionsSearched)
+	return res, nil
+}
 
+func (o *Orchestrator) searchMode(ctx context.Context, params SearchParams, mode router.Mode, cacheKey string, start time.Time) (*SearchResult, error) {
 	if params.Collection != "" {
 		if mode == router.ModeQuery {
 			return o.searchSingleCollectionWithDeepFallback(ctx, params, cacheKey, start)
@@ -283,13 +472,82 @@ func (o *Orchestrator) Search(ctx context.Context, params SearchParams) (*Search
 	return o.searchWithFallback(ctx, params, mode, cacheKey, start)
 }
 
-func (o *Orchestrator) resolveMode(requested string, query string) router.Mode {
+// downgraded reports whether a search served by BM25 was meant to run a
+// richer mode.
+func (o *Orchestrator) downgraded(params SearchParams) bool {
+	if params.Downgraded {
+		return true
+	}
+	intended := router.Mode(params.Mode)
+	if params.Mode == "" || params.Mode == "auto" {
+		intended = params.Route.Mode
+	}
+	return intended != router.ModeSearch
+}
+
+// bm25Queries returns the keyword rewrites behind an answer served by BM25.
+func bm25Queries(params SearchParams, mode string) []string {
+	if mode != string(router.ModeSearch) {
+		return nil
+	}
+	return params.keywordQueries
+}
+
+// keywordQueries rewrites a verbose query into keyword queries for BM25.
+// Queries shorter than search.rewrite_min_words are left alone.
+func (o *Orchestrator) keywordQueries(query string) []string {
+	o.cfgMu.RLock()
+	cfg, seg := o.cfg, o.seg
+	o.cfgMu.RUnlock()
+	if seg == nil || cfg.Search.RewriteMinWords <= 0 || seg.CountWords(query) < cfg.Search.RewriteMinWords {
+		return nil
+	}
+	queries := seg.KeywordQueries(query, cfg.Search.RewriteMaxTerms)
+	if len(queries) > 0 {
+		o.log.Debug("verbose query rewritten for bm25", "query_len", runeLen(query), "queries", queries)
+	}
+	return queries
+}
+
+// Route evaluates the routing rules for a search naming the given
+// collections.
+func (o *Orchestrator) Route(ctx context.Context, query string, collections []string) router.Decision {
+	in := router.Input{
+		Query:         query,
+		Collections:   collections,
+		HasCapability: o.exec.HasCapability,
+	}
+	if p, ok := authz.FromContext(ctx); ok {
+		in.Client = p.Name
+	}
+	return o.router().Route(in)
+}
+
+func nonEmpty(name string) []string {
+	if name == "" {
+		return nil
+	}
+	return []string{name}
+}
+
+func (o *Orchestrator) resolveMode(ctx context.Context, requested string, query string, route router.Decision) router.Mode {
+	_, span := tracing.Start(ctx, "orchestrator.resolve_mode", attribute.String("qmdsr.requested_mode", requested))
+	mode := o.detectMode(requested, query, route)
+	span.SetAttributes(attribute.String("qmdsr.mode", string(mode)), attribute.String("qmdsr.route_rule", route.ModeRule))
+	span.End()
+	return mode
+}
+
+func (o *Orchestrator) detectMode(requested string, query string, route router.Decision) router.Mode {
 	isAuto := requested == "" || requested == "auto"
 	var mode router.Mode
 	if !isAuto {
 		mode = router.Mode(requested)
 	} else {
-		mode = router.DetectMode(query, o.exec.HasCapability("vector"), o.exec.HasCapability("deep_query"))
+		mode = route.Mode
+		if strings.TrimSpace(query) == "" {
+			mode = router.ModeSearch
+		}
 	}
 
 	if o.IsOverloaded() && mode != router.ModeSearch {
@@ -303,7 +561,7 @@ func (o *Orchestrator) resolveMode(requested string, query string) router.Mode {
 			o.log.Debug("query mode unavailable, fallback to search")
 			return router.ModeSearch
 		}
-		if isAuto && !o.allowAutoDeepQuery(query) {
+		if isAuto && !o.allowAutoDeepQuery(query, route.Gate) {
 			o.log.Debug("auto query downgraded to search in smart_routing mode")
 			return router.ModeSearch
 		}
@@ -312,13 +570,38 @@ func (o *Orchestrator) resolveMode(requested string, query string) router.Mode {
 			o.log.Debug("vsearch mode unavailable, fallback to search")
 			return router.ModeSearch
 		}
+	case router.ModeHybrid:
+		if !o.exec.HasCapability("vector") {
+			o.log.Debug("hybrid mode unavailable without vector capability, fallback to search")
+			return router.ModeSearch
+		}
 	}
 
 	return mode
 }
 
-func (o *Orchestrator) allowAutoDeepQuery(query string) bool {
-	if !(o.cfg.Runtime.LowResourceMode && o.cfg.Runtime.AllowCPUDeepQuery && o.cfg.Runtime.SmartRouting) {
+// collectionMode downgrades vector-backed modes to BM25 for collections
+// without embed: true, which have no vectors to search.
+func (o *Orchestrator) collectionMode(name string, mode router.Mode) router.Mode {
+	if mode == router.ModeSearch {
+		return mode
+	}
+	if col := o.findCollection(name); col != nil && !col.Embed {
+		o.log.Debug("collection not embedded, downgrading to search", "collection", name, "mode", mode)
+		return router.ModeSearch
+	}
+	return mode
+}
+
+func (o *Orchestrator) allowAutoDeepQuery(query string, gate router.Gate) bool {
+	switch gate {
+	case router.GateAllow:
+		return true
+	case router.GateDeny:
+		return false
+	}
+	cfg := o.config()
+	if !(cfg.Runtime.LowResourceMode && cfg.Runtime.AllowCPUDeepQuery && cfg.Runtime.SmartRouting) {
 		return true
 	}
 
@@ -329,18 +612,19 @@ func (o *Orchestrator) allowAutoDeepQuery(query string) bool {
 
 	chars := runeLen(q)
 	words := textutil.CountWordsMaxFieldsOrCJK(q)
-	abstractCues := countAbstractCues(q)
+	rules := o.router()
+	abstractCues := rules.CountCues("abstract", q)
 
-	if chars < o.cfg.Runtime.CPUDeepMinChars {
+	if chars < cfg.Runtime.CPUDeepMinChars {
 		return false
 	}
-	if o.cfg.Runtime.CPUDeepMaxChars > 0 && chars > o.cfg.Runtime.CPUDeepMaxChars {
+	if cfg.Runtime.CPUDeepMaxChars > 0 && chars > cfg.Runtime.CPUDeepMaxChars {
 		return false
 	}
-	if o.cfg.Runtime.CPUDeepMaxWords > 0 && words > o.cfg.Runtime.CPUDeepMaxWords {
+	if cfg.Runtime.CPUDeepMaxWords > 0 && words > cfg.Runtime.CPUDeepMaxWords {
 		return false
 	}
-	if o.cfg.Runtime.CPUDeepMaxAbstractCues > 0 && abstractCues > o.cfg.Runtime.CPUDeepMaxAbstractCues {
+	if cfg.Runtime.CPUDeepMaxAbstractCues > 0 && abstractCues > cfg.Runtime.CPUDeepMaxAbstractCues {
 		return false
 	}
 	// Guard against OOM-prone abstract long-form prompts on low-resource hosts.
@@ -348,11 +632,11 @@ func (o *Orchestrator) allowAutoDeepQuery(query string) bool {
 		return false
 	}
 
-	if words >= o.cfg.Runtime.CPUDeepMinWords {
+	if words >= cfg.Runtime.CPUDeepMinWords {
 		return true
 	}
 
-	if hasQuestionCue(q) {
+	if rules.CountCues("question", q) > 0 {
 		return words >= 4 || textutil.CountCJK(q) >= 6
 	}
 
@@ -360,52 +644,20 @@ func (o *Orchestrator) allowAutoDeepQuery(query string) bool {
 }
 
 // AllowDeepQuery reports whether the current runtime budget allows executing deep query.
-// In low-resource mode this enforces smart routing budgets; otherwise it always allows.
-func (o *Orchestrator) AllowDeepQuery(query string) bool {
-	return o.allowAutoDeepQuery(query)
+// A gate decision from the routing rules wins; otherwise low-resource mode
+// enforces smart routing budgets and any other host always allows.
+func (o *Orchestrator) AllowDeepQuery(query string, gate router.Gate) bool {
+	return o.allowAutoDeepQuery(query, gate)
 }
 
 func runeLen(s string) int {
 	return len([]rune(s))
 }
 
-func hasQuestionCue(s string) bool {
-	lower := strings.ToLower(s)
-	cues := []string{
-		"如何", "怎么", "怎样", "什么", "为什么", "为何", "是否", "能不能", "可以", "应该",
-		"?", "？", "how ", "what ", "why ", "when ", "where ", "which ", "should ",
-	}
-	for _, cue := range cues {
-		if strings.Contains(lower, cue) {
-			return true
-		}
-	}
-	return false
-}
-
-func countAbstractCues(s string) int {
-	lower := strings.ToLower(s)
-	cues := []string{
-		"方案", "架构", "规划", "体系", "框架", "设计", "tradeoff", "strategy",
-		"architecture", "design", "plan", "migration", "roadmap",
-	}
-	count := 0
-	for _, cue := range cues {
-		if strings.Contains(lower, cue) {
-			count++
-		}
-	}
-	return count
-}
-
 func (o *Orchestrator) searchSingleCollection(ctx context.Context, params SearchParams, mode router.Mode, cacheKey string, start time.Time) (*SearchResult, error) {
 	colCfg := o.findCollection(params.Collection)
-	if colCfg == nil {
-		return nil, fmt.Errorf("collection %q not found", params.Collection)
-	}
-
-	if colCfg.RequireExplicit && colCfg.SafetyPrompt && !params.Confirm {
-		return nil, fmt.Errorf("collection %q requires confirm=true", params.Collection)
+	if err := o.authorizeCollection(ctx, params.Collection, params.Confirm); err != nil {
+		return nil, err
 	}
 
 	results, err := o.execSearch(ctx, mode, params.Query, params.Collection, params)
@@ -414,20 +666,16 @@ func (o *Orchestrator) searchSingleCollection(ctx context.Context, params Search
 	}
 
 	results = o.filterExclude(results, colCfg)
-	results = o.filterMinScore(results, params.MinScore)
+	results = o.filterMinScore(results, minScoreForMode(mode, params.MinScore))
 	results = o.finalizeResults(results, params.N, params.FilesOnly, params.FilesAll)
 
-	return o.cacheAndBuildSearchResult(cacheKey, results, mode, []string{params.Collection}, false, false, "", start), nil
+	return o.cacheAndBuildSearchResult(cacheKey, params, results, mode, []string{params.Collection}, nil, false, "", start), nil
 }
 
 func (o *Orchestrator) searchSingleCollectionWithDeepFallback(ctx context.Context, params SearchParams, cacheKey string, start time.Time) (*SearchResult, error) {
 	colCfg := o.findCollection(params.Collection)
-	if colCfg == nil {
-		return nil, fmt.Errorf("collection %q not found", params.Collection)
-	}
-
-	if colCfg.RequireExplicit && colCfg.SafetyPrompt && !params.Confirm {
-		return nil, fmt.Errorf("collection %q requires confirm=true", params.Collection)
+	if err := o.authorizeCollection(ctx, params.Collection, params.Confirm); err != nil {
+		return nil, err
 	}
 
 	if ok, reason := o.shouldSkipDeepByNegativeCache(params.Query, params.Collection); ok {
@@ -439,7 +687,7 @@ func (o *Orchestrator) searchSingleCollectionWithDeepFallback(ctx context.Contex
 		broadResults = o.filterExclude(broadResults, colCfg)
 		broadResults = o.filterMinScore(broadResults, params.MinScore)
 		broadResults = o.finalizeResults(broadResults, params.N, params.FilesOnly, params.FilesAll)
-		return o.cacheAndBuildSearchResult(cacheKey, broadResults, router.ModeSearch, []string{params.Collection}, false, true, reason, start), nil
+		return o.cacheAndBuildSearchResult(cacheKey, params, broadResults, router.ModeSearch, []string{params.Collection}, nil, true, reason, start), nil
 	}
 
 	type resultPayload struct {
@@ -462,6 +710,7 @@ func (o *Orchestrator) searchSingleCollectionWithDeepFallback(ctx context.Contex
 		broadResults = o.filterExclude(broadResults, colCfg)
 		broadResults = o.filterMinScore(broadResults, params.MinScore)
 		broadResults = o.finalizeResults(broadResults, params.N, params.FilesOnly, params.FilesAll)
+		o.emitPartial(params, StageTier1, broadResults, router.ModeSearch, []string{params.Collection}, false)
 		broadCh <- resultPayload{results: broadResults}
 	}()
 
@@ -484,43 +733,33 @@ func (o *Orchestrator) searchSingleCollectionWithDeepFallback(ctx context.Contex
 
 	if deep.err != nil {
 		o.markDeepNegative(params.Query, params.Collection)
-		return o.cacheAndBuildSearchResult(cacheKey, broad.results, router.ModeSearch, []string{params.Collection}, false, true, "deep_failed_fallback_broad", start), nil
+		return o.cacheAndBuildSearchResult(cacheKey, params, broad.results, router.ModeSearch, []string{params.Collection}, nil, true, "deep_failed_fallback_broad", start), nil
 	}
 
 	if len(deep.results) == 0 {
-		return o.cacheAndBuildSearchResult(cacheKey, broad.results, router.ModeSearch, []string{params.Collection}, false, true, "deep_empty_fallback_broad", start), nil
+		return o.cacheAndBuildSearchResult(cacheKey, params, broad.results, router.ModeSearch, []string{params.Collection}, nil, true, "deep_empty_fallback_broad", start), nil
 	}
 
-	return o.cacheAndBuildSearchResult(cacheKey, deep.results, router.ModeQuery, []string{params.Collection}, false, false, "", start), nil
+	return o.cacheAndBuildSearchResult(cacheKey, params, deep.results, router.ModeQuery, []string{params.Collection}, nil, false, "", start), nil
 }
 
 func (o *Orchestrator) searchWithDeepFallback(ctx context.Context, params SearchParams, cacheKey string, start time.Time) (*SearchResult, error) {
 	if ok, reason := o.shouldSkipDeepByNegativeCache(params.Query, "all"); ok {
-		broadResults, broadSearched, broadFallback := o.searchBroadAll(ctx, params)
-		return o.cacheAndBuildSearchResult(cacheKey, broadResults, router.ModeSearch, broadSearched, broadFallback, true, reason, start), nil
+		broad := o.searchBroadAll(ctx, params)
+		return o.cacheAndBuildSearchResult(cacheKey, params, broad.results, router.ModeSearch, broad.searched, broad.steps, true, reason, start), nil
 	}
 
-	type broadPayload struct {
-		results  []model.SearchResult
-		searched []string
-		fallback bool
-	}
 	type deepPayload struct {
 		results  []model.SearchResult
 		searched []string
 		err      error
 	}
 
-	broadCh := make(chan broadPayload, 1)
+	broadCh := make(chan cascadeResult, 1)
 	deepCh := make(chan deepPayload, 1)
 
 	go func() {
-		broadResults, broadSearched, broadFallback := o.searchBroadAll(ctx, params)
-		broadCh <- broadPayload{
-			results:  broadResults,
-			searched: broadSearched,
-			fallback: broadFallback,
-		}
+		broadCh <- o.searchBroadAll(ctx, params)
 	}()
 
 	go func() {
@@ -539,24 +778,25 @@ func (o *Orchestrator) searchWithDeepFallback(ctx context.Context, params Search
 
 	if deep.err != nil {
 		o.markDeepNegative(params.Query, "all")
-		return o.cacheAndBuildSearchResult(cacheKey, broad.results, router.ModeSearch, broad.searched, broad.fallback, true, "deep_failed_fallback_broad", start), nil
+		return o.cacheAndBuildSearchResult(cacheKey, params, broad.results, router.ModeSearch, broad.searched, broad.steps, true, "deep_failed_fallback_broad", start), nil
 	}
 
 	deepResults := o.finalizeResults(o.filterMinScore(deep.results, params.MinScore), params.N, params.FilesOnly, params.FilesAll)
 	if len(deepResults) == 0 {
-		return o.cacheAndBuildSearchResult(cacheKey, broad.results, router.ModeSearch, broad.searched, broad.fallback, true, "deep_empty_fallback_broad", start), nil
+		return o.cacheAndBuildSearchResult(cacheKey, params, broad.results, router.ModeSearch, broad.searched, broad.steps, true, "deep_empty_fallback_broad", start), nil
 	}
 
-	return o.cacheAndBuildSearchResult(cacheKey, deepResults, router.ModeQuery, deep.searched, false, false, "", start), nil
+	return o.cacheAndBuildSearchResult(cacheKey, params, deepResults, router.ModeQuery, deep.searched, nil, false, "", start), nil
 }
 
 func (o *Orchestrator) searchWithFallback(ctx context.Context, params SearchParams, mode router.Mode, cacheKey string, start time.Time) (*SearchResult, error) {
-	filtered, searched, fallbackTriggered := o.searchPrimaryWithTierFallback(ctx, params, mode, "search failed")
+	tiers := o.searchCascade(ctx, params, mode, "search failed")
+	filtered, searched := tiers.results, tiers.searched
 	degraded := false
 	degradeReason := ""
 
 	if !params.DisableDeepEscalation && mode == router.ModeSearch && len(filtered) == 0 && o.exec.HasCapability("deep_query") && !o.IsOverloaded() {
-		if o.allowAutoDeepQuery(params.Query) {
+		if o.allowAutoDeepQuery(params.Query, params.Route.Gate) {
 			if ok, reason := o.shouldSkipDeepByNegativeCache(params.Query, "all"); ok {
 				degraded = true
 				degradeReason = reason
@@ -586,24 +826,27 @@ func (o *Orchestrator) searchWithFallback(ctx context.Context, params SearchPara
 
 	filtered = o.finalizeResults(filtered, params.N, params.FilesOnly, params.FilesAll)
 
-	o.cacheResults(cacheKey, filtered, string(mode), strings.Join(searched, ","), fallbackTriggered, degraded, degradeReason)
+	o.cacheResults(cacheKey, params, filtered, string(mode), searched, tiers.steps, degraded, degradeReason)
 
 	res := &SearchResult{
 		Results: filtered,
 		Meta: model.SearchMeta{
 			ModeUsed:            string(mode),
 			CollectionsSearched: searched,
-			FallbackTriggered:   fallbackTriggered,
+			FallbackTriggered:   tiers.fallbackTriggered(),
+			Cascade:             tiers.steps,
 			Degraded:            degraded,
 			DegradeReason:       degradeReason,
 			LatencyMs:           time.Since(start).Milliseconds(),
 		},
 	}
-	o.observeSearchSample(res.Meta.LatencyMs, len(filtered), degraded)
+	o.observeSearchSample(res.Meta, len(filtered))
 	return res, nil
 }
 
 func (o *Orchestrator) searchTierParallel(ctx context.Context, cols []config.CollectionCfg, mode router.Mode, params SearchParams, logMsg string) ([]model.SearchResult, []string, error) {
+	cols = o.permittedCollections(ctx, cols)
+
 	var mu sync.Mutex
 	var allResults []model.SearchResult
 	var searched []string
@@ -614,7 +857,15 @@ func (o *Orchestrator) searchTierParallel(ctx context.Context, cols []config.Col
 		wg.Add(1)
 		go func(c config.CollectionCfg) {
 			defer wg.Done()
+			mode := o.collectionMode(c.Name, mode)
+			ctx, span := tracing.Start(ctx, "orchestrator.search_collection",
+				attribute.String("qmdsr.collection", c.Name),
+				attribute.Int("qmdsr.tier", c.Tier),
+				attribute.String("qmdsr.mode", string(mode)),
+			)
 			results, err := o.execSearch(ctx, mode, params.Query, c.Name, params)
+			span.SetAttributes(attribute.Int("qmdsr.hits", len(results)))
+			tracing.End(span, err)
 			if err != nil {
 				mu.Lock()
 				if firstErr == nil {
@@ -635,16 +886,16 @@ func (o *Orchestrator) searchTierParallel(ctx context.Context, cols []config.Col
 	return allResults, searched, firstErr
 }
 
-func (o *Orchestrator) searchBroadAll(ctx context.Context, params SearchParams) ([]model.SearchResult, []string, bool) {
-	filtered, searched, fallbackTriggered := o.searchPrimaryWithTierFallback(ctx, params, router.ModeSearch, "broad search failed")
-	filtered = o.finalizeResults(filtered, params.N, params.FilesOnly, params.FilesAll)
-	return filtered, searched, fallbackTriggered
+func (o *Orchestrator) searchBroadAll(ctx context.Context, params SearchParams) cascadeResult {
+	broad := o.searchCascade(ctx, params, router.ModeSearch, "broad search failed")
+	broad.results = o.finalizeResults(broad.results, params.N, params.FilesOnly, params.FilesAll)
+	return broad
 }
 
 func (o *Orchestrator) searchDeepTier1(ctx context.Context, params SearchParams) ([]model.SearchResult, []string, error) {
-	tier1 := o.collectionsByTier(1)
+	tier1 := slices.DeleteFunc(o.collectionsByTier(1), func(c config.CollectionCfg) bool { return !c.Embed })
 	if len(tier1) == 0 {
-		return nil, nil, fmt.Errorf("no tier-1 collection configured")
+		return nil, nil, fmt.Errorf("no embedded tier-1 collection configured")
 	}
 	allResults, searched, firstErr := o.searchTierParallel(ctx, tier1, router.ModeQuery, params, "deep search failed")
 	if len(allResults) == 0 && firstErr != nil {
@@ -678,21 +929,71 @@ func (o *Orchestrator) execSearch(ctx context.Context, mode router.Mode, query,
 		return o.exec.VSearch(ctx, query, opts)
 	case router.ModeQuery:
 		return o.exec.Query(ctx, query, opts)
+	case router.ModeHybrid:
+		return o.execHybrid(ctx, query, params, opts)
 	default:
-		return o.exec.Search(ctx, query, opts)
+		return o.execBM25(ctx, query, params, opts)
+	}
+}
+
+// execHybrid runs BM25 and vsearch concurrently and fuses both ranked lists
+// with weighted reciprocal rank fusion. A failing leg degrades to the other.
+func (o *Orchestrator) execHybrid(ctx context.Context, query string, params SearchParams, opts executor.SearchOpts) ([]model.SearchResult, error) {
+	cfg := o.config()
+	type legPayload struct {
+		results []model.SearchResult
+		err     error
+	}
+
+	bm25Ch := make(chan legPayload, 1)
+	vectorCh := make(chan legPayload, 1)
+
+	go func() {
+		results, err := o.execBM25(ctx, query, params, opts)
+		bm25Ch <- legPayload{results: results, err: err}
+	}()
+	go func() {
+		results, err := o.exec.VSearch(ctx, query, opts)
+		vectorCh <- legPayload{results: results, err: err}
+	}()
+
+	bm25 := <-bm25Ch
+	vector := <-vectorCh
+
+	if bm25.err != nil && vector.err != nil {
+		return nil, fmt.Errorf("hybrid search failed: bm25: %v; vsearch: %w", bm25.err, vector.err)
+	}
+	if bm25.err != nil {
+		o.log.Warn("hybrid bm25 leg failed, using vsearch only", "collection", opts.Collection, "err", bm25.err)
+	}
+	if vector.err != nil {
+		o.log.Warn("hybrid vsearch leg failed, using bm25 only", "collection", opts.Collection, "err", vector.err)
 	}
+
+	return searchutil.FuseRRF(
+		[][]model.SearchResult{bm25.results, vector.results},
+		[]float64{cfg.Search.HybridBM25Weight, cfg.Search.HybridVectorWeight},
+		cfg.Search.HybridRRFK,
+	), nil
 }
 
 func (o *Orchestrator) acquireOverloadSearchToken(ctx context.Context) (chan struct{}, error) {
-	if !o.IsOverloaded() || o.searchTokens == nil {
+	o.cfgMu.RLock()
+	tokens := o.searchTokens
+	o.cfgMu.RUnlock()
+	if !o.IsOverloaded() || tokens == nil {
 		return nil, nil
 	}
 
+	_, span := tracing.Start(ctx, "orchestrator.acquire_overload_token")
 	select {
-	case o.searchTokens <- struct{}{}:
-		return o.searchTokens, nil
+	case tokens <- struct{}{}:
+		span.End()
+		return tokens, nil
 	case <-ctx.Done():
-		return nil, fmt.Errorf("overload search queue busy: %w", ctx.Err())
+		err := fmt.Errorf("overload search queue busy: %w", ctx.Err())
+		tracing.End(span, err)
+		return nil, err
 	}
 }
 
@@ -707,9 +1008,10 @@ func (o *Orchestrator) releaseOverloadSearchToken(ch chan struct{}) {
 }
 
 func (o *Orchestrator) findCollection(name string) *config.CollectionCfg {
-	for i := range o.cfg.Collections {
-		if o.cfg.Collections[i].Name == name {
-			return &o.cfg.Collections[i]
+	cfg := o.config()
+	for i := range cfg.Collections {
+		if cfg.Collections[i].Name == name {
+			return &cfg.Collections[i]
 		}
 	}
 	return nil
@@ -717,7 +1019,7 @@ func (o *Orchestrator) findCollection(name string) *config.CollectionCfg {
 
 func (o *Orchestrator) collectionsByTier(tier int) []config.CollectionCfg {
 	var result []config.CollectionCfg
-	for _, col := range o.cfg.Collections {
+	for _, col := range o.config().Collections {
 		if col.Tier == tier && !col.RequireExplicit {
 			result = append(result, col)
 		}
@@ -732,47 +1034,28 @@ func (o *Orchestrator) filterExclude(results []model.SearchResult, col *config.C
 
 	filtered := make([]model.SearchResult, 0, len(results))
 	for _, r := range results {
-		excluded := false
 		relPath := r.File
 		if strings.HasPrefix(relPath, col.Path) {
 			relPath = strings.TrimPrefix(relPath, col.Path)
 			relPath = strings.TrimPrefix(relPath, "/")
 		}
-		relPath = filepath.Clean(relPath)
-
-		for _, pattern := range col.Exclude {
-			matched, err := filepath.Match(pattern, relPath)
-			if err == nil && matched {
-				excluded = true
-				break
-			}
-			dir := filepath.Dir(relPath)
-			for dir != "." && dir != "/" {
-				testPath := dir + "/"
-				if matched, err := filepath.Match(pattern, testPath); err == nil && matched {
-					excluded = true
-					break
-				}
-				dir = filepath.Dir(dir)
-			}
-			if excluded {
-				break
-			}
-			if strings.HasSuffix(pattern, "/**") {
-				prefix := strings.TrimSuffix(pattern, "/**")
-				if strings.HasPrefix(relPath, prefix+"/") || relPath == prefix {
-					excluded = true
-					break
-				}
-			}
-		}
-		if !excluded {
+		if !pathmatch.Excluded(col.Exclude, relPath) {
 			filtered = append(filtered, r)
 		}
 	}
 	return filtered
 }
 
+// minScoreForMode returns the post-search score threshold for mode. Hybrid
+// scores are normalized fused ranks, and min_score was already applied to the
+// raw scores of each leg, so they are not thresholded again.
+func minScoreForMode(mode router.Mode, minScore float64) float64 {
+	if mode == router.ModeHybrid {
+		return 0
+	}
+	return minScore
+}
+
 func (o *Orchestrator) filterMinScore(results []model.SearchResult, minScore float64) []model.SearchResult {
 	if minScore <= 0 {
 		return results
@@ -786,7 +1069,7 @@ func (o *Orchestrator) filterMinScore(results []model.SearchResult, minScore flo
 	return filtered
 }
 
-func (o *Orchestrator) cacheResults(key string, results []model.SearchResult, mode, collection string, fallbackTriggered bool, degraded bool, degradeReason string) {
+func (o *Orchestrator) cacheResults(key string, params SearchParams, results []model.SearchResult, mode string, searched []string, steps []model.TierStep, degraded bool, degradeReason string) {
 	if o.cache == nil {
 		return
 	}
@@ -794,13 +1077,50 @@ func (o *Orchestrator) cacheResults(key string, results []model.SearchResult, mo
 		Results:           results,
 		Query:             key,
 		Mode:              mode,
-		Collection:        collection,
-		FallbackTriggered: fallbackTriggered,
+		Collection:        strings.Join(searched, ","),
+		Collections:       o.cacheDependencies(params, searched, steps),
+		FallbackTriggered: len(steps) > 1,
+		Cascade:           steps,
+		BM25Queries:       bm25Queries(params, mode),
+		Expansions:        o.expansions(params, mode, searched),
 		Degraded:          degraded,
 		DegradeReason:     degradeReason,
 	})
 }
 
+// cacheDependencies lists the collections whose reindex must invalidate an
+// answer. Tier-wide searches depend on every collection of the tiers their
+// cascade ran, including those that failed or returned nothing: a later
+// tier's answer is only valid while the earlier tiers still fall through.
+// Deep searches without a cascade depend on every tier-1 collection.
+func (o *Orchestrator) cacheDependencies(params SearchParams, searched []string, steps []model.TierStep) []string {
+	seen := make(map[string]bool)
+	var deps []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			deps = append(deps, name)
+		}
+	}
+	if params.Collection != "" {
+		add(params.Collection)
+	} else if len(steps) > 0 {
+		for _, step := range steps {
+			for _, name := range step.Collections {
+				add(name)
+			}
+		}
+	} else {
+		for _, col := range o.collectionsByTier(1) {
+			add(col.Name)
+		}
+	}
+	for _, name := range searched {
+		add(name)
+	}
+	return deps
+}
+
 func (o *Orchestrator) finalizeResults(results []model.SearchResult, n int, filesOnly bool, filesAll bool) []model.SearchResult {
 	if filesOnly {
 		results = searchutil.DedupSortLimit(results, n)
@@ -815,7 +1135,7 @@ func (o *Orchestrator) finalizeResults(results []model.SearchResult, n int, file
 }
 
 func (o *Orchestrator) enforceFilesAllMaxHits(results []model.SearchResult) []model.SearchResult {
-	limit := o.cfg.Search.FilesAllMaxHits
+	limit := o.config().Search.FilesAllMaxHits
 	if limit <= 0 || len(results) <= limit {
 		return results
 	}
@@ -831,7 +1151,7 @@ func (o *Orchestrator) cleanResultSnippets(results []model.SearchResult) []model
 }
 
 func (o *Orchestrator) enforceMaxChars(results []model.SearchResult) []model.SearchResult {
-	maxChars := o.cfg.Search.MaxChars
+	maxChars := o.config().Search.MaxChars
 	if maxChars <= 0 {
 		return results
 	}
@@ -872,17 +1192,19 @@ func truncateWithEllipsis(s string, maxChars int) string {
 }
 
 func (o *Orchestrator) deepFailTimeout() time.Duration {
-	if o.cfg.Runtime.DeepFailTimeout > 0 {
-		return o.cfg.Runtime.DeepFailTimeout
+	cfg := o.config()
+	if cfg.Runtime.DeepFailTimeout > 0 {
+		return cfg.Runtime.DeepFailTimeout
 	}
-	if o.cfg.Runtime.QueryTimeout > 0 {
-		return o.cfg.Runtime.QueryTimeout
+	if cfg.Runtime.QueryTimeout > 0 {
+		return cfg.Runtime.QueryTimeout
 	}
 	return 12 * time.Second
 }
 
 func (o *Orchestrator) shouldSkipDeepByNegativeCache(query, scope string) (bool, string) {
-	ttl := o.cfg.Runtime.DeepNegativeTTL
+	cfg := o.config()
+	ttl := cfg.Runtime.DeepNegativeTTL
 	if ttl <= 0 {
 		return false, ""
 	}
@@ -898,13 +1220,13 @@ func (o *Orchestrator) shouldSkipDeepByNegativeCache(query, scope string) (bool,
 		if now.After(expiry) {
 			delete(o.deepNeg, exactKey)
 		} else {
-			o.deepNegExactHitCnt++
+			metrics.DeepNegativeHits.WithLabelValues("exact").Inc()
 			return true, "deep_negative_cached_fallback_broad"
 		}
 	}
 
 	// Scope cooldown is only meaningful when deep query is enabled.
-	if !o.cfg.Runtime.AllowCPUDeepQuery {
+	if !cfg.Runtime.AllowCPUDeepQuery {
 		return false, ""
 	}
 
@@ -912,7 +1234,7 @@ func (o *Orchestrator) shouldSkipDeepByNegativeCache(query, scope string) (bool,
 		if now.After(expiry) {
 			delete(o.deepNeg, scopeCooldownKey)
 		} else {
-			o.deepNegScopeHitCnt++
+			metrics.DeepNegativeHits.WithLabelValues("scope").Inc()
 			return true, "deep_negative_scope_cooldown"
 		}
 	}
@@ -920,7 +1242,8 @@ func (o *Orchestrator) shouldSkipDeepByNegativeCache(query, scope string) (bool,
 }
 
 func (o *Orchestrator) markDeepNegative(query, scope string) {
-	ttl := o.cfg.Runtime.DeepNegativeTTL
+	cfg := o.config()
+	ttl := cfg.Runtime.DeepNegativeTTL
 	if ttl <= 0 {
 		return
 	}
@@ -931,11 +1254,11 @@ func (o *Orchestrator) markDeepNegative(query, scope string) {
 
 	o.deepNegMu.Lock()
 	o.deepNeg[exactKey] = exactExpiry
-	if o.cfg.Runtime.AllowCPUDeepQuery {
+	if cfg.Runtime.AllowCPUDeepQuery {
 		o.markScopeCooldownLocked(scope, now)
 	}
-	o.deepNegMarkCount++
 	o.deepNegMu.Unlock()
+	metrics.DeepNegativeMarks.Inc()
 }
 
 func (o *Orchestrator) markScopeCooldownLocked(scope string, now time.Time) {
@@ -943,7 +1266,7 @@ func (o *Orchestrator) markScopeCooldownLocked(scope string, now time.Time) {
 	if scope == "" {
 		scope = "all"
 	}
-	cooldown := o.cfg.Runtime.DeepNegativeScopeCooldown
+	cooldown := o.config().Runtime.DeepNegativeScopeCooldown
 	if cooldown <= 0 {
 		return
 	}
@@ -1018,110 +1341,57 @@ func (o *Orchestrator) CleanupDeepNegativeCache() int {
 	return removed
 }
 
-func (o *Orchestrator) searchPrimaryWithTierFallback(ctx context.Context, params SearchParams, mode router.Mode, logMsg string) ([]model.SearchResult, []string, bool) {
-	tier1 := o.collectionsByTier(1)
-	allResults, searched, _ := o.searchTierParallel(ctx, tier1, mode, params, logMsg)
-
-	filtered := o.filterMinScore(allResults, params.MinScore)
-	fallbackTriggered := false
-
-	if len(filtered) == 0 && params.Fallback && o.cfg.Search.FallbackEnabled {
-		tier2 := o.collectionsByTier(2)
-		if len(tier2) > 0 {
-			fallbackTriggered = true
-			t2Results, t2Searched, _ := o.searchTierParallel(ctx, tier2, mode, params, "parallel search failed")
-			filtered = o.filterMinScore(t2Results, params.MinScore)
-			searched = append(searched, t2Searched...)
-		}
+// emitPartial hands a finalized copy of results to params.OnPartial, if set.
+func (o *Orchestrator) emitPartial(params SearchParams, stage string, results []model.SearchResult, mode router.Mode, searched []string, fallbackTriggered bool) {
+	if params.OnPartial == nil {
+		return
 	}
-
-	return filtered, searched, fallbackTriggered
+	snapshot := append([]model.SearchResult(nil), results...)
+	snapshot = o.finalizeResults(snapshot, params.N, params.FilesOnly, params.FilesAll)
+	params.OnPartial(PartialResult{
+		Stage:   stage,
+		Results: snapshot,
+		Meta: model.SearchMeta{
+			ModeUsed:            string(mode),
+			CollectionsSearched: append([]string(nil), searched...),
+			FallbackTriggered:   fallbackTriggered,
+		},
+	})
 }
 
-func (o *Orchestrator) cacheAndBuildSearchResult(cacheKey string, results []model.SearchResult, mode router.Mode, searched []string, fallbackTriggered bool, degraded bool, degradeReason string, start time.Time) *SearchResult {
-	o.cacheResults(cacheKey, results, string(mode), strings.Join(searched, ","), fallbackTriggered, degraded, degradeReason)
+func (o *Orchestrator) cacheAndBuildSearchResult(cacheKey string, params SearchParams, results []model.SearchResult, mode router.Mode, searched []string, steps []model.TierStep, degraded bool, degradeReason string, start time.Time) *SearchResult {
+	o.cacheResults(cacheKey, params, results, string(mode), searched, steps, degraded, degradeReason)
 	res := &SearchResult{
 		Results: results,
 		Meta: model.SearchMeta{
 			ModeUsed:            string(mode),
 			CollectionsSearched: searched,
-			FallbackTriggered:   fallbackTriggered,
+			FallbackTriggered:   len(steps) > 1,
+			Cascade:             steps,
 			Degraded:            degraded,
 			DegradeReason:       degradeReason,
 			LatencyMs:           time.Since(start).Milliseconds(),
 		},
 	}
-	o.observeSearchSample(res.Meta.LatencyMs, len(results), degraded)
+	o.observeSearchSample(res.Meta, len(results))
 	return res
 }
 
 func (o *Orchestrator) effectiveCoarseK() int {
-	k := o.cfg.Search.CoarseK
+	k := o.config().Search.CoarseK
 	if k <= 0 {
 		return 20
 	}
 	return k
 }
 
-func (o *Orchestrator) observeSearchSample(latencyMs int64, hits int, degraded bool) {
-	now := time.Now()
-	total := atomic.AddInt64(&o.obsCount, 1)
-	atomic.AddInt64(&o.obsLatencyMSum, latencyMs)
-	atomic.AddInt64(&o.obsHitsSum, int64(hits))
-	switch {
-	case hits == 0:
-		atomic.AddInt64(&o.obsHitZero, 1)
-	case hits <= 3:
-		atomic.AddInt64(&o.obsHitLow, 1)
-	case hits <= 8:
-		atomic.AddInt64(&o.obsHitMid, 1)
-	default:
-		atomic.AddInt64(&o.obsHitHigh, 1)
-	}
-	if degraded {
-		atomic.AddInt64(&o.obsDegraded, 1)
-	}
-	shouldLog := total%50 == 0
-	o.obsMu.Lock()
-	if !shouldLog && now.Sub(o.obsLastLogAt) >= 30*time.Minute {
-		shouldLog = true
-	}
-	if shouldLog {
-		o.obsLastLogAt = now
-	}
-	o.obsMu.Unlock()
-
-	if !shouldLog {
-		return
+func (o *Orchestrator) observeSearchSample(meta model.SearchMeta, hits int) {
+	reason := ""
+	if meta.Degraded {
+		reason = meta.DegradeReason
+		if reason == "" {
+			reason = "unspecified"
+		}
 	}
-
-	latencySum := atomic.LoadInt64(&o.obsLatencyMSum)
-	hitsSum := atomic.LoadInt64(&o.obsHitsSum)
-	avgLatency := float64(latencySum) / float64(total)
-	avgHits := float64(hitsSum) / float64(total)
-	hitZero := atomic.LoadInt64(&o.obsHitZero)
-	hitLow := atomic.LoadInt64(&o.obsHitLow)
-	hitMid := atomic.LoadInt64(&o.obsHitMid)
-	hitHigh := atomic.LoadInt64(&o.obsHitHigh)
-	degradedCnt := atomic.LoadInt64(&o.obsDegraded)
-
-	o.deepNegMu.Lock()
-	deepNegMarks := o.deepNegMarkCount
-	deepNegExactHits := o.deepNegExactHitCnt
-	deepNegScopeHits := o.deepNegScopeHitCnt
-	o.deepNegMu.Unlock()
-
-	o.log.Info("search_observation",
-		"samples", total,
-		"avg_latency_ms", fmt.Sprintf("%.2f", avgLatency),
-		"avg_hits", fmt.Sprintf("%.2f", avgHits),
-		"hit_zero", hitZero,
-		"hit_1_3", hitLow,
-		"hit_4_8", hitMid,
-		"hit_9_plus", hitHigh,
-		"degraded_count", degradedCnt,
-		"deep_negative_mark_count", deepNegMarks,
-		"deep_negative_exact_hit_count", deepNegExactHits,
-		"deep_negative_scope_hit_count", deepNegScopeHits,
-	)
+	metrics.ObserveSearch(meta.ModeUsed, time.Duration(meta.LatencyMs)*time.Millisecond, hits, reason)
 }
@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"qmdsr/config"
+	"qmdsr/internal/pathmatch"
+	"qmdsr/model"
+)
+
+type DriftKind string
+
+const (
+	// DriftMissing: configured but not registered in qmd.
+	DriftMissing DriftKind = "missing"
+	// DriftUnmanaged: registered in qmd but no longer configured.
+	DriftUnmanaged DriftKind = "unmanaged"
+	DriftPath      DriftKind = "path"
+	DriftMask      DriftKind = "mask"
+)
+
+// CollectionDrift describes how one qmd registration differs from the config.
+type CollectionDrift struct {
+	Name           string
+	Kinds          []DriftKind
+	ConfiguredPath string
+	RegisteredPath string
+	ConfiguredMask string
+	RegisteredMask string
+}
+
+type ReconcileReport struct {
+	Drift []CollectionDrift
+	// Actions lists the qmd changes a repair makes, in order.
+	Actions []string
+	Applied bool
+}
+
+func (d CollectionDrift) String() string {
+	kinds := make([]string, len(d.Kinds))
+	for i, k := range d.Kinds {
+		kinds[i] = string(k)
+	}
+	return d.Name + "(" + strings.Join(kinds, ",") + ")"
+}
+
+// DetectDrift compares name, path and mask of every configured collection
+// against `qmd collection list`. A path or mask qmd does not report is not
+// compared.
+func (o *Orchestrator) DetectDrift(ctx context.Context) ([]CollectionDrift, error) {
+	registered, err := o.exec.CollectionList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+	byName := make(map[string]model.CollectionInfo, len(registered))
+	for _, col := range registered {
+		byName[col.Name] = col
+	}
+
+	var drift []CollectionDrift
+	configured := o.config().Collections
+	for _, col := range configured {
+		d := CollectionDrift{
+			Name:           col.Name,
+			ConfiguredPath: col.Path,
+			ConfiguredMask: collectionMask(col.Mask),
+		}
+		reg, ok := byName[col.Name]
+		if !ok {
+			d.Kinds = []DriftKind{DriftMissing}
+			drift = append(drift, d)
+			continue
+		}
+		d.RegisteredPath, d.RegisteredMask = reg.Path, reg.Mask
+		if reg.Path != "" && filepath.Clean(reg.Path) != filepath.Clean(col.Path) {
+			d.Kinds = append(d.Kinds, DriftPath)
+		}
+		if reg.Mask != "" && reg.Mask != d.ConfiguredMask {
+			d.Kinds = append(d.Kinds, DriftMask)
+		}
+		if len(d.Kinds) > 0 {
+			drift = append(drift, d)
+		}
+	}
+	for _, reg := range registered {
+		if slices.ContainsFunc(configured, func(c config.CollectionCfg) bool { return c.Name == reg.Name }) {
+			continue
+		}
+		drift = append(drift, CollectionDrift{
+			Name:           reg.Name,
+			Kinds:          []DriftKind{DriftUnmanaged},
+			RegisteredPath: reg.Path,
+			RegisteredMask: reg.Mask,
+		})
+	}
+	return drift, nil
+}
+
+// ReconcileCollections reports drift and, with repair, removes unmanaged and
+// moved registrations and adds the configured ones again. dryRun lists the
+// repair actions without running them.
+func (o *Orchestrator) ReconcileCollections(ctx context.Context, repair, dryRun bool) (*ReconcileReport, error) {
+	drift, err := o.DetectDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReconcileReport{Drift: drift}
+	if len(drift) == 0 || !repair {
+		return report, nil
+	}
+
+	var stale []config.CollectionCfg
+	for _, d := range drift {
+		if !slices.Contains(d.Kinds, DriftMissing) {
+			stale = append(stale, config.CollectionCfg{
+				Name:    d.Name,
+				Path:    d.RegisteredPath,
+				Context: o.collectionContext(d.Name),
+			})
+			report.Actions = append(report.Actions, "remove "+d.Name)
+		}
+		if !slices.Contains(d.Kinds, DriftUnmanaged) {
+			report.Actions = append(report.Actions, fmt.Sprintf("add %s %s %s", d.Name, d.ConfiguredPath, d.ConfiguredMask))
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	o.log.Warn("repairing collection drift", "drift", drift, "actions", report.Actions)
+	if err := o.UnregisterCollections(ctx, stale); err != nil {
+		return report, err
+	}
+	if err := o.EnsureCollections(ctx); err != nil {
+		return report, err
+	}
+	if o.cache != nil {
+		names := make([]string, 0, len(drift))
+		for _, d := range drift {
+			names = append(names, d.Name)
+		}
+		o.cache.InvalidateCollections(names)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// CheckCollections is the heartbeat check for collection drift.
+func (o *Orchestrator) CheckCollections(ctx context.Context) (model.HealthLevel, string) {
+	drift, err := o.DetectDrift(ctx)
+	if err != nil {
+		return model.Degraded, "cannot check collections: " + err.Error()
+	}
+	if len(drift) == 0 {
+		return model.Healthy, ""
+	}
+	names := make([]string, len(drift))
+	for i, d := range drift {
+		names[i] = d.String()
+	}
+	return model.Degraded, "collection drift: " + strings.Join(names, " ")
+}
+
+func (o *Orchestrator) collectionContext(name string) string {
+	if col := o.findCollection(name); col != nil {
+		return col.Context
+	}
+	return ""
+}
+
+func collectionMask(mask string) string {
+	if mask == "" {
+		return pathmatch.DefaultMask
+	}
+	return mask
+}
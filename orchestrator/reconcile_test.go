@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"qmdsr/config"
+	"qmdsr/model"
+)
+
+func newDriftFixture() (*Orchestrator, *fakeEnsureExec) {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "alpha", Path: "/a", Tier: 1},
+			{Name: "beta", Path: "/b2", Context: "ctx-b", Tier: 1},
+			{Name: "gamma", Path: "/g", Mask: "**/*.txt", Tier: 2},
+			{Name: "delta", Path: "/d", Tier: 2},
+		},
+	}
+	fakeExec := newFakeEnsureExec(nil, map[string]string{"/b": "ctx-b"})
+	fakeExec.collections = []model.CollectionInfo{
+		{Name: "alpha", Path: "/a/", Mask: "**/*.md"},
+		{Name: "beta", Path: "/b", Mask: "**/*.md"},
+		{Name: "gamma", Path: "/g", Mask: "**/*.md"},
+		{Name: "stale", Path: "/s", Mask: "**/*.md"},
+	}
+	return New(cfg, fakeExec, nil, testLogger()), fakeExec
+}
+
+func TestDetectDrift_ComparesNamePathAndMask(t *testing.T) {
+	o, _ := newDriftFixture()
+
+	drift, err := o.DetectDrift(context.Background())
+	if err != nil {
+		t.Fatalf("DetectDrift failed: %v", err)
+	}
+	got := make(map[string][]DriftKind, len(drift))
+	for _, d := range drift {
+		got[d.Name] = d.Kinds
+	}
+	want := map[string][]DriftKind{
+		"beta":  {DriftPath},
+		"gamma": {DriftMask},
+		"delta": {DriftMissing},
+		"stale": {DriftUnmanaged},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+	for name, kinds := range want {
+		if !slices.Equal(got[name], kinds) {
+			t.Fatalf("drift for %s = %v, want %v", name, got[name], kinds)
+		}
+	}
+
+	level, msg := o.CheckCollections(context.Background())
+	if level != model.Degraded || msg == "" {
+		t.Fatalf("expected degraded health with drift, got %v %q", level, msg)
+	}
+}
+
+func TestReconcileCollections_DryRunLeavesIndexUntouched(t *testing.T) {
+	o, fakeExec := newDriftFixture()
+
+	report, err := o.ReconcileCollections(context.Background(), true, true)
+	if err != nil {
+		t.Fatalf("ReconcileCollections failed: %v", err)
+	}
+	if report.Applied || len(report.Actions) == 0 {
+		t.Fatalf("expected planned actions only, got %+v", report)
+	}
+	if fakeExec.collectionAddCalls != 0 || len(fakeExec.collectionRemoveCalls) != 0 {
+		t.Fatalf("dry run changed the index: adds=%d removes=%v", fakeExec.collectionAddCalls, fakeExec.collectionRemoveCalls)
+	}
+}
+
+func TestReconcileCollections_RepairRemovesAndReAdds(t *testing.T) {
+	o, fakeExec := newDriftFixture()
+
+	report, err := o.ReconcileCollections(context.Background(), true, false)
+	if err != nil {
+		t.Fatalf("ReconcileCollections failed: %v", err)
+	}
+	if !report.Applied {
+		t.Fatalf("expected repair to be applied: %+v", report)
+	}
+	removed := slices.Sorted(slices.Values(fakeExec.collectionRemoveCalls))
+	if !slices.Equal(removed, []string{"beta", "gamma", "stale"}) {
+		t.Fatalf("unexpected removals: %v", removed)
+	}
+	if fakeExec.collectionAddCalls != 3 {
+		t.Fatalf("expected beta, gamma and delta added, got %d adds", fakeExec.collectionAddCalls)
+	}
+	if _, ok := fakeExec.contexts["/b"]; ok {
+		t.Fatalf("expected context of the old beta path removed: %v", fakeExec.contexts)
+	}
+	if fakeExec.contexts["/b2"] != "ctx-b" {
+		t.Fatalf("expected context registered at the new beta path: %v", fakeExec.contexts)
+	}
+
+	drift, err := o.DetectDrift(context.Background())
+	if err != nil || len(drift) != 0 {
+		t.Fatalf("expected no drift after repair, got %+v err=%v", drift, err)
+	}
+}
@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/model"
+)
+
+type fakeRewriteExec struct {
+	*fakeEnsureExec
+	queries []string
+	hits    map[string][]model.SearchResult
+}
+
+func (f *fakeRewriteExec) Search(_ context.Context, query string, _ executor.SearchOpts) ([]model.SearchResult, error) {
+	f.queries = append(f.queries, query)
+	return f.hits[query], nil
+}
+
+const verboseQuery = "Can you please explain how we should configure the traffic shaping on the OpenWrt router to fix video call lag"
+
+func newRewriteTestOrchestrator(exec *fakeRewriteExec) *Orchestrator {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{Name: "notes", Path: "/notes", Tier: 1}},
+		Search: config.SearchConfig{
+			TopK:            10,
+			MinScore:        0.3,
+			HybridRRFK:      60,
+			RewriteMinWords: 10,
+			RewriteMaxTerms: 6,
+		},
+	}
+	return New(cfg, exec, nil, testLogger())
+}
+
+func TestSearch_RewritesDowngradedVerboseQuery(t *testing.T) {
+	exec := &fakeRewriteExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		hits: map[string][]model.SearchResult{
+			"configure traffic shaping openwrt router video": {{File: "qmd://notes/sqm.md", Score: 0.8}},
+			"configure traffic shaping":                      {{File: "qmd://notes/qos.md", Score: 0.9}, {File: "qmd://notes/sqm.md", Score: 0.7}},
+		},
+	}
+	o := newRewriteTestOrchestrator(exec)
+
+	// vsearch is unavailable, so the search is served by BM25.
+	res, err := o.Search(context.Background(), SearchParams{Query: verboseQuery, Mode: "vsearch", Collection: "notes"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	want := []string{"configure traffic shaping openwrt router video", "configure traffic shaping"}
+	if !reflect.DeepEqual(exec.queries, want) {
+		t.Fatalf("bm25 queries = %q, want %q", exec.queries, want)
+	}
+	if !reflect.DeepEqual(res.Meta.BM25Queries, want) {
+		t.Errorf("meta bm25 queries = %q, want %q", res.Meta.BM25Queries, want)
+	}
+	if len(res.Results) != 2 || res.Results[0].File != "qmd://notes/sqm.md" {
+		t.Errorf("expected the doc found by both queries first, got %+v", res.Results)
+	}
+}
+
+func TestSearch_CachedAnswerKeepsBM25Queries(t *testing.T) {
+	exec := &fakeRewriteExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		hits: map[string][]model.SearchResult{
+			"configure traffic shaping": {{File: "qmd://notes/qos.md", Score: 0.9}},
+		},
+	}
+	o := newRewriteTestOrchestrator(exec)
+	o.cache = cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+
+	params := SearchParams{Query: verboseQuery, Mode: "vsearch", Collection: "notes"}
+	first, err := o.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	cached, err := o.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("cached Search failed: %v", err)
+	}
+	if !cached.Meta.CacheHit {
+		t.Fatalf("expected the repeated search to hit the cache")
+	}
+	if len(first.Meta.BM25Queries) == 0 || !reflect.DeepEqual(cached.Meta.BM25Queries, first.Meta.BM25Queries) {
+		t.Errorf("cached bm25 queries = %q, want %q", cached.Meta.BM25Queries, first.Meta.BM25Queries)
+	}
+}
+
+func TestSearch_RewritesOnlyDowngradedVerboseQueries(t *testing.T) {
+	cases := []struct {
+		name   string
+		params SearchParams
+		want   string
+		meta   []string
+	}{
+		{"explicit core", SearchParams{Query: verboseQuery, Mode: "search"}, verboseQuery, nil},
+		{"short query", SearchParams{Query: "traffic shaping openwrt", Mode: "vsearch"}, "traffic shaping openwrt", nil},
+		{"forced by caller", SearchParams{Query: verboseQuery, Mode: "search", Downgraded: true}, "configure traffic shaping openwrt router video", []string{"configure traffic shaping openwrt router video", "configure traffic shaping"}},
+	}
+	for _, tc := range cases {
+		exec := &fakeRewriteExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+		o := newRewriteTestOrchestrator(exec)
+		tc.params.Collection = "notes"
+		res, err := o.Search(context.Background(), tc.params)
+		if err != nil {
+			t.Fatalf("%s: Search failed: %v", tc.name, err)
+		}
+		if len(exec.queries) == 0 || exec.queries[0] != tc.want {
+			t.Errorf("%s: bm25 queries = %q, want %q first", tc.name, exec.queries, tc.want)
+		}
+		if !reflect.DeepEqual(res.Meta.BM25Queries, tc.meta) {
+			t.Errorf("%s: meta bm25 queries = %q, want %q", tc.name, res.Meta.BM25Queries, tc.meta)
+		}
+	}
+}
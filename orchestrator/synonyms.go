@@ -0,0 +1,170 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/internal/searchutil"
+	"qmdsr/internal/synonym"
+	"qmdsr/model"
+	"qmdsr/router"
+)
+
+// synonymsChanged reports whether a reload changes how BM25 queries are
+// expanded. Collection synonyms are covered by the per-collection
+// invalidation of the reload.
+func synonymsChanged(cur, next *config.Config) bool {
+	return !reflect.DeepEqual(cur.Search.Synonyms, next.Search.Synonyms) ||
+		cur.Search.Aliases != next.Search.Aliases ||
+		cur.Search.SynonymMaxVariants != next.Search.SynonymMaxVariants ||
+		cur.Search.SynonymWeight != next.Search.SynonymWeight
+}
+
+func (o *Orchestrator) resetExpanders() {
+	o.synMu.Lock()
+	o.expanders = nil
+	o.synMu.Unlock()
+}
+
+// expander merges the global synonyms, the collection's synonyms and the
+// aliases harvested from its notes.
+func (o *Orchestrator) expander(collection string) *synonym.Expander {
+	cfg := o.config()
+	o.synMu.Lock()
+	defer o.synMu.Unlock()
+	if e, ok := o.expanders[collection]; ok {
+		return e
+	}
+	dicts := []synonym.Dict{cfg.Search.Synonyms}
+	if col := o.findCollection(collection); col != nil {
+		dicts = append(dicts, col.Synonyms)
+	}
+	if cfg.Search.Aliases == "frontmatter" {
+		dicts = append(dicts, o.aliases[collection])
+	}
+	e := synonym.NewExpander(dicts...)
+	if o.expanders == nil {
+		o.expanders = make(map[string]*synonym.Expander)
+	}
+	o.expanders[collection] = e
+	return e
+}
+
+// RefreshAliases harvests the aliases: frontmatter of the notes in the named
+// collections, or in every collection when names is empty.
+func (o *Orchestrator) RefreshAliases(ctx context.Context, names []string) {
+	cfg := o.config()
+	if cfg.Search.Aliases != "frontmatter" {
+		return
+	}
+	harvested := make(map[string]synonym.Dict)
+	for _, col := range cfg.Collections {
+		if len(names) > 0 && !slices.Contains(names, col.Name) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		d, err := synonym.HarvestAliases(col.Path, col.Mask, col.Exclude)
+		if err != nil {
+			o.log.Warn("alias harvest failed", "collection", col.Name, "err", err)
+			continue
+		}
+		harvested[col.Name] = d
+	}
+
+	o.synMu.Lock()
+	if o.aliases == nil {
+		o.aliases = make(map[string]synonym.Dict)
+	}
+	for name, d := range harvested {
+		o.aliases[name] = d
+		delete(o.expanders, name)
+	}
+	o.synMu.Unlock()
+	for name, d := range harvested {
+		o.log.Debug("aliases harvested", "collection", name, "groups", len(d))
+	}
+}
+
+// bm25Plan lists the BM25 queries for one collection with their fusion
+// weights: the keyword rewrites or the segmented query, each followed by its
+// synonym variants.
+func (o *Orchestrator) bm25Plan(query, collection string, params SearchParams) ([]string, []float64, []synonym.Expansion) {
+	cfg := o.config()
+	base := params.keywordQueries
+	if len(base) == 0 {
+		base = []string{o.BM25Query(query)}
+	}
+	var exp *synonym.Expander
+	if cfg.Search.SynonymMaxVariants > 0 {
+		exp = o.expander(collection)
+	}
+
+	var queries []string
+	var weights []float64
+	var expansions []synonym.Expansion
+	for _, q := range base {
+		queries = append(queries, q)
+		weights = append(weights, 1)
+		variants, x := exp.Expand(q, cfg.Search.SynonymMaxVariants)
+		for _, v := range variants {
+			if !slices.Contains(queries, v) {
+				queries = append(queries, v)
+				weights = append(weights, cfg.Search.SynonymWeight)
+			}
+		}
+		expansions = append(expansions, x...)
+	}
+	return queries, weights, expansions
+}
+
+// execBM25 runs the BM25 queries of bm25Plan and fuses their results with
+// weighted reciprocal rank fusion. Failing queries are skipped.
+func (o *Orchestrator) execBM25(ctx context.Context, query string, params SearchParams, opts executor.SearchOpts) ([]model.SearchResult, error) {
+	queries, weights, _ := o.bm25Plan(query, opts.Collection, params)
+	if len(queries) == 1 {
+		return o.exec.Search(ctx, queries[0], opts)
+	}
+	lists := make([][]model.SearchResult, 0, len(queries))
+	listWeights := make([]float64, 0, len(queries))
+	var firstErr error
+	for i, q := range queries {
+		results, err := o.exec.Search(ctx, q, opts)
+		if err != nil {
+			o.log.Warn("bm25 query failed", "collection", opts.Collection, "query", q, "err", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		lists = append(lists, results)
+		listWeights = append(listWeights, weights[i])
+	}
+	if len(lists) == 0 {
+		return nil, fmt.Errorf("bm25 search failed: %w", firstErr)
+	}
+	return searchutil.FuseRRF(lists, listWeights, o.config().Search.HybridRRFK), nil
+}
+
+// expansions describes the synonym expansions BM25 used for a search
+// served in mode from the searched collections.
+func (o *Orchestrator) expansions(params SearchParams, mode string, searched []string) []string {
+	if mode != string(router.ModeSearch) && mode != string(router.ModeHybrid) {
+		return nil
+	}
+	var out []string
+	for _, collection := range searched {
+		_, _, expansions := o.bm25Plan(params.Query, collection, params)
+		for _, x := range expansions {
+			if s := x.String(); !slices.Contains(out, s) {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/internal/synonym"
+	"qmdsr/model"
+)
+
+func newSynonymTestOrchestrator(t *testing.T, exec *fakeRewriteExec) *Orchestrator {
+	t.Helper()
+	dir := t.TempDir()
+	note := "---\naliases: [GTD]\n---\n# Getting Things Done\n"
+	if err := os.WriteFile(filepath.Join(dir, "Getting Things Done.md"), []byte(note), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{
+			Name: "notes", Path: dir, Mask: "**/*.md", Tier: 1,
+			Synonyms: synonym.Dict{{"qos", "traffic shaping"}},
+		}},
+		Search: config.SearchConfig{
+			TopK:               10,
+			MinScore:           0.3,
+			HybridRRFK:         60,
+			Synonyms:           synonym.Dict{{"k8s", "kubernetes"}},
+			Aliases:            "frontmatter",
+			SynonymMaxVariants: 4,
+			SynonymWeight:      0.5,
+		},
+	}
+	return New(cfg, exec, nil, testLogger())
+}
+
+func TestSearch_ExpandsBM25QueryWithSynonyms(t *testing.T) {
+	exec := &fakeRewriteExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		hits: map[string][]model.SearchResult{
+			"k8s qos":             {{File: "qmd://notes/a.md", Score: 0.9}, {File: "qmd://notes/b.md", Score: 0.7}},
+			"kubernetes qos":      {{File: "qmd://notes/b.md", Score: 0.9}},
+			"k8s traffic shaping": {{File: "qmd://notes/b.md", Score: 0.8}},
+		},
+	}
+	o := newSynonymTestOrchestrator(t, exec)
+
+	res, err := o.Search(context.Background(), SearchParams{Query: "k8s qos", Mode: "search", Collection: "notes"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	want := []string{"k8s qos", "kubernetes qos", "k8s traffic shaping"}
+	if !reflect.DeepEqual(exec.queries, want) {
+		t.Fatalf("bm25 queries = %q, want %q", exec.queries, want)
+	}
+	if got, want := res.Meta.Expansions, []string{"k8s->kubernetes", "qos->traffic shaping"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expansions = %q, want %q", got, want)
+	}
+	if len(res.Results) != 2 || res.Results[0].File != "qmd://notes/b.md" {
+		t.Errorf("expected the doc the variants also matched first, got %+v", res.Results)
+	}
+}
+
+func TestSearch_CachedAnswerKeepsExpansions(t *testing.T) {
+	exec := &fakeRewriteExec{
+		fakeEnsureExec: newFakeEnsureExec(nil, nil),
+		hits:           map[string][]model.SearchResult{"kubernetes qos": {{File: "qmd://notes/b.md", Score: 0.9}}},
+	}
+	o := newSynonymTestOrchestrator(t, exec)
+	o.cache = cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+
+	params := SearchParams{Query: "k8s qos", Mode: "search", Collection: "notes"}
+	if _, err := o.Search(context.Background(), params); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	cached, err := o.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("cached Search failed: %v", err)
+	}
+	if !cached.Meta.CacheHit {
+		t.Fatalf("expected the repeated search to hit the cache")
+	}
+	if want := []string{"k8s->kubernetes", "qos->traffic shaping"}; !reflect.DeepEqual(cached.Meta.Expansions, want) {
+		t.Errorf("cached expansions = %q, want %q", cached.Meta.Expansions, want)
+	}
+}
+
+func TestSearch_ExpandsHarvestedAliasesAfterRefresh(t *testing.T) {
+	exec := &fakeRewriteExec{fakeEnsureExec: newFakeEnsureExec(nil, nil)}
+	o := newSynonymTestOrchestrator(t, exec)
+	search := func() []string {
+		exec.queries = nil
+		if _, err := o.Search(context.Background(), SearchParams{Query: "gtd weekly review", Mode: "search", Collection: "notes"}); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return exec.queries
+	}
+
+	if got := search(); !reflect.DeepEqual(got, []string{"gtd weekly review"}) {
+		t.Fatalf("before harvest: bm25 queries = %q", got)
+	}
+	o.RefreshAliases(context.Background(), []string{"notes"})
+	want := []string{"gtd weekly review", "getting things done weekly review"}
+	if got := search(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after harvest: bm25 queries = %q, want %q", got, want)
+	}
+
+	next := *o.config()
+	next.Search.SynonymMaxVariants = -1
+	o.ApplyConfig(&next)
+	if got := search(); !reflect.DeepEqual(got, []string{"gtd weekly review"}) {
+		t.Errorf("with expansion disabled: bm25 queries = %q", got)
+	}
+}
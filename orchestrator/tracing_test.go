@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+	return exp
+}
+
+func TestSearch_SpansJoinIncomingTrace(t *testing.T) {
+	exp := installTestTracer(t)
+	o, _ := newTierCacheTest(map[string]bool{"notes": true})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	carrier := propagation.MapCarrier{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+
+	if _, err := o.Search(ctx, SearchParams{Query: "plan", Mode: "search"}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	collections := map[string]bool{}
+	var resolved bool
+	for _, s := range exp.GetSpans() {
+		if got := s.SpanContext.TraceID().String(); got != traceID {
+			t.Fatalf("span %s has trace %s, want %s", s.Name, got, traceID)
+		}
+		switch s.Name {
+		case "orchestrator.resolve_mode":
+			resolved = true
+		case "orchestrator.search_collection":
+			for _, a := range s.Attributes {
+				if a.Key == "qmdsr.collection" {
+					collections[a.Value.AsString()] = true
+				}
+			}
+		}
+	}
+	if !resolved {
+		t.Fatalf("missing resolve_mode span")
+	}
+	if !collections["notes"] || !collections["memory"] {
+		t.Fatalf("expected a search_collection span per tier-1 collection, got %v", collections)
+	}
+}
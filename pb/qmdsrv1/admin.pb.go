@@ -22,6 +22,61 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type JobState int32
+
+const (
+	JobState_JOB_UNSPECIFIED JobState = 0
+	JobState_JOB_RUNNING     JobState = 1
+	JobState_JOB_SUCCEEDED   JobState = 2
+	JobState_JOB_FAILED      JobState = 3
+	JobState_JOB_CANCELLED   JobState = 4
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_UNSPECIFIED",
+		1: "JOB_RUNNING",
+		2: "JOB_SUCCEEDED",
+		3: "JOB_FAILED",
+		4: "JOB_CANCELLED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_UNSPECIFIED": 0,
+		"JOB_RUNNING":     1,
+		"JOB_SUCCEEDED":   2,
+		"JOB_FAILED":      3,
+		"JOB_CANCELLED":   4,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_qmdsr_v1_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_qmdsr_v1_admin_proto_enumTypes[0]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
 type EmbedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Force         bool                   `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`
@@ -67,11 +122,13 @@ func (x *EmbedRequest) GetForce() bool {
 }
 
 type OpResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	TraceId       string                 `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
-	LatencyMs     int64                  `protobuf:"varint,4,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Ok        bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Message   string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TraceId   string                 `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	LatencyMs int64                  `protobuf:"varint,4,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// Set by Reindex and Embed, which run as background jobs.
+	JobId         string `protobuf:"bytes,5,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,6 +191,13 @@ func (x *OpResponse) GetLatencyMs() int64 {
 	return 0
 }
 
+func (x *OpResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
 type CollectionInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -262,30 +326,1300 @@ func (x *CollectionsResponse) GetLatencyMs() int64 {
 	return 0
 }
 
-var File_qmdsr_v1_admin_proto protoreflect.FileDescriptor
+type ReloadConfigResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Changed            bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	CollectionsAdded   []string               `protobuf:"bytes,2,rep,name=collections_added,json=collectionsAdded,proto3" json:"collections_added,omitempty"`
+	CollectionsRemoved []string               `protobuf:"bytes,3,rep,name=collections_removed,json=collectionsRemoved,proto3" json:"collections_removed,omitempty"`
+	CollectionsChanged []string               `protobuf:"bytes,4,rep,name=collections_changed,json=collectionsChanged,proto3" json:"collections_changed,omitempty"`
+	Sections           []string               `protobuf:"bytes,5,rep,name=sections,proto3" json:"sections,omitempty"`
+	RestartRequired    []string               `protobuf:"bytes,6,rep,name=restart_required,json=restartRequired,proto3" json:"restart_required,omitempty"`
+	TraceId            string                 `protobuf:"bytes,7,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	LatencyMs          int64                  `protobuf:"varint,8,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
 
-const file_qmdsr_v1_admin_proto_rawDesc = "" +
-	"\n" +
-	"\x14qmdsr/v1/admin.proto\x12\bqmdsr.v1\x1a\x1bgoogle/protobuf/empty.proto\"$\n" +
-	"\fEmbedRequest\x12\x14\n" +
-	"\x05force\x18\x01 \x01(\bR\x05force\"p\n" +
-	"\n" +
-	"OpResponse\x12\x0e\n" +
-	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
-	"\btrace_id\x18\x03 \x01(\tR\atraceId\x12\x1d\n" +
-	"\n" +
-	"latency_ms\x18\x04 \x01(\x03R\tlatencyMs\"b\n" +
-	"\x0eCollectionInfo\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
-	"\x04mask\x18\x03 \x01(\tR\x04mask\x12\x14\n" +
-	"\x05files\x18\x04 \x01(\x05R\x05files\"\x8b\x01\n" +
-	"\x13CollectionsResponse\x12:\n" +
-	"\vcollections\x18\x01 \x03(\v2\x18.qmdsr.v1.CollectionInfoR\vcollections\x12\x19\n" +
-	"\btrace_id\x18\x02 \x01(\tR\atraceId\x12\x1d\n" +
+func (x *ReloadConfigResponse) Reset() {
+	*x = ReloadConfigResponse{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigResponse) ProtoMessage() {}
+
+func (x *ReloadConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigResponse.ProtoReflect.Descriptor instead.
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReloadConfigResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *ReloadConfigResponse) GetCollectionsAdded() []string {
+	if x != nil {
+		return x.CollectionsAdded
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetCollectionsRemoved() []string {
+	if x != nil {
+		return x.CollectionsRemoved
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetCollectionsChanged() []string {
+	if x != nil {
+		return x.CollectionsChanged
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetSections() []string {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetRestartRequired() []string {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *ReloadConfigResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+type CollectionSpec struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path            string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Mask            string                 `protobuf:"bytes,3,opt,name=mask,proto3" json:"mask,omitempty"`
+	Exclude         []string               `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	Context         string                 `protobuf:"bytes,5,opt,name=context,proto3" json:"context,omitempty"`
+	Tier            int32                  `protobuf:"varint,6,opt,name=tier,proto3" json:"tier,omitempty"`
+	Embed           bool                   `protobuf:"varint,7,opt,name=embed,proto3" json:"embed,omitempty"`
+	RequireExplicit bool                   `protobuf:"varint,8,opt,name=require_explicit,json=requireExplicit,proto3" json:"require_explicit,omitempty"`
+	SafetyPrompt    bool                   `protobuf:"varint,9,opt,name=safety_prompt,json=safetyPrompt,proto3" json:"safety_prompt,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CollectionSpec) Reset() {
+	*x = CollectionSpec{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionSpec) ProtoMessage() {}
+
+func (x *CollectionSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionSpec.ProtoReflect.Descriptor instead.
+func (*CollectionSpec) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CollectionSpec) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CollectionSpec) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CollectionSpec) GetMask() string {
+	if x != nil {
+		return x.Mask
+	}
+	return ""
+}
+
+func (x *CollectionSpec) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *CollectionSpec) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *CollectionSpec) GetTier() int32 {
+	if x != nil {
+		return x.Tier
+	}
+	return 0
+}
+
+func (x *CollectionSpec) GetEmbed() bool {
+	if x != nil {
+		return x.Embed
+	}
+	return false
+}
+
+func (x *CollectionSpec) GetRequireExplicit() bool {
+	if x != nil {
+		return x.RequireExplicit
+	}
+	return false
+}
+
+func (x *CollectionSpec) GetSafetyPrompt() bool {
+	if x != nil {
+		return x.SafetyPrompt
+	}
+	return false
+}
+
+// Unset fields keep their current value. exclude is replaced only when
+// replace_exclude is true, so an empty list can clear it.
+type UpdateCollectionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path            *string                `protobuf:"bytes,2,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	Mask            *string                `protobuf:"bytes,3,opt,name=mask,proto3,oneof" json:"mask,omitempty"`
+	Exclude         []string               `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	ReplaceExclude  bool                   `protobuf:"varint,5,opt,name=replace_exclude,json=replaceExclude,proto3" json:"replace_exclude,omitempty"`
+	Context         *string                `protobuf:"bytes,6,opt,name=context,proto3,oneof" json:"context,omitempty"`
+	Tier            *int32                 `protobuf:"varint,7,opt,name=tier,proto3,oneof" json:"tier,omitempty"`
+	Embed           *bool                  `protobuf:"varint,8,opt,name=embed,proto3,oneof" json:"embed,omitempty"`
+	RequireExplicit *bool                  `protobuf:"varint,9,opt,name=require_explicit,json=requireExplicit,proto3,oneof" json:"require_explicit,omitempty"`
+	SafetyPrompt    *bool                  `protobuf:"varint,10,opt,name=safety_prompt,json=safetyPrompt,proto3,oneof" json:"safety_prompt,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateCollectionRequest) Reset() {
+	*x = UpdateCollectionRequest{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCollectionRequest) ProtoMessage() {}
+
+func (x *UpdateCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCollectionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateCollectionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetMask() string {
+	if x != nil && x.Mask != nil {
+		return *x.Mask
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *UpdateCollectionRequest) GetReplaceExclude() bool {
+	if x != nil {
+		return x.ReplaceExclude
+	}
+	return false
+}
+
+func (x *UpdateCollectionRequest) GetContext() string {
+	if x != nil && x.Context != nil {
+		return *x.Context
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetTier() int32 {
+	if x != nil && x.Tier != nil {
+		return *x.Tier
+	}
+	return 0
+}
+
+func (x *UpdateCollectionRequest) GetEmbed() bool {
+	if x != nil && x.Embed != nil {
+		return *x.Embed
+	}
+	return false
+}
+
+func (x *UpdateCollectionRequest) GetRequireExplicit() bool {
+	if x != nil && x.RequireExplicit != nil {
+		return *x.RequireExplicit
+	}
+	return false
+}
+
+func (x *UpdateCollectionRequest) GetSafetyPrompt() bool {
+	if x != nil && x.SafetyPrompt != nil {
+		return *x.SafetyPrompt
+	}
+	return false
+}
+
+type RemoveCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCollectionRequest) Reset() {
+	*x = RemoveCollectionRequest{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCollectionRequest) ProtoMessage() {}
+
+func (x *RemoveCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCollectionRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoveCollectionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CollectionChangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Collection    *CollectionSpec        `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,5,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionChangeResponse) Reset() {
+	*x = CollectionChangeResponse{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionChangeResponse) ProtoMessage() {}
+
+func (x *CollectionChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionChangeResponse.ProtoReflect.Descriptor instead.
+func (*CollectionChangeResponse) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CollectionChangeResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CollectionChangeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CollectionChangeResponse) GetCollection() *CollectionSpec {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+func (x *CollectionChangeResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *CollectionChangeResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+// Without repair only drift is reported. dry_run lists the repair actions
+// without running them.
+type ReconcileCollectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repair        bool                   `protobuf:"varint,1,opt,name=repair,proto3" json:"repair,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileCollectionsRequest) Reset() {
+	*x = ReconcileCollectionsRequest{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileCollectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileCollectionsRequest) ProtoMessage() {}
+
+func (x *ReconcileCollectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileCollectionsRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileCollectionsRequest) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReconcileCollectionsRequest) GetRepair() bool {
+	if x != nil {
+		return x.Repair
+	}
+	return false
+}
+
+func (x *ReconcileCollectionsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type CollectionDrift struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// missing / unmanaged / path / mask
+	Kinds          []string `protobuf:"bytes,2,rep,name=kinds,proto3" json:"kinds,omitempty"`
+	ConfiguredPath string   `protobuf:"bytes,3,opt,name=configured_path,json=configuredPath,proto3" json:"configured_path,omitempty"`
+	RegisteredPath string   `protobuf:"bytes,4,opt,name=registered_path,json=registeredPath,proto3" json:"registered_path,omitempty"`
+	ConfiguredMask string   `protobuf:"bytes,5,opt,name=configured_mask,json=configuredMask,proto3" json:"configured_mask,omitempty"`
+	RegisteredMask string   `protobuf:"bytes,6,opt,name=registered_mask,json=registeredMask,proto3" json:"registered_mask,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CollectionDrift) Reset() {
+	*x = CollectionDrift{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionDrift) ProtoMessage() {}
+
+func (x *CollectionDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionDrift.ProtoReflect.Descriptor instead.
+func (*CollectionDrift) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CollectionDrift) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CollectionDrift) GetKinds() []string {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+func (x *CollectionDrift) GetConfiguredPath() string {
+	if x != nil {
+		return x.ConfiguredPath
+	}
+	return ""
+}
+
+func (x *CollectionDrift) GetRegisteredPath() string {
+	if x != nil {
+		return x.RegisteredPath
+	}
+	return ""
+}
+
+func (x *CollectionDrift) GetConfiguredMask() string {
+	if x != nil {
+		return x.ConfiguredMask
+	}
+	return ""
+}
+
+func (x *CollectionDrift) GetRegisteredMask() string {
+	if x != nil {
+		return x.RegisteredMask
+	}
+	return ""
+}
+
+type ReconcileCollectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Drift         []*CollectionDrift     `protobuf:"bytes,1,rep,name=drift,proto3" json:"drift,omitempty"`
+	Actions       []string               `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+	Applied       bool                   `protobuf:"varint,3,opt,name=applied,proto3" json:"applied,omitempty"`
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,5,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileCollectionsResponse) Reset() {
+	*x = ReconcileCollectionsResponse{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileCollectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileCollectionsResponse) ProtoMessage() {}
+
+func (x *ReconcileCollectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileCollectionsResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileCollectionsResponse) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReconcileCollectionsResponse) GetDrift() []*CollectionDrift {
+	if x != nil {
+		return x.Drift
+	}
+	return nil
+}
+
+func (x *ReconcileCollectionsResponse) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *ReconcileCollectionsResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *ReconcileCollectionsResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *ReconcileCollectionsResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Job struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// reindex / embed / embed_full
+	Kind            string   `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	State           JobState `protobuf:"varint,3,opt,name=state,proto3,enum=qmdsr.v1.JobState" json:"state,omitempty"`
+	StartedAtUnixMs int64    `protobuf:"varint,4,opt,name=started_at_unix_ms,json=startedAtUnixMs,proto3" json:"started_at_unix_ms,omitempty"`
+	EndedAtUnixMs   int64    `protobuf:"varint,5,opt,name=ended_at_unix_ms,json=endedAtUnixMs,proto3" json:"ended_at_unix_ms,omitempty"`
+	Retries         int32    `protobuf:"varint,6,opt,name=retries,proto3" json:"retries,omitempty"`
+	// Last 4 KiB of qmd stderr.
+	StderrTail    string `protobuf:"bytes,7,opt,name=stderr_tail,json=stderrTail,proto3" json:"stderr_tail,omitempty"`
+	Error         string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	TraceId       string `protobuf:"bytes,9,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Job) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_UNSPECIFIED
+}
+
+func (x *Job) GetStartedAtUnixMs() int64 {
+	if x != nil {
+		return x.StartedAtUnixMs
+	}
+	return 0
+}
+
+func (x *Job) GetEndedAtUnixMs() int64 {
+	if x != nil {
+		return x.EndedAtUnixMs
+	}
+	return 0
+}
+
+func (x *Job) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *Job) GetStderrTail() string {
+	if x != nil {
+		return x.StderrTail
+	}
+	return ""
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*Job                 `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListJobsResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// One finished run of a scheduled or admin-triggered task, retries included.
+type TaskRun struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	StartUnixMs int64                  `protobuf:"varint,1,opt,name=start_unix_ms,json=startUnixMs,proto3" json:"start_unix_ms,omitempty"`
+	DurationMs  int64                  `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Attempts    int32                  `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error       string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	JobId       string                 `protobuf:"bytes,5,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Versions recorded in the result cache for collections whose files
+	// changed (reindex tasks only).
+	CacheVersions map[string]string `protobuf:"bytes,6,rep,name=cache_versions,json=cacheVersions,proto3" json:"cache_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskRun) Reset() {
+	*x = TaskRun{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRun) ProtoMessage() {}
+
+func (x *TaskRun) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRun.ProtoReflect.Descriptor instead.
+func (*TaskRun) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskRun) GetStartUnixMs() int64 {
+	if x != nil {
+		return x.StartUnixMs
+	}
+	return 0
+}
+
+func (x *TaskRun) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TaskRun) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *TaskRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskRun) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TaskRun) GetCacheVersions() map[string]string {
+	if x != nil {
+		return x.CacheVersions
+	}
+	return nil
+}
+
+type SchedulerTask struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Interval or cron expression; empty for tasks without a schedule.
+	Schedule        string `protobuf:"bytes,2,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	LastRunUnix     int64  `protobuf:"varint,3,opt,name=last_run_unix,json=lastRunUnix,proto3" json:"last_run_unix,omitempty"`
+	NextRunUnix     int64  `protobuf:"varint,4,opt,name=next_run_unix,json=nextRunUnix,proto3" json:"next_run_unix,omitempty"`
+	LastSuccessUnix int64  `protobuf:"varint,5,opt,name=last_success_unix,json=lastSuccessUnix,proto3" json:"last_success_unix,omitempty"`
+	Running         bool   `protobuf:"varint,6,opt,name=running,proto3" json:"running,omitempty"`
+	Stale           bool   `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Newest first.
+	History       []*TaskRun `protobuf:"bytes,8,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerTask) Reset() {
+	*x = SchedulerTask{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerTask) ProtoMessage() {}
+
+func (x *SchedulerTask) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerTask.ProtoReflect.Descriptor instead.
+func (*SchedulerTask) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SchedulerTask) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SchedulerTask) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *SchedulerTask) GetLastRunUnix() int64 {
+	if x != nil {
+		return x.LastRunUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTask) GetNextRunUnix() int64 {
+	if x != nil {
+		return x.NextRunUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTask) GetLastSuccessUnix() int64 {
+	if x != nil {
+		return x.LastSuccessUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTask) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *SchedulerTask) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *SchedulerTask) GetHistory() []*TaskRun {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type SchedulerStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*SchedulerTask       `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	TraceId       string                 `protobuf:"bytes,2,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerStatusResponse) Reset() {
+	*x = SchedulerStatusResponse{}
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerStatusResponse) ProtoMessage() {}
+
+func (x *SchedulerStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerStatusResponse.ProtoReflect.Descriptor instead.
+func (*SchedulerStatusResponse) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SchedulerStatusResponse) GetTasks() []*SchedulerTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *SchedulerStatusResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *SchedulerStatusResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+var File_qmdsr_v1_admin_proto protoreflect.FileDescriptor
+
+const file_qmdsr_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14qmdsr/v1/admin.proto\x12\bqmdsr.v1\x1a\x1bgoogle/protobuf/empty.proto\"$\n" +
+	"\fEmbedRequest\x12\x14\n" +
+	"\x05force\x18\x01 \x01(\bR\x05force\"\x87\x01\n" +
+	"\n" +
+	"OpResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\btrace_id\x18\x03 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x04 \x01(\x03R\tlatencyMs\x12\x15\n" +
+	"\x06job_id\x18\x05 \x01(\tR\x05jobId\"b\n" +
+	"\x0eCollectionInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mask\x18\x03 \x01(\tR\x04mask\x12\x14\n" +
+	"\x05files\x18\x04 \x01(\x05R\x05files\"\x8b\x01\n" +
+	"\x13CollectionsResponse\x12:\n" +
+	"\vcollections\x18\x01 \x03(\v2\x18.qmdsr.v1.CollectionInfoR\vcollections\x12\x19\n" +
+	"\btrace_id\x18\x02 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\"\xc0\x02\n" +
+	"\x14ReloadConfigResponse\x12\x18\n" +
+	"\achanged\x18\x01 \x01(\bR\achanged\x12+\n" +
+	"\x11collections_added\x18\x02 \x03(\tR\x10collectionsAdded\x12/\n" +
+	"\x13collections_removed\x18\x03 \x03(\tR\x12collectionsRemoved\x12/\n" +
+	"\x13collections_changed\x18\x04 \x03(\tR\x12collectionsChanged\x12\x1a\n" +
+	"\bsections\x18\x05 \x03(\tR\bsections\x12)\n" +
+	"\x10restart_required\x18\x06 \x03(\tR\x0frestartRequired\x12\x19\n" +
+	"\btrace_id\x18\a \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\b \x01(\x03R\tlatencyMs\"\xfa\x01\n" +
+	"\x0eCollectionSpec\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mask\x18\x03 \x01(\tR\x04mask\x12\x18\n" +
+	"\aexclude\x18\x04 \x03(\tR\aexclude\x12\x18\n" +
+	"\acontext\x18\x05 \x01(\tR\acontext\x12\x12\n" +
+	"\x04tier\x18\x06 \x01(\x05R\x04tier\x12\x14\n" +
+	"\x05embed\x18\a \x01(\bR\x05embed\x12)\n" +
+	"\x10require_explicit\x18\b \x01(\bR\x0frequireExplicit\x12#\n" +
+	"\rsafety_prompt\x18\t \x01(\bR\fsafetyPrompt\"\xa7\x03\n" +
+	"\x17UpdateCollectionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\x04path\x18\x02 \x01(\tH\x00R\x04path\x88\x01\x01\x12\x17\n" +
+	"\x04mask\x18\x03 \x01(\tH\x01R\x04mask\x88\x01\x01\x12\x18\n" +
+	"\aexclude\x18\x04 \x03(\tR\aexclude\x12'\n" +
+	"\x0freplace_exclude\x18\x05 \x01(\bR\x0ereplaceExclude\x12\x1d\n" +
+	"\acontext\x18\x06 \x01(\tH\x02R\acontext\x88\x01\x01\x12\x17\n" +
+	"\x04tier\x18\a \x01(\x05H\x03R\x04tier\x88\x01\x01\x12\x19\n" +
+	"\x05embed\x18\b \x01(\bH\x04R\x05embed\x88\x01\x01\x12.\n" +
+	"\x10require_explicit\x18\t \x01(\bH\x05R\x0frequireExplicit\x88\x01\x01\x12(\n" +
+	"\rsafety_prompt\x18\n" +
+	" \x01(\bH\x06R\fsafetyPrompt\x88\x01\x01B\a\n" +
+	"\x05_pathB\a\n" +
+	"\x05_maskB\n" +
+	"\n" +
+	"\b_contextB\a\n" +
+	"\x05_tierB\b\n" +
+	"\x06_embedB\x13\n" +
+	"\x11_require_explicitB\x10\n" +
+	"\x0e_safety_prompt\"-\n" +
+	"\x17RemoveCollectionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xb8\x01\n" +
+	"\x18CollectionChangeResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x128\n" +
+	"\n" +
+	"collection\x18\x03 \x01(\v2\x18.qmdsr.v1.CollectionSpecR\n" +
+	"collection\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x05 \x01(\x03R\tlatencyMs\"N\n" +
+	"\x1bReconcileCollectionsRequest\x12\x16\n" +
+	"\x06repair\x18\x01 \x01(\bR\x06repair\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xdf\x01\n" +
+	"\x0fCollectionDrift\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05kinds\x18\x02 \x03(\tR\x05kinds\x12'\n" +
+	"\x0fconfigured_path\x18\x03 \x01(\tR\x0econfiguredPath\x12'\n" +
+	"\x0fregistered_path\x18\x04 \x01(\tR\x0eregisteredPath\x12'\n" +
+	"\x0fconfigured_mask\x18\x05 \x01(\tR\x0econfiguredMask\x12'\n" +
+	"\x0fregistered_mask\x18\x06 \x01(\tR\x0eregisteredMask\"\xbd\x01\n" +
+	"\x1cReconcileCollectionsResponse\x12/\n" +
+	"\x05drift\x18\x01 \x03(\v2\x19.qmdsr.v1.CollectionDriftR\x05drift\x12\x18\n" +
+	"\aactions\x18\x02 \x03(\tR\aactions\x12\x18\n" +
+	"\aapplied\x18\x03 \x01(\bR\aapplied\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x05 \x01(\x03R\tlatencyMs\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10CancelJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x95\x02\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12(\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x12.qmdsr.v1.JobStateR\x05state\x12+\n" +
+	"\x12started_at_unix_ms\x18\x04 \x01(\x03R\x0fstartedAtUnixMs\x12'\n" +
+	"\x10ended_at_unix_ms\x18\x05 \x01(\x03R\rendedAtUnixMs\x12\x18\n" +
+	"\aretries\x18\x06 \x01(\x05R\aretries\x12\x1f\n" +
+	"\vstderr_tail\x18\a \x01(\tR\n" +
+	"stderrTail\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x19\n" +
+	"\btrace_id\x18\t \x01(\tR\atraceId\"5\n" +
+	"\x10ListJobsResponse\x12!\n" +
+	"\x04jobs\x18\x01 \x03(\v2\r.qmdsr.v1.JobR\x04jobs\"\xa6\x02\n" +
+	"\aTaskRun\x12\"\n" +
+	"\rstart_unix_ms\x18\x01 \x01(\x03R\vstartUnixMs\x12\x1f\n" +
+	"\vduration_ms\x18\x02 \x01(\x03R\n" +
+	"durationMs\x12\x1a\n" +
+	"\battempts\x18\x03 \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x15\n" +
+	"\x06job_id\x18\x05 \x01(\tR\x05jobId\x12K\n" +
+	"\x0ecache_versions\x18\x06 \x03(\v2$.qmdsr.v1.TaskRun.CacheVersionsEntryR\rcacheVersions\x1a@\n" +
+	"\x12CacheVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x90\x02\n" +
+	"\rSchedulerTask\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bschedule\x18\x02 \x01(\tR\bschedule\x12\"\n" +
+	"\rlast_run_unix\x18\x03 \x01(\x03R\vlastRunUnix\x12\"\n" +
+	"\rnext_run_unix\x18\x04 \x01(\x03R\vnextRunUnix\x12*\n" +
+	"\x11last_success_unix\x18\x05 \x01(\x03R\x0flastSuccessUnix\x12\x18\n" +
+	"\arunning\x18\x06 \x01(\bR\arunning\x12\x14\n" +
+	"\x05stale\x18\a \x01(\bR\x05stale\x12+\n" +
+	"\ahistory\x18\b \x03(\v2\x11.qmdsr.v1.TaskRunR\ahistory\"\x82\x01\n" +
+	"\x17SchedulerStatusResponse\x12-\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x17.qmdsr.v1.SchedulerTaskR\x05tasks\x12\x19\n" +
+	"\btrace_id\x18\x02 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs*f\n" +
+	"\bJobState\x12\x13\n" +
+	"\x0fJOB_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vJOB_RUNNING\x10\x01\x12\x11\n" +
+	"\rJOB_SUCCEEDED\x10\x02\x12\x0e\n" +
 	"\n" +
-	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs2\xbc\x02\n" +
+	"JOB_FAILED\x10\x03\x12\x11\n" +
+	"\rJOB_CANCELLED\x10\x042\xe8\a\n" +
 	"\fAdminService\x127\n" +
 	"\aReindex\x12\x16.google.protobuf.Empty\x1a\x14.qmdsr.v1.OpResponse\x125\n" +
 	"\x05Embed\x12\x16.qmdsr.v1.EmbedRequest\x1a\x14.qmdsr.v1.OpResponse\x12:\n" +
@@ -293,7 +1627,16 @@ const file_qmdsr_v1_admin_proto_rawDesc = "" +
 	"CacheClear\x12\x16.google.protobuf.Empty\x1a\x14.qmdsr.v1.OpResponse\x12D\n" +
 	"\vCollections\x12\x16.google.protobuf.Empty\x1a\x1d.qmdsr.v1.CollectionsResponse\x12:\n" +
 	"\n" +
-	"MCPRestart\x12\x16.google.protobuf.Empty\x1a\x14.qmdsr.v1.OpResponseB\x1aZ\x18qmdsr/pb/qmdsrv1;qmdsrv1b\x06proto3"
+	"MCPRestart\x12\x16.google.protobuf.Empty\x1a\x14.qmdsr.v1.OpResponse\x12F\n" +
+	"\fReloadConfig\x12\x16.google.protobuf.Empty\x1a\x1e.qmdsr.v1.ReloadConfigResponse\x12M\n" +
+	"\rAddCollection\x12\x18.qmdsr.v1.CollectionSpec\x1a\".qmdsr.v1.CollectionChangeResponse\x12Y\n" +
+	"\x10UpdateCollection\x12!.qmdsr.v1.UpdateCollectionRequest\x1a\".qmdsr.v1.CollectionChangeResponse\x12Y\n" +
+	"\x10RemoveCollection\x12!.qmdsr.v1.RemoveCollectionRequest\x1a\".qmdsr.v1.CollectionChangeResponse\x12e\n" +
+	"\x14ReconcileCollections\x12%.qmdsr.v1.ReconcileCollectionsRequest\x1a&.qmdsr.v1.ReconcileCollectionsResponse\x120\n" +
+	"\x06GetJob\x12\x17.qmdsr.v1.GetJobRequest\x1a\r.qmdsr.v1.Job\x12>\n" +
+	"\bListJobs\x12\x16.google.protobuf.Empty\x1a\x1a.qmdsr.v1.ListJobsResponse\x126\n" +
+	"\tCancelJob\x12\x1a.qmdsr.v1.CancelJobRequest\x1a\r.qmdsr.v1.Job\x12L\n" +
+	"\x0fSchedulerStatus\x12\x16.google.protobuf.Empty\x1a!.qmdsr.v1.SchedulerStatusResponseB\x1aZ\x18qmdsr/pb/qmdsrv1;qmdsrv1b\x06proto3"
 
 var (
 	file_qmdsr_v1_admin_proto_rawDescOnce sync.Once
@@ -307,31 +1650,74 @@ func file_qmdsr_v1_admin_proto_rawDescGZIP() []byte {
 	return file_qmdsr_v1_admin_proto_rawDescData
 }
 
-var file_qmdsr_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_qmdsr_v1_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_qmdsr_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_qmdsr_v1_admin_proto_goTypes = []any{
-	(*EmbedRequest)(nil),        // 0: qmdsr.v1.EmbedRequest
-	(*OpResponse)(nil),          // 1: qmdsr.v1.OpResponse
-	(*CollectionInfo)(nil),      // 2: qmdsr.v1.CollectionInfo
-	(*CollectionsResponse)(nil), // 3: qmdsr.v1.CollectionsResponse
-	(*emptypb.Empty)(nil),       // 4: google.protobuf.Empty
+	(JobState)(0),                        // 0: qmdsr.v1.JobState
+	(*EmbedRequest)(nil),                 // 1: qmdsr.v1.EmbedRequest
+	(*OpResponse)(nil),                   // 2: qmdsr.v1.OpResponse
+	(*CollectionInfo)(nil),               // 3: qmdsr.v1.CollectionInfo
+	(*CollectionsResponse)(nil),          // 4: qmdsr.v1.CollectionsResponse
+	(*ReloadConfigResponse)(nil),         // 5: qmdsr.v1.ReloadConfigResponse
+	(*CollectionSpec)(nil),               // 6: qmdsr.v1.CollectionSpec
+	(*UpdateCollectionRequest)(nil),      // 7: qmdsr.v1.UpdateCollectionRequest
+	(*RemoveCollectionRequest)(nil),      // 8: qmdsr.v1.RemoveCollectionRequest
+	(*CollectionChangeResponse)(nil),     // 9: qmdsr.v1.CollectionChangeResponse
+	(*ReconcileCollectionsRequest)(nil),  // 10: qmdsr.v1.ReconcileCollectionsRequest
+	(*CollectionDrift)(nil),              // 11: qmdsr.v1.CollectionDrift
+	(*ReconcileCollectionsResponse)(nil), // 12: qmdsr.v1.ReconcileCollectionsResponse
+	(*GetJobRequest)(nil),                // 13: qmdsr.v1.GetJobRequest
+	(*CancelJobRequest)(nil),             // 14: qmdsr.v1.CancelJobRequest
+	(*Job)(nil),                          // 15: qmdsr.v1.Job
+	(*ListJobsResponse)(nil),             // 16: qmdsr.v1.ListJobsResponse
+	(*TaskRun)(nil),                      // 17: qmdsr.v1.TaskRun
+	(*SchedulerTask)(nil),                // 18: qmdsr.v1.SchedulerTask
+	(*SchedulerStatusResponse)(nil),      // 19: qmdsr.v1.SchedulerStatusResponse
+	nil,                                  // 20: qmdsr.v1.TaskRun.CacheVersionsEntry
+	(*emptypb.Empty)(nil),                // 21: google.protobuf.Empty
 }
 var file_qmdsr_v1_admin_proto_depIdxs = []int32{
-	2, // 0: qmdsr.v1.CollectionsResponse.collections:type_name -> qmdsr.v1.CollectionInfo
-	4, // 1: qmdsr.v1.AdminService.Reindex:input_type -> google.protobuf.Empty
-	0, // 2: qmdsr.v1.AdminService.Embed:input_type -> qmdsr.v1.EmbedRequest
-	4, // 3: qmdsr.v1.AdminService.CacheClear:input_type -> google.protobuf.Empty
-	4, // 4: qmdsr.v1.AdminService.Collections:input_type -> google.protobuf.Empty
-	4, // 5: qmdsr.v1.AdminService.MCPRestart:input_type -> google.protobuf.Empty
-	1, // 6: qmdsr.v1.AdminService.Reindex:output_type -> qmdsr.v1.OpResponse
-	1, // 7: qmdsr.v1.AdminService.Embed:output_type -> qmdsr.v1.OpResponse
-	1, // 8: qmdsr.v1.AdminService.CacheClear:output_type -> qmdsr.v1.OpResponse
-	3, // 9: qmdsr.v1.AdminService.Collections:output_type -> qmdsr.v1.CollectionsResponse
-	1, // 10: qmdsr.v1.AdminService.MCPRestart:output_type -> qmdsr.v1.OpResponse
-	6, // [6:11] is the sub-list for method output_type
-	1, // [1:6] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3,  // 0: qmdsr.v1.CollectionsResponse.collections:type_name -> qmdsr.v1.CollectionInfo
+	6,  // 1: qmdsr.v1.CollectionChangeResponse.collection:type_name -> qmdsr.v1.CollectionSpec
+	11, // 2: qmdsr.v1.ReconcileCollectionsResponse.drift:type_name -> qmdsr.v1.CollectionDrift
+	0,  // 3: qmdsr.v1.Job.state:type_name -> qmdsr.v1.JobState
+	15, // 4: qmdsr.v1.ListJobsResponse.jobs:type_name -> qmdsr.v1.Job
+	20, // 5: qmdsr.v1.TaskRun.cache_versions:type_name -> qmdsr.v1.TaskRun.CacheVersionsEntry
+	17, // 6: qmdsr.v1.SchedulerTask.history:type_name -> qmdsr.v1.TaskRun
+	18, // 7: qmdsr.v1.SchedulerStatusResponse.tasks:type_name -> qmdsr.v1.SchedulerTask
+	21, // 8: qmdsr.v1.AdminService.Reindex:input_type -> google.protobuf.Empty
+	1,  // 9: qmdsr.v1.AdminService.Embed:input_type -> qmdsr.v1.EmbedRequest
+	21, // 10: qmdsr.v1.AdminService.CacheClear:input_type -> google.protobuf.Empty
+	21, // 11: qmdsr.v1.AdminService.Collections:input_type -> google.protobuf.Empty
+	21, // 12: qmdsr.v1.AdminService.MCPRestart:input_type -> google.protobuf.Empty
+	21, // 13: qmdsr.v1.AdminService.ReloadConfig:input_type -> google.protobuf.Empty
+	6,  // 14: qmdsr.v1.AdminService.AddCollection:input_type -> qmdsr.v1.CollectionSpec
+	7,  // 15: qmdsr.v1.AdminService.UpdateCollection:input_type -> qmdsr.v1.UpdateCollectionRequest
+	8,  // 16: qmdsr.v1.AdminService.RemoveCollection:input_type -> qmdsr.v1.RemoveCollectionRequest
+	10, // 17: qmdsr.v1.AdminService.ReconcileCollections:input_type -> qmdsr.v1.ReconcileCollectionsRequest
+	13, // 18: qmdsr.v1.AdminService.GetJob:input_type -> qmdsr.v1.GetJobRequest
+	21, // 19: qmdsr.v1.AdminService.ListJobs:input_type -> google.protobuf.Empty
+	14, // 20: qmdsr.v1.AdminService.CancelJob:input_type -> qmdsr.v1.CancelJobRequest
+	21, // 21: qmdsr.v1.AdminService.SchedulerStatus:input_type -> google.protobuf.Empty
+	2,  // 22: qmdsr.v1.AdminService.Reindex:output_type -> qmdsr.v1.OpResponse
+	2,  // 23: qmdsr.v1.AdminService.Embed:output_type -> qmdsr.v1.OpResponse
+	2,  // 24: qmdsr.v1.AdminService.CacheClear:output_type -> qmdsr.v1.OpResponse
+	4,  // 25: qmdsr.v1.AdminService.Collections:output_type -> qmdsr.v1.CollectionsResponse
+	2,  // 26: qmdsr.v1.AdminService.MCPRestart:output_type -> qmdsr.v1.OpResponse
+	5,  // 27: qmdsr.v1.AdminService.ReloadConfig:output_type -> qmdsr.v1.ReloadConfigResponse
+	9,  // 28: qmdsr.v1.AdminService.AddCollection:output_type -> qmdsr.v1.CollectionChangeResponse
+	9,  // 29: qmdsr.v1.AdminService.UpdateCollection:output_type -> qmdsr.v1.CollectionChangeResponse
+	9,  // 30: qmdsr.v1.AdminService.RemoveCollection:output_type -> qmdsr.v1.CollectionChangeResponse
+	12, // 31: qmdsr.v1.AdminService.ReconcileCollections:output_type -> qmdsr.v1.ReconcileCollectionsResponse
+	15, // 32: qmdsr.v1.AdminService.GetJob:output_type -> qmdsr.v1.Job
+	16, // 33: qmdsr.v1.AdminService.ListJobs:output_type -> qmdsr.v1.ListJobsResponse
+	15, // 34: qmdsr.v1.AdminService.CancelJob:output_type -> qmdsr.v1.Job
+	19, // 35: qmdsr.v1.AdminService.SchedulerStatus:output_type -> qmdsr.v1.SchedulerStatusResponse
+	22, // [22:36] is the sub-list for method output_type
+	8,  // [8:22] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_qmdsr_v1_admin_proto_init() }
@@ -339,18 +1725,20 @@ func file_qmdsr_v1_admin_proto_init() {
 	if File_qmdsr_v1_admin_proto != nil {
 		return
 	}
+	file_qmdsr_v1_admin_proto_msgTypes[6].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_qmdsr_v1_admin_proto_rawDesc), len(file_qmdsr_v1_admin_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_qmdsr_v1_admin_proto_goTypes,
 		DependencyIndexes: file_qmdsr_v1_admin_proto_depIdxs,
+		EnumInfos:         file_qmdsr_v1_admin_proto_enumTypes,
 		MessageInfos:      file_qmdsr_v1_admin_proto_msgTypes,
 	}.Build()
 	File_qmdsr_v1_admin_proto = out.File
@@ -20,11 +20,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AdminService_Reindex_FullMethodName     = "/qmdsr.v1.AdminService/Reindex"
-	AdminService_Embed_FullMethodName       = "/qmdsr.v1.AdminService/Embed"
-	AdminService_CacheClear_FullMethodName  = "/qmdsr.v1.AdminService/CacheClear"
-	AdminService_Collections_FullMethodName = "/qmdsr.v1.AdminService/Collections"
-	AdminService_MCPRestart_FullMethodName  = "/qmdsr.v1.AdminService/MCPRestart"
+	AdminService_Reindex_FullMethodName              = "/qmdsr.v1.AdminService/Reindex"
+	AdminService_Embed_FullMethodName                = "/qmdsr.v1.AdminService/Embed"
+	AdminService_CacheClear_FullMethodName           = "/qmdsr.v1.AdminService/CacheClear"
+	AdminService_Collections_FullMethodName          = "/qmdsr.v1.AdminService/Collections"
+	AdminService_MCPRestart_FullMethodName           = "/qmdsr.v1.AdminService/MCPRestart"
+	AdminService_ReloadConfig_FullMethodName         = "/qmdsr.v1.AdminService/ReloadConfig"
+	AdminService_AddCollection_FullMethodName        = "/qmdsr.v1.AdminService/AddCollection"
+	AdminService_UpdateCollection_FullMethodName     = "/qmdsr.v1.AdminService/UpdateCollection"
+	AdminService_RemoveCollection_FullMethodName     = "/qmdsr.v1.AdminService/RemoveCollection"
+	AdminService_ReconcileCollections_FullMethodName = "/qmdsr.v1.AdminService/ReconcileCollections"
+	AdminService_GetJob_FullMethodName               = "/qmdsr.v1.AdminService/GetJob"
+	AdminService_ListJobs_FullMethodName             = "/qmdsr.v1.AdminService/ListJobs"
+	AdminService_CancelJob_FullMethodName            = "/qmdsr.v1.AdminService/CancelJob"
+	AdminService_SchedulerStatus_FullMethodName      = "/qmdsr.v1.AdminService/SchedulerStatus"
 )
 
 // AdminServiceClient is the client API for AdminService service.
@@ -36,6 +45,15 @@ type AdminServiceClient interface {
 	CacheClear(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OpResponse, error)
 	Collections(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CollectionsResponse, error)
 	MCPRestart(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OpResponse, error)
+	ReloadConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	AddCollection(ctx context.Context, in *CollectionSpec, opts ...grpc.CallOption) (*CollectionChangeResponse, error)
+	UpdateCollection(ctx context.Context, in *UpdateCollectionRequest, opts ...grpc.CallOption) (*CollectionChangeResponse, error)
+	RemoveCollection(ctx context.Context, in *RemoveCollectionRequest, opts ...grpc.CallOption) (*CollectionChangeResponse, error)
+	ReconcileCollections(ctx context.Context, in *ReconcileCollectionsRequest, opts ...grpc.CallOption) (*ReconcileCollectionsResponse, error)
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error)
+	ListJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error)
+	SchedulerStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SchedulerStatusResponse, error)
 }
 
 type adminServiceClient struct {
@@ -96,6 +114,96 @@ func (c *adminServiceClient) MCPRestart(ctx context.Context, in *emptypb.Empty,
 	return out, nil
 }
 
+func (c *adminServiceClient) ReloadConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, AdminService_ReloadConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) AddCollection(ctx context.Context, in *CollectionSpec, opts ...grpc.CallOption) (*CollectionChangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionChangeResponse)
+	err := c.cc.Invoke(ctx, AdminService_AddCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateCollection(ctx context.Context, in *UpdateCollectionRequest, opts ...grpc.CallOption) (*CollectionChangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionChangeResponse)
+	err := c.cc.Invoke(ctx, AdminService_UpdateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RemoveCollection(ctx context.Context, in *RemoveCollectionRequest, opts ...grpc.CallOption) (*CollectionChangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionChangeResponse)
+	err := c.cc.Invoke(ctx, AdminService_RemoveCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ReconcileCollections(ctx context.Context, in *ReconcileCollectionsRequest, opts ...grpc.CallOption) (*ReconcileCollectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReconcileCollectionsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ReconcileCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, AdminService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, AdminService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SchedulerStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SchedulerStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SchedulerStatusResponse)
+	err := c.cc.Invoke(ctx, AdminService_SchedulerStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServiceServer is the server API for AdminService service.
 // All implementations must embed UnimplementedAdminServiceServer
 // for forward compatibility.
@@ -105,6 +213,15 @@ type AdminServiceServer interface {
 	CacheClear(context.Context, *emptypb.Empty) (*OpResponse, error)
 	Collections(context.Context, *emptypb.Empty) (*CollectionsResponse, error)
 	MCPRestart(context.Context, *emptypb.Empty) (*OpResponse, error)
+	ReloadConfig(context.Context, *emptypb.Empty) (*ReloadConfigResponse, error)
+	AddCollection(context.Context, *CollectionSpec) (*CollectionChangeResponse, error)
+	UpdateCollection(context.Context, *UpdateCollectionRequest) (*CollectionChangeResponse, error)
+	RemoveCollection(context.Context, *RemoveCollectionRequest) (*CollectionChangeResponse, error)
+	ReconcileCollections(context.Context, *ReconcileCollectionsRequest) (*ReconcileCollectionsResponse, error)
+	GetJob(context.Context, *GetJobRequest) (*Job, error)
+	ListJobs(context.Context, *emptypb.Empty) (*ListJobsResponse, error)
+	CancelJob(context.Context, *CancelJobRequest) (*Job, error)
+	SchedulerStatus(context.Context, *emptypb.Empty) (*SchedulerStatusResponse, error)
 	mustEmbedUnimplementedAdminServiceServer()
 }
 
@@ -130,6 +247,33 @@ func (UnimplementedAdminServiceServer) Collections(context.Context, *emptypb.Emp
 func (UnimplementedAdminServiceServer) MCPRestart(context.Context, *emptypb.Empty) (*OpResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method MCPRestart not implemented")
 }
+func (UnimplementedAdminServiceServer) ReloadConfig(context.Context, *emptypb.Empty) (*ReloadConfigResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedAdminServiceServer) AddCollection(context.Context, *CollectionSpec) (*CollectionChangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddCollection not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateCollection(context.Context, *UpdateCollectionRequest) (*CollectionChangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCollection not implemented")
+}
+func (UnimplementedAdminServiceServer) RemoveCollection(context.Context, *RemoveCollectionRequest) (*CollectionChangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveCollection not implemented")
+}
+func (UnimplementedAdminServiceServer) ReconcileCollections(context.Context, *ReconcileCollectionsRequest) (*ReconcileCollectionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReconcileCollections not implemented")
+}
+func (UnimplementedAdminServiceServer) GetJob(context.Context, *GetJobRequest) (*Job, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedAdminServiceServer) ListJobs(context.Context, *emptypb.Empty) (*ListJobsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedAdminServiceServer) CancelJob(context.Context, *CancelJobRequest) (*Job, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedAdminServiceServer) SchedulerStatus(context.Context, *emptypb.Empty) (*SchedulerStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SchedulerStatus not implemented")
+}
 func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
 func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
 
@@ -241,6 +385,168 @@ func _AdminService_MCPRestart_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ReloadConfig(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_AddCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AddCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AddCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AddCollection(ctx, req.(*CollectionSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateCollection(ctx, req.(*UpdateCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RemoveCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RemoveCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RemoveCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RemoveCollection(ctx, req.(*RemoveCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ReconcileCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileCollectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ReconcileCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ReconcileCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ReconcileCollections(ctx, req.(*ReconcileCollectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListJobs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SchedulerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SchedulerStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SchedulerStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SchedulerStatus(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,6 +574,42 @@ var AdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MCPRestart",
 			Handler:    _AdminService_MCPRestart_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _AdminService_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "AddCollection",
+			Handler:    _AdminService_AddCollection_Handler,
+		},
+		{
+			MethodName: "UpdateCollection",
+			Handler:    _AdminService_UpdateCollection_Handler,
+		},
+		{
+			MethodName: "RemoveCollection",
+			Handler:    _AdminService_RemoveCollection_Handler,
+		},
+		{
+			MethodName: "ReconcileCollections",
+			Handler:    _AdminService_ReconcileCollections_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _AdminService_GetJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _AdminService_ListJobs_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _AdminService_CancelJob_Handler,
+		},
+		{
+			MethodName: "SchedulerStatus",
+			Handler:    _AdminService_SchedulerStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "qmdsr/v1/admin.proto",
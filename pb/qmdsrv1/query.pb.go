@@ -29,6 +29,7 @@ const (
 	Mode_MODE_BROAD       Mode = 2
 	Mode_MODE_DEEP        Mode = 3
 	Mode_MODE_AUTO        Mode = 4
+	Mode_MODE_HYBRID      Mode = 5
 )
 
 // Enum value maps for Mode.
@@ -39,6 +40,7 @@ var (
 		2: "MODE_BROAD",
 		3: "MODE_DEEP",
 		4: "MODE_AUTO",
+		5: "MODE_HYBRID",
 	}
 	Mode_value = map[string]int32{
 		"MODE_UNSPECIFIED": 0,
@@ -46,6 +48,7 @@ var (
 		"MODE_BROAD":       2,
 		"MODE_DEEP":        3,
 		"MODE_AUTO":        4,
+		"MODE_HYBRID":      5,
 	}
 )
 
@@ -83,6 +86,7 @@ const (
 	ServedMode_SERVED_CORE        ServedMode = 1
 	ServedMode_SERVED_BROAD       ServedMode = 2
 	ServedMode_SERVED_DEEP        ServedMode = 3
+	ServedMode_SERVED_HYBRID      ServedMode = 4
 )
 
 // Enum value maps for ServedMode.
@@ -92,12 +96,14 @@ var (
 		1: "SERVED_CORE",
 		2: "SERVED_BROAD",
 		3: "SERVED_DEEP",
+		4: "SERVED_HYBRID",
 	}
 	ServedMode_value = map[string]int32{
 		"SERVED_UNSPECIFIED": 0,
 		"SERVED_CORE":        1,
 		"SERVED_BROAD":       2,
 		"SERVED_DEEP":        3,
+		"SERVED_HYBRID":      4,
 	}
 )
 
@@ -128,6 +134,58 @@ func (ServedMode) EnumDescriptor() ([]byte, []int) {
 	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{1}
 }
 
+type SearchStage int32
+
+const (
+	SearchStage_STAGE_UNSPECIFIED SearchStage = 0
+	SearchStage_STAGE_TIER1       SearchStage = 1
+	SearchStage_STAGE_TIER2       SearchStage = 2
+	SearchStage_STAGE_DEEP        SearchStage = 3
+)
+
+// Enum value maps for SearchStage.
+var (
+	SearchStage_name = map[int32]string{
+		0: "STAGE_UNSPECIFIED",
+		1: "STAGE_TIER1",
+		2: "STAGE_TIER2",
+		3: "STAGE_DEEP",
+	}
+	SearchStage_value = map[string]int32{
+		"STAGE_UNSPECIFIED": 0,
+		"STAGE_TIER1":       1,
+		"STAGE_TIER2":       2,
+		"STAGE_DEEP":        3,
+	}
+)
+
+func (x SearchStage) Enum() *SearchStage {
+	p := new(SearchStage)
+	*p = x
+	return p
+}
+
+func (x SearchStage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SearchStage) Descriptor() protoreflect.EnumDescriptor {
+	return file_qmdsr_v1_query_proto_enumTypes[2].Descriptor()
+}
+
+func (SearchStage) Type() protoreflect.EnumType {
+	return &file_qmdsr_v1_query_proto_enumTypes[2]
+}
+
+func (x SearchStage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SearchStage.Descriptor instead.
+func (SearchStage) EnumDescriptor() ([]byte, []int) {
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{2}
+}
+
 type SearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
@@ -428,18 +486,138 @@ func (x *SearchResponse) GetFormattedText() string {
 	return ""
 }
 
+// SearchFrame is one snapshot of a streamed search. Every frame carries the
+// complete hit list known so far and replaces the previous frame; the last
+// frame has final=true and matches what Search would have returned.
+type SearchFrame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         SearchStage            `protobuf:"varint,1,opt,name=stage,proto3,enum=qmdsr.v1.SearchStage" json:"stage,omitempty"`
+	Final         bool                   `protobuf:"varint,2,opt,name=final,proto3" json:"final,omitempty"`
+	Hits          []*Hit                 `protobuf:"bytes,3,rep,name=hits,proto3" json:"hits,omitempty"`
+	ServedMode    ServedMode             `protobuf:"varint,4,opt,name=served_mode,json=servedMode,proto3,enum=qmdsr.v1.ServedMode" json:"served_mode,omitempty"`
+	Degraded      bool                   `protobuf:"varint,5,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	DegradeReason string                 `protobuf:"bytes,6,opt,name=degrade_reason,json=degradeReason,proto3" json:"degrade_reason,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,7,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	TraceId       string                 `protobuf:"bytes,8,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	RouteLog      []string               `protobuf:"bytes,9,rep,name=route_log,json=routeLog,proto3" json:"route_log,omitempty"`
+	FormattedText string                 `protobuf:"bytes,10,opt,name=formatted_text,json=formattedText,proto3" json:"formatted_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFrame) Reset() {
+	*x = SearchFrame{}
+	mi := &file_qmdsr_v1_query_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFrame) ProtoMessage() {}
+
+func (x *SearchFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_query_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFrame.ProtoReflect.Descriptor instead.
+func (*SearchFrame) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchFrame) GetStage() SearchStage {
+	if x != nil {
+		return x.Stage
+	}
+	return SearchStage_STAGE_UNSPECIFIED
+}
+
+func (x *SearchFrame) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *SearchFrame) GetHits() []*Hit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchFrame) GetServedMode() ServedMode {
+	if x != nil {
+		return x.ServedMode
+	}
+	return ServedMode_SERVED_UNSPECIFIED
+}
+
+func (x *SearchFrame) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *SearchFrame) GetDegradeReason() string {
+	if x != nil {
+		return x.DegradeReason
+	}
+	return ""
+}
+
+func (x *SearchFrame) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *SearchFrame) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *SearchFrame) GetRouteLog() []string {
+	if x != nil {
+		return x.RouteLog
+	}
+	return nil
+}
+
+func (x *SearchFrame) GetFormattedText() string {
+	if x != nil {
+		return x.FormattedText
+	}
+	return ""
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DocRef        string                 `protobuf:"bytes,1,opt,name=doc_ref,json=docRef,proto3" json:"doc_ref,omitempty"`
 	Full          bool                   `protobuf:"varint,2,opt,name=full,proto3" json:"full,omitempty"`
 	LineNumbers   bool                   `protobuf:"varint,3,opt,name=line_numbers,json=lineNumbers,proto3" json:"line_numbers,omitempty"`
+	Confirm       bool                   `protobuf:"varint,4,opt,name=confirm,proto3" json:"confirm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetRequest) Reset() {
 	*x = GetRequest{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[3]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +629,7 @@ func (x *GetRequest) String() string {
 func (*GetRequest) ProtoMessage() {}
 
 func (x *GetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[3]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +642,7 @@ func (x *GetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{3}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetRequest) GetDocRef() string {
@@ -488,6 +666,13 @@ func (x *GetRequest) GetLineNumbers() bool {
 	return false
 }
 
+func (x *GetRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
 type GetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
@@ -499,7 +684,7 @@ type GetResponse struct {
 
 func (x *GetResponse) Reset() {
 	*x = GetResponse{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[4]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -511,7 +696,7 @@ func (x *GetResponse) String() string {
 func (*GetResponse) ProtoMessage() {}
 
 func (x *GetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[4]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -524,7 +709,7 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{4}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetResponse) GetContent() string {
@@ -552,13 +737,14 @@ type MultiGetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pattern       string                 `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
 	MaxBytes      int32                  `protobuf:"varint,2,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	Confirm       bool                   `protobuf:"varint,3,opt,name=confirm,proto3" json:"confirm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MultiGetRequest) Reset() {
 	*x = MultiGetRequest{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[5]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -570,7 +756,7 @@ func (x *MultiGetRequest) String() string {
 func (*MultiGetRequest) ProtoMessage() {}
 
 func (x *MultiGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[5]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -583,7 +769,7 @@ func (x *MultiGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MultiGetRequest.ProtoReflect.Descriptor instead.
 func (*MultiGetRequest) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{5}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MultiGetRequest) GetPattern() string {
@@ -600,6 +786,13 @@ func (x *MultiGetRequest) GetMaxBytes() int32 {
 	return 0
 }
 
+func (x *MultiGetRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
 type DocContent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	File          string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
@@ -610,7 +803,7 @@ type DocContent struct {
 
 func (x *DocContent) Reset() {
 	*x = DocContent{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[6]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -622,7 +815,7 @@ func (x *DocContent) String() string {
 func (*DocContent) ProtoMessage() {}
 
 func (x *DocContent) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[6]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -635,7 +828,7 @@ func (x *DocContent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DocContent.ProtoReflect.Descriptor instead.
 func (*DocContent) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{6}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DocContent) GetFile() string {
@@ -663,7 +856,7 @@ type MultiGetResponse struct {
 
 func (x *MultiGetResponse) Reset() {
 	*x = MultiGetResponse{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[7]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -675,7 +868,7 @@ func (x *MultiGetResponse) String() string {
 func (*MultiGetResponse) ProtoMessage() {}
 
 func (x *MultiGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[7]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -688,7 +881,7 @@ func (x *MultiGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MultiGetResponse.ProtoReflect.Descriptor instead.
 func (*MultiGetResponse) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{7}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *MultiGetResponse) GetDocuments() []*DocContent {
@@ -729,7 +922,7 @@ type SearchAndGetRequest struct {
 
 func (x *SearchAndGetRequest) Reset() {
 	*x = SearchAndGetRequest{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[8]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -741,7 +934,7 @@ func (x *SearchAndGetRequest) String() string {
 func (*SearchAndGetRequest) ProtoMessage() {}
 
 func (x *SearchAndGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[8]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -754,7 +947,7 @@ func (x *SearchAndGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchAndGetRequest.ProtoReflect.Descriptor instead.
 func (*SearchAndGetRequest) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{8}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SearchAndGetRequest) GetQuery() string {
@@ -836,7 +1029,7 @@ type SearchAndGetResponse struct {
 
 func (x *SearchAndGetResponse) Reset() {
 	*x = SearchAndGetResponse{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[9]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -848,7 +1041,7 @@ func (x *SearchAndGetResponse) String() string {
 func (*SearchAndGetResponse) ProtoMessage() {}
 
 func (x *SearchAndGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[9]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,7 +1054,7 @@ func (x *SearchAndGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchAndGetResponse.ProtoReflect.Descriptor instead.
 func (*SearchAndGetResponse) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{9}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SearchAndGetResponse) GetFileHits() []*Hit {
@@ -928,7 +1121,7 @@ type HealthRequest struct {
 
 func (x *HealthRequest) Reset() {
 	*x = HealthRequest{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[10]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -940,7 +1133,7 @@ func (x *HealthRequest) String() string {
 func (*HealthRequest) ProtoMessage() {}
 
 func (x *HealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[10]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -953,7 +1146,7 @@ func (x *HealthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
 func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{10}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{11}
 }
 
 type ComponentHealth struct {
@@ -967,7 +1160,7 @@ type ComponentHealth struct {
 
 func (x *ComponentHealth) Reset() {
 	*x = ComponentHealth{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[11]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -979,7 +1172,7 @@ func (x *ComponentHealth) String() string {
 func (*ComponentHealth) ProtoMessage() {}
 
 func (x *ComponentHealth) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[11]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -992,7 +1185,7 @@ func (x *ComponentHealth) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ComponentHealth.ProtoReflect.Descriptor instead.
 func (*ComponentHealth) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{11}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ComponentHealth) GetName() string {
@@ -1022,13 +1215,14 @@ type HealthResponse struct {
 	Components    []*ComponentHealth     `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
 	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
 	UptimeSec     int64                  `protobuf:"varint,4,opt,name=uptime_sec,json=uptimeSec,proto3" json:"uptime_sec,omitempty"`
+	Remediations  []*RemediationAttempt  `protobuf:"bytes,5,rep,name=remediations,proto3" json:"remediations,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HealthResponse) Reset() {
 	*x = HealthResponse{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[12]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1040,7 +1234,7 @@ func (x *HealthResponse) String() string {
 func (*HealthResponse) ProtoMessage() {}
 
 func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[12]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1053,7 +1247,7 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
 func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{12}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *HealthResponse) GetStatus() string {
@@ -1084,6 +1278,115 @@ func (x *HealthResponse) GetUptimeSec() int64 {
 	return 0
 }
 
+func (x *HealthResponse) GetRemediations() []*RemediationAttempt {
+	if x != nil {
+		return x.Remediations
+	}
+	return nil
+}
+
+// A self-heal action run for a component, newest first. error is empty when
+// the action succeeded.
+type RemediationAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Remediation   string                 `protobuf:"bytes,1,opt,name=remediation,proto3" json:"remediation,omitempty"`
+	Component     string                 `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"`
+	FromStatus    string                 `protobuf:"bytes,3,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
+	ToStatus      string                 `protobuf:"bytes,4,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	AtUnixMs      int64                  `protobuf:"varint,6,opt,name=at_unix_ms,json=atUnixMs,proto3" json:"at_unix_ms,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemediationAttempt) Reset() {
+	*x = RemediationAttempt{}
+	mi := &file_qmdsr_v1_query_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemediationAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemediationAttempt) ProtoMessage() {}
+
+func (x *RemediationAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_query_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemediationAttempt.ProtoReflect.Descriptor instead.
+func (*RemediationAttempt) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemediationAttempt) GetRemediation() string {
+	if x != nil {
+		return x.Remediation
+	}
+	return ""
+}
+
+func (x *RemediationAttempt) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *RemediationAttempt) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *RemediationAttempt) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *RemediationAttempt) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RemediationAttempt) GetAtUnixMs() int64 {
+	if x != nil {
+		return x.AtUnixMs
+	}
+	return 0
+}
+
+func (x *RemediationAttempt) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *RemediationAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type StatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1092,7 +1395,7 @@ type StatusRequest struct {
 
 func (x *StatusRequest) Reset() {
 	*x = StatusRequest{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[13]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1104,7 +1407,7 @@ func (x *StatusRequest) String() string {
 func (*StatusRequest) ProtoMessage() {}
 
 func (x *StatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[13]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1117,32 +1420,34 @@ func (x *StatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
 func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{13}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{15}
 }
 
 type StatusResponse struct {
-	state                       protoimpl.MessageState `protogen:"open.v1"`
-	Version                     string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	Commit                      string                 `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
-	LowResourceMode             bool                   `protobuf:"varint,3,opt,name=low_resource_mode,json=lowResourceMode,proto3" json:"low_resource_mode,omitempty"`
-	AllowCpuDeepQuery           bool                   `protobuf:"varint,4,opt,name=allow_cpu_deep_query,json=allowCpuDeepQuery,proto3" json:"allow_cpu_deep_query,omitempty"`
-	DeepQueryEnabled            bool                   `protobuf:"varint,5,opt,name=deep_query_enabled,json=deepQueryEnabled,proto3" json:"deep_query_enabled,omitempty"`
-	VectorEnabled               bool                   `protobuf:"varint,6,opt,name=vector_enabled,json=vectorEnabled,proto3" json:"vector_enabled,omitempty"`
-	QueryMaxConcurrency         int32                  `protobuf:"varint,7,opt,name=query_max_concurrency,json=queryMaxConcurrency,proto3" json:"query_max_concurrency,omitempty"`
-	QueryTimeoutMs              int32                  `protobuf:"varint,8,opt,name=query_timeout_ms,json=queryTimeoutMs,proto3" json:"query_timeout_ms,omitempty"`
-	DeepFailTimeoutMs           int32                  `protobuf:"varint,9,opt,name=deep_fail_timeout_ms,json=deepFailTimeoutMs,proto3" json:"deep_fail_timeout_ms,omitempty"`
-	DeepNegativeTtlSec          int32                  `protobuf:"varint,10,opt,name=deep_negative_ttl_sec,json=deepNegativeTtlSec,proto3" json:"deep_negative_ttl_sec,omitempty"`
-	TraceId                     string                 `protobuf:"bytes,11,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
-	CpuOverloaded               bool                   `protobuf:"varint,12,opt,name=cpu_overloaded,json=cpuOverloaded,proto3" json:"cpu_overloaded,omitempty"`
-	CpuCriticalOverloaded       bool                   `protobuf:"varint,13,opt,name=cpu_critical_overloaded,json=cpuCriticalOverloaded,proto3" json:"cpu_critical_overloaded,omitempty"`
-	OverloadMaxConcurrentSearch int32                  `protobuf:"varint,14,opt,name=overload_max_concurrent_search,json=overloadMaxConcurrentSearch,proto3" json:"overload_max_concurrent_search,omitempty"`
+	state                       protoimpl.MessageState   `protogen:"open.v1"`
+	Version                     string                   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Commit                      string                   `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	LowResourceMode             bool                     `protobuf:"varint,3,opt,name=low_resource_mode,json=lowResourceMode,proto3" json:"low_resource_mode,omitempty"`
+	AllowCpuDeepQuery           bool                     `protobuf:"varint,4,opt,name=allow_cpu_deep_query,json=allowCpuDeepQuery,proto3" json:"allow_cpu_deep_query,omitempty"`
+	DeepQueryEnabled            bool                     `protobuf:"varint,5,opt,name=deep_query_enabled,json=deepQueryEnabled,proto3" json:"deep_query_enabled,omitempty"`
+	VectorEnabled               bool                     `protobuf:"varint,6,opt,name=vector_enabled,json=vectorEnabled,proto3" json:"vector_enabled,omitempty"`
+	QueryMaxConcurrency         int32                    `protobuf:"varint,7,opt,name=query_max_concurrency,json=queryMaxConcurrency,proto3" json:"query_max_concurrency,omitempty"`
+	QueryTimeoutMs              int32                    `protobuf:"varint,8,opt,name=query_timeout_ms,json=queryTimeoutMs,proto3" json:"query_timeout_ms,omitempty"`
+	DeepFailTimeoutMs           int32                    `protobuf:"varint,9,opt,name=deep_fail_timeout_ms,json=deepFailTimeoutMs,proto3" json:"deep_fail_timeout_ms,omitempty"`
+	DeepNegativeTtlSec          int32                    `protobuf:"varint,10,opt,name=deep_negative_ttl_sec,json=deepNegativeTtlSec,proto3" json:"deep_negative_ttl_sec,omitempty"`
+	TraceId                     string                   `protobuf:"bytes,11,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	CpuOverloaded               bool                     `protobuf:"varint,12,opt,name=cpu_overloaded,json=cpuOverloaded,proto3" json:"cpu_overloaded,omitempty"`
+	CpuCriticalOverloaded       bool                     `protobuf:"varint,13,opt,name=cpu_critical_overloaded,json=cpuCriticalOverloaded,proto3" json:"cpu_critical_overloaded,omitempty"`
+	OverloadMaxConcurrentSearch int32                    `protobuf:"varint,14,opt,name=overload_max_concurrent_search,json=overloadMaxConcurrentSearch,proto3" json:"overload_max_concurrent_search,omitempty"`
+	Embed                       []*CollectionEmbedStatus `protobuf:"bytes,15,rep,name=embed,proto3" json:"embed,omitempty"`
+	Scheduler                   []*SchedulerTaskSummary  `protobuf:"bytes,16,rep,name=scheduler,proto3" json:"scheduler,omitempty"`
 	unknownFields               protoimpl.UnknownFields
 	sizeCache                   protoimpl.SizeCache
 }
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
-	mi := &file_qmdsr_v1_query_proto_msgTypes[14]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1154,7 +1459,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_qmdsr_v1_query_proto_msgTypes[14]
+	mi := &file_qmdsr_v1_query_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1167,7 +1472,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{14}
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *StatusResponse) GetVersion() string {
@@ -1268,6 +1573,184 @@ func (x *StatusResponse) GetOverloadMaxConcurrentSearch() int32 {
 	return 0
 }
 
+func (x *StatusResponse) GetEmbed() []*CollectionEmbedStatus {
+	if x != nil {
+		return x.Embed
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetScheduler() []*SchedulerTaskSummary {
+	if x != nil {
+		return x.Scheduler
+	}
+	return nil
+}
+
+// Embedding schedule of a collection with embed: true. last_embedded_unix is
+// 0 until the collection is first embedded.
+type CollectionEmbedStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Collection       string                 `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	IntervalSec      int64                  `protobuf:"varint,2,opt,name=interval_sec,json=intervalSec,proto3" json:"interval_sec,omitempty"`
+	LastEmbeddedUnix int64                  `protobuf:"varint,3,opt,name=last_embedded_unix,json=lastEmbeddedUnix,proto3" json:"last_embedded_unix,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CollectionEmbedStatus) Reset() {
+	*x = CollectionEmbedStatus{}
+	mi := &file_qmdsr_v1_query_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionEmbedStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionEmbedStatus) ProtoMessage() {}
+
+func (x *CollectionEmbedStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_query_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionEmbedStatus.ProtoReflect.Descriptor instead.
+func (*CollectionEmbedStatus) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CollectionEmbedStatus) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *CollectionEmbedStatus) GetIntervalSec() int64 {
+	if x != nil {
+		return x.IntervalSec
+	}
+	return 0
+}
+
+func (x *CollectionEmbedStatus) GetLastEmbeddedUnix() int64 {
+	if x != nil {
+		return x.LastEmbeddedUnix
+	}
+	return 0
+}
+
+// Latest run of a scheduled task; AdminService.SchedulerStatus has the full
+// history. Times are 0 until the first run.
+type SchedulerTaskSummary struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	LastRunUnix     int64                  `protobuf:"varint,2,opt,name=last_run_unix,json=lastRunUnix,proto3" json:"last_run_unix,omitempty"`
+	NextRunUnix     int64                  `protobuf:"varint,3,opt,name=next_run_unix,json=nextRunUnix,proto3" json:"next_run_unix,omitempty"`
+	LastSuccessUnix int64                  `protobuf:"varint,4,opt,name=last_success_unix,json=lastSuccessUnix,proto3" json:"last_success_unix,omitempty"`
+	LastDurationMs  int64                  `protobuf:"varint,5,opt,name=last_duration_ms,json=lastDurationMs,proto3" json:"last_duration_ms,omitempty"`
+	LastError       string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Running         bool                   `protobuf:"varint,7,opt,name=running,proto3" json:"running,omitempty"`
+	Stale           bool                   `protobuf:"varint,8,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SchedulerTaskSummary) Reset() {
+	*x = SchedulerTaskSummary{}
+	mi := &file_qmdsr_v1_query_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerTaskSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerTaskSummary) ProtoMessage() {}
+
+func (x *SchedulerTaskSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_qmdsr_v1_query_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerTaskSummary.ProtoReflect.Descriptor instead.
+func (*SchedulerTaskSummary) Descriptor() ([]byte, []int) {
+	return file_qmdsr_v1_query_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SchedulerTaskSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SchedulerTaskSummary) GetLastRunUnix() int64 {
+	if x != nil {
+		return x.LastRunUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTaskSummary) GetNextRunUnix() int64 {
+	if x != nil {
+		return x.NextRunUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTaskSummary) GetLastSuccessUnix() int64 {
+	if x != nil {
+		return x.LastSuccessUnix
+	}
+	return 0
+}
+
+func (x *SchedulerTaskSummary) GetLastDurationMs() int64 {
+	if x != nil {
+		return x.LastDurationMs
+	}
+	return 0
+}
+
+func (x *SchedulerTaskSummary) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *SchedulerTaskSummary) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *SchedulerTaskSummary) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
 var File_qmdsr_v1_query_proto protoreflect.FileDescriptor
 
 const file_qmdsr_v1_query_proto_rawDesc = "" +
@@ -1306,20 +1789,36 @@ const file_qmdsr_v1_query_proto_rawDesc = "" +
 	"latency_ms\x18\x05 \x01(\x03R\tlatencyMs\x12\x19\n" +
 	"\btrace_id\x18\x06 \x01(\tR\atraceId\x12\x1b\n" +
 	"\troute_log\x18\a \x03(\tR\brouteLog\x12%\n" +
-	"\x0eformatted_text\x18\b \x01(\tR\rformattedText\"\\\n" +
+	"\x0eformatted_text\x18\b \x01(\tR\rformattedText\"\xeb\x02\n" +
+	"\vSearchFrame\x12+\n" +
+	"\x05stage\x18\x01 \x01(\x0e2\x15.qmdsr.v1.SearchStageR\x05stage\x12\x14\n" +
+	"\x05final\x18\x02 \x01(\bR\x05final\x12!\n" +
+	"\x04hits\x18\x03 \x03(\v2\r.qmdsr.v1.HitR\x04hits\x125\n" +
+	"\vserved_mode\x18\x04 \x01(\x0e2\x14.qmdsr.v1.ServedModeR\n" +
+	"servedMode\x12\x1a\n" +
+	"\bdegraded\x18\x05 \x01(\bR\bdegraded\x12%\n" +
+	"\x0edegrade_reason\x18\x06 \x01(\tR\rdegradeReason\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\a \x01(\x03R\tlatencyMs\x12\x19\n" +
+	"\btrace_id\x18\b \x01(\tR\atraceId\x12\x1b\n" +
+	"\troute_log\x18\t \x03(\tR\brouteLog\x12%\n" +
+	"\x0eformatted_text\x18\n" +
+	" \x01(\tR\rformattedText\"v\n" +
 	"\n" +
 	"GetRequest\x12\x17\n" +
 	"\adoc_ref\x18\x01 \x01(\tR\x06docRef\x12\x12\n" +
 	"\x04full\x18\x02 \x01(\bR\x04full\x12!\n" +
-	"\fline_numbers\x18\x03 \x01(\bR\vlineNumbers\"a\n" +
+	"\fline_numbers\x18\x03 \x01(\bR\vlineNumbers\x12\x18\n" +
+	"\aconfirm\x18\x04 \x01(\bR\aconfirm\"a\n" +
 	"\vGetResponse\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12\x19\n" +
 	"\btrace_id\x18\x02 \x01(\tR\atraceId\x12\x1d\n" +
 	"\n" +
-	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\"H\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\"b\n" +
 	"\x0fMultiGetRequest\x12\x18\n" +
 	"\apattern\x18\x01 \x01(\tR\apattern\x12\x1b\n" +
-	"\tmax_bytes\x18\x02 \x01(\x05R\bmaxBytes\":\n" +
+	"\tmax_bytes\x18\x02 \x01(\x05R\bmaxBytes\x12\x18\n" +
+	"\aconfirm\x18\x03 \x01(\bR\aconfirm\":\n" +
 	"\n" +
 	"DocContent\x12\x12\n" +
 	"\x04file\x18\x01 \x01(\tR\x04file\x12\x18\n" +
@@ -1355,7 +1854,7 @@ const file_qmdsr_v1_query_proto_rawDesc = "" +
 	"\x0fComponentHealth\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x96\x01\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xd8\x01\n" +
 	"\x0eHealthResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x129\n" +
 	"\n" +
@@ -1363,8 +1862,21 @@ const file_qmdsr_v1_query_proto_rawDesc = "" +
 	"components\x12\x12\n" +
 	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1d\n" +
 	"\n" +
-	"uptime_sec\x18\x04 \x01(\x03R\tuptimeSec\"\x0f\n" +
-	"\rStatusRequest\"\xf5\x04\n" +
+	"uptime_sec\x18\x04 \x01(\x03R\tuptimeSec\x12@\n" +
+	"\fremediations\x18\x05 \x03(\v2\x1c.qmdsr.v1.RemediationAttemptR\fremediations\"\x81\x02\n" +
+	"\x12RemediationAttempt\x12 \n" +
+	"\vremediation\x18\x01 \x01(\tR\vremediation\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\tR\tcomponent\x12\x1f\n" +
+	"\vfrom_status\x18\x03 \x01(\tR\n" +
+	"fromStatus\x12\x1b\n" +
+	"\tto_status\x18\x04 \x01(\tR\btoStatus\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x1c\n" +
+	"\n" +
+	"at_unix_ms\x18\x06 \x01(\x03R\batUnixMs\x12\x1f\n" +
+	"\vduration_ms\x18\a \x01(\x03R\n" +
+	"durationMs\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\x0f\n" +
+	"\rStatusRequest\"\xea\x05\n" +
 	"\x0eStatusResponse\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12\x16\n" +
 	"\x06commit\x18\x02 \x01(\tR\x06commit\x12*\n" +
@@ -1380,22 +1892,49 @@ const file_qmdsr_v1_query_proto_rawDesc = "" +
 	"\btrace_id\x18\v \x01(\tR\atraceId\x12%\n" +
 	"\x0ecpu_overloaded\x18\f \x01(\bR\rcpuOverloaded\x126\n" +
 	"\x17cpu_critical_overloaded\x18\r \x01(\bR\x15cpuCriticalOverloaded\x12C\n" +
-	"\x1eoverload_max_concurrent_search\x18\x0e \x01(\x05R\x1boverloadMaxConcurrentSearch*Y\n" +
+	"\x1eoverload_max_concurrent_search\x18\x0e \x01(\x05R\x1boverloadMaxConcurrentSearch\x125\n" +
+	"\x05embed\x18\x0f \x03(\v2\x1f.qmdsr.v1.CollectionEmbedStatusR\x05embed\x12<\n" +
+	"\tscheduler\x18\x10 \x03(\v2\x1e.qmdsr.v1.SchedulerTaskSummaryR\tscheduler\"\x88\x01\n" +
+	"\x15CollectionEmbedStatus\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x01 \x01(\tR\n" +
+	"collection\x12!\n" +
+	"\finterval_sec\x18\x02 \x01(\x03R\vintervalSec\x12,\n" +
+	"\x12last_embedded_unix\x18\x03 \x01(\x03R\x10lastEmbeddedUnix\"\x97\x02\n" +
+	"\x14SchedulerTaskSummary\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\rlast_run_unix\x18\x02 \x01(\x03R\vlastRunUnix\x12\"\n" +
+	"\rnext_run_unix\x18\x03 \x01(\x03R\vnextRunUnix\x12*\n" +
+	"\x11last_success_unix\x18\x04 \x01(\x03R\x0flastSuccessUnix\x12(\n" +
+	"\x10last_duration_ms\x18\x05 \x01(\x03R\x0elastDurationMs\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12\x18\n" +
+	"\arunning\x18\a \x01(\bR\arunning\x12\x14\n" +
+	"\x05stale\x18\b \x01(\bR\x05stale*j\n" +
 	"\x04Mode\x12\x14\n" +
 	"\x10MODE_UNSPECIFIED\x10\x00\x12\r\n" +
 	"\tMODE_CORE\x10\x01\x12\x0e\n" +
 	"\n" +
 	"MODE_BROAD\x10\x02\x12\r\n" +
 	"\tMODE_DEEP\x10\x03\x12\r\n" +
-	"\tMODE_AUTO\x10\x04*X\n" +
+	"\tMODE_AUTO\x10\x04\x12\x0f\n" +
+	"\vMODE_HYBRID\x10\x05*k\n" +
 	"\n" +
 	"ServedMode\x12\x16\n" +
 	"\x12SERVED_UNSPECIFIED\x10\x00\x12\x0f\n" +
 	"\vSERVED_CORE\x10\x01\x12\x10\n" +
 	"\fSERVED_BROAD\x10\x02\x12\x0f\n" +
-	"\vSERVED_DEEP\x10\x032\x8b\x03\n" +
+	"\vSERVED_DEEP\x10\x03\x12\x11\n" +
+	"\rSERVED_HYBRID\x10\x04*V\n" +
+	"\vSearchStage\x12\x15\n" +
+	"\x11STAGE_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSTAGE_TIER1\x10\x01\x12\x0f\n" +
+	"\vSTAGE_TIER2\x10\x02\x12\x0e\n" +
+	"\n" +
+	"STAGE_DEEP\x10\x032\xcd\x03\n" +
 	"\fQueryService\x12;\n" +
-	"\x06Search\x12\x17.qmdsr.v1.SearchRequest\x1a\x18.qmdsr.v1.SearchResponse\x12M\n" +
+	"\x06Search\x12\x17.qmdsr.v1.SearchRequest\x1a\x18.qmdsr.v1.SearchResponse\x12@\n" +
+	"\fSearchStream\x12\x17.qmdsr.v1.SearchRequest\x1a\x15.qmdsr.v1.SearchFrame0\x01\x12M\n" +
 	"\fSearchAndGet\x12\x1d.qmdsr.v1.SearchAndGetRequest\x1a\x1e.qmdsr.v1.SearchAndGetResponse\x122\n" +
 	"\x03Get\x12\x14.qmdsr.v1.GetRequest\x1a\x15.qmdsr.v1.GetResponse\x12A\n" +
 	"\bMultiGet\x12\x19.qmdsr.v1.MultiGetRequest\x1a\x1a.qmdsr.v1.MultiGetResponse\x12;\n" +
@@ -1414,54 +1953,67 @@ func file_qmdsr_v1_query_proto_rawDescGZIP() []byte {
 	return file_qmdsr_v1_query_proto_rawDescData
 }
 
-var file_qmdsr_v1_query_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_qmdsr_v1_query_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_qmdsr_v1_query_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_qmdsr_v1_query_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_qmdsr_v1_query_proto_goTypes = []any{
-	(Mode)(0),                    // 0: qmdsr.v1.Mode
-	(ServedMode)(0),              // 1: qmdsr.v1.ServedMode
-	(*SearchRequest)(nil),        // 2: qmdsr.v1.SearchRequest
-	(*Hit)(nil),                  // 3: qmdsr.v1.Hit
-	(*SearchResponse)(nil),       // 4: qmdsr.v1.SearchResponse
-	(*GetRequest)(nil),           // 5: qmdsr.v1.GetRequest
-	(*GetResponse)(nil),          // 6: qmdsr.v1.GetResponse
-	(*MultiGetRequest)(nil),      // 7: qmdsr.v1.MultiGetRequest
-	(*DocContent)(nil),           // 8: qmdsr.v1.DocContent
-	(*MultiGetResponse)(nil),     // 9: qmdsr.v1.MultiGetResponse
-	(*SearchAndGetRequest)(nil),  // 10: qmdsr.v1.SearchAndGetRequest
-	(*SearchAndGetResponse)(nil), // 11: qmdsr.v1.SearchAndGetResponse
-	(*HealthRequest)(nil),        // 12: qmdsr.v1.HealthRequest
-	(*ComponentHealth)(nil),      // 13: qmdsr.v1.ComponentHealth
-	(*HealthResponse)(nil),       // 14: qmdsr.v1.HealthResponse
-	(*StatusRequest)(nil),        // 15: qmdsr.v1.StatusRequest
-	(*StatusResponse)(nil),       // 16: qmdsr.v1.StatusResponse
+	(Mode)(0),                     // 0: qmdsr.v1.Mode
+	(ServedMode)(0),               // 1: qmdsr.v1.ServedMode
+	(SearchStage)(0),              // 2: qmdsr.v1.SearchStage
+	(*SearchRequest)(nil),         // 3: qmdsr.v1.SearchRequest
+	(*Hit)(nil),                   // 4: qmdsr.v1.Hit
+	(*SearchResponse)(nil),        // 5: qmdsr.v1.SearchResponse
+	(*SearchFrame)(nil),           // 6: qmdsr.v1.SearchFrame
+	(*GetRequest)(nil),            // 7: qmdsr.v1.GetRequest
+	(*GetResponse)(nil),           // 8: qmdsr.v1.GetResponse
+	(*MultiGetRequest)(nil),       // 9: qmdsr.v1.MultiGetRequest
+	(*DocContent)(nil),            // 10: qmdsr.v1.DocContent
+	(*MultiGetResponse)(nil),      // 11: qmdsr.v1.MultiGetResponse
+	(*SearchAndGetRequest)(nil),   // 12: qmdsr.v1.SearchAndGetRequest
+	(*SearchAndGetResponse)(nil),  // 13: qmdsr.v1.SearchAndGetResponse
+	(*HealthRequest)(nil),         // 14: qmdsr.v1.HealthRequest
+	(*ComponentHealth)(nil),       // 15: qmdsr.v1.ComponentHealth
+	(*HealthResponse)(nil),        // 16: qmdsr.v1.HealthResponse
+	(*RemediationAttempt)(nil),    // 17: qmdsr.v1.RemediationAttempt
+	(*StatusRequest)(nil),         // 18: qmdsr.v1.StatusRequest
+	(*StatusResponse)(nil),        // 19: qmdsr.v1.StatusResponse
+	(*CollectionEmbedStatus)(nil), // 20: qmdsr.v1.CollectionEmbedStatus
+	(*SchedulerTaskSummary)(nil),  // 21: qmdsr.v1.SchedulerTaskSummary
 }
 var file_qmdsr_v1_query_proto_depIdxs = []int32{
 	0,  // 0: qmdsr.v1.SearchRequest.requested_mode:type_name -> qmdsr.v1.Mode
-	3,  // 1: qmdsr.v1.SearchResponse.hits:type_name -> qmdsr.v1.Hit
+	4,  // 1: qmdsr.v1.SearchResponse.hits:type_name -> qmdsr.v1.Hit
 	1,  // 2: qmdsr.v1.SearchResponse.served_mode:type_name -> qmdsr.v1.ServedMode
-	8,  // 3: qmdsr.v1.MultiGetResponse.documents:type_name -> qmdsr.v1.DocContent
-	0,  // 4: qmdsr.v1.SearchAndGetRequest.requested_mode:type_name -> qmdsr.v1.Mode
-	3,  // 5: qmdsr.v1.SearchAndGetResponse.file_hits:type_name -> qmdsr.v1.Hit
-	8,  // 6: qmdsr.v1.SearchAndGetResponse.documents:type_name -> qmdsr.v1.DocContent
-	1,  // 7: qmdsr.v1.SearchAndGetResponse.served_mode:type_name -> qmdsr.v1.ServedMode
-	13, // 8: qmdsr.v1.HealthResponse.components:type_name -> qmdsr.v1.ComponentHealth
-	2,  // 9: qmdsr.v1.QueryService.Search:input_type -> qmdsr.v1.SearchRequest
-	10, // 10: qmdsr.v1.QueryService.SearchAndGet:input_type -> qmdsr.v1.SearchAndGetRequest
-	5,  // 11: qmdsr.v1.QueryService.Get:input_type -> qmdsr.v1.GetRequest
-	7,  // 12: qmdsr.v1.QueryService.MultiGet:input_type -> qmdsr.v1.MultiGetRequest
-	12, // 13: qmdsr.v1.QueryService.Health:input_type -> qmdsr.v1.HealthRequest
-	15, // 14: qmdsr.v1.QueryService.Status:input_type -> qmdsr.v1.StatusRequest
-	4,  // 15: qmdsr.v1.QueryService.Search:output_type -> qmdsr.v1.SearchResponse
-	11, // 16: qmdsr.v1.QueryService.SearchAndGet:output_type -> qmdsr.v1.SearchAndGetResponse
-	6,  // 17: qmdsr.v1.QueryService.Get:output_type -> qmdsr.v1.GetResponse
-	9,  // 18: qmdsr.v1.QueryService.MultiGet:output_type -> qmdsr.v1.MultiGetResponse
-	14, // 19: qmdsr.v1.QueryService.Health:output_type -> qmdsr.v1.HealthResponse
-	16, // 20: qmdsr.v1.QueryService.Status:output_type -> qmdsr.v1.StatusResponse
-	15, // [15:21] is the sub-list for method output_type
-	9,  // [9:15] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	2,  // 3: qmdsr.v1.SearchFrame.stage:type_name -> qmdsr.v1.SearchStage
+	4,  // 4: qmdsr.v1.SearchFrame.hits:type_name -> qmdsr.v1.Hit
+	1,  // 5: qmdsr.v1.SearchFrame.served_mode:type_name -> qmdsr.v1.ServedMode
+	10, // 6: qmdsr.v1.MultiGetResponse.documents:type_name -> qmdsr.v1.DocContent
+	0,  // 7: qmdsr.v1.SearchAndGetRequest.requested_mode:type_name -> qmdsr.v1.Mode
+	4,  // 8: qmdsr.v1.SearchAndGetResponse.file_hits:type_name -> qmdsr.v1.Hit
+	10, // 9: qmdsr.v1.SearchAndGetResponse.documents:type_name -> qmdsr.v1.DocContent
+	1,  // 10: qmdsr.v1.SearchAndGetResponse.served_mode:type_name -> qmdsr.v1.ServedMode
+	15, // 11: qmdsr.v1.HealthResponse.components:type_name -> qmdsr.v1.ComponentHealth
+	17, // 12: qmdsr.v1.HealthResponse.remediations:type_name -> qmdsr.v1.RemediationAttempt
+	20, // 13: qmdsr.v1.StatusResponse.embed:type_name -> qmdsr.v1.CollectionEmbedStatus
+	21, // 14: qmdsr.v1.StatusResponse.scheduler:type_name -> qmdsr.v1.SchedulerTaskSummary
+	3,  // 15: qmdsr.v1.QueryService.Search:input_type -> qmdsr.v1.SearchRequest
+	3,  // 16: qmdsr.v1.QueryService.SearchStream:input_type -> qmdsr.v1.SearchRequest
+	12, // 17: qmdsr.v1.QueryService.SearchAndGet:input_type -> qmdsr.v1.SearchAndGetRequest
+	7,  // 18: qmdsr.v1.QueryService.Get:input_type -> qmdsr.v1.GetRequest
+	9,  // 19: qmdsr.v1.QueryService.MultiGet:input_type -> qmdsr.v1.MultiGetRequest
+	14, // 20: qmdsr.v1.QueryService.Health:input_type -> qmdsr.v1.HealthRequest
+	18, // 21: qmdsr.v1.QueryService.Status:input_type -> qmdsr.v1.StatusRequest
+	5,  // 22: qmdsr.v1.QueryService.Search:output_type -> qmdsr.v1.SearchResponse
+	6,  // 23: qmdsr.v1.QueryService.SearchStream:output_type -> qmdsr.v1.SearchFrame
+	13, // 24: qmdsr.v1.QueryService.SearchAndGet:output_type -> qmdsr.v1.SearchAndGetResponse
+	8,  // 25: qmdsr.v1.QueryService.Get:output_type -> qmdsr.v1.GetResponse
+	11, // 26: qmdsr.v1.QueryService.MultiGet:output_type -> qmdsr.v1.MultiGetResponse
+	16, // 27: qmdsr.v1.QueryService.Health:output_type -> qmdsr.v1.HealthResponse
+	19, // 28: qmdsr.v1.QueryService.Status:output_type -> qmdsr.v1.StatusResponse
+	22, // [22:29] is the sub-list for method output_type
+	15, // [15:22] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_qmdsr_v1_query_proto_init() }
@@ -1474,8 +2026,8 @@ func file_qmdsr_v1_query_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_qmdsr_v1_query_proto_rawDesc), len(file_qmdsr_v1_query_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   15,
+			NumEnums:      3,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
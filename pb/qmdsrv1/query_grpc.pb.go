@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	QueryService_Search_FullMethodName       = "/qmdsr.v1.QueryService/Search"
+	QueryService_SearchStream_FullMethodName = "/qmdsr.v1.QueryService/SearchStream"
 	QueryService_SearchAndGet_FullMethodName = "/qmdsr.v1.QueryService/SearchAndGet"
 	QueryService_Get_FullMethodName          = "/qmdsr.v1.QueryService/Get"
 	QueryService_MultiGet_FullMethodName     = "/qmdsr.v1.QueryService/MultiGet"
@@ -32,6 +33,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type QueryServiceClient interface {
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchFrame], error)
 	SearchAndGet(ctx context.Context, in *SearchAndGetRequest, opts ...grpc.CallOption) (*SearchAndGetResponse, error)
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResponse, error)
@@ -57,6 +59,25 @@ func (c *queryServiceClient) Search(ctx context.Context, in *SearchRequest, opts
 	return out, nil
 }
 
+func (c *queryServiceClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchFrame], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], QueryService_SearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, SearchFrame]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_SearchStreamClient = grpc.ServerStreamingClient[SearchFrame]
+
 func (c *queryServiceClient) SearchAndGet(ctx context.Context, in *SearchAndGetRequest, opts ...grpc.CallOption) (*SearchAndGetResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SearchAndGetResponse)
@@ -112,6 +133,7 @@ func (c *queryServiceClient) Status(ctx context.Context, in *StatusRequest, opts
 // for forward compatibility.
 type QueryServiceServer interface {
 	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchFrame]) error
 	SearchAndGet(context.Context, *SearchAndGetRequest) (*SearchAndGetResponse, error)
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	MultiGet(context.Context, *MultiGetRequest) (*MultiGetResponse, error)
@@ -130,6 +152,9 @@ type UnimplementedQueryServiceServer struct{}
 func (UnimplementedQueryServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedQueryServiceServer) SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchFrame]) error {
+	return status.Error(codes.Unimplemented, "method SearchStream not implemented")
+}
 func (UnimplementedQueryServiceServer) SearchAndGet(context.Context, *SearchAndGetRequest) (*SearchAndGetResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SearchAndGet not implemented")
 }
@@ -184,6 +209,17 @@ func _QueryService_Search_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).SearchStream(m, &grpc.GenericServerStream[SearchRequest, SearchFrame]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_SearchStreamServer = grpc.ServerStreamingServer[SearchFrame]
+
 func _QueryService_SearchAndGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchAndGetRequest)
 	if err := dec(in); err != nil {
@@ -306,6 +342,12 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _QueryService_Status_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchStream",
+			Handler:       _QueryService_SearchStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "qmdsr/v1/query.proto",
 }
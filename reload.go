@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+
+	"qmdsr/api"
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/executor"
+	"qmdsr/orchestrator"
+	"qmdsr/scheduler"
+	"qmdsr/watcher"
+)
+
+// reloader re-reads the config file on SIGHUP or AdminService.ReloadConfig
+// and hands the validated result to every running component. A config that
+// fails to load or validate leaves the running one untouched. Collection
+// edits made through the admin RPCs are persisted to the collections overlay
+// and applied through the same path.
+type reloader struct {
+	path string
+	log  *slog.Logger
+
+	cache   *cache.Cache
+	exec    *executor.CLIExecutor
+	queries *executor.QueryLimiter
+	orch    *orchestrator.Orchestrator
+	sched   *scheduler.Scheduler
+	watcher *watcher.Watcher
+	srv     *api.Server
+
+	mu  sync.Mutex
+	cfg *config.Config
+}
+
+func (r *reloader) Reload(ctx context.Context) (config.Diff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloadLocked(ctx)
+}
+
+func (r *reloader) AddCollection(ctx context.Context, col config.CollectionCfg) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if findCollection(r.cfg, col.Name).Name != "" {
+		return fmt.Errorf("collection %s already exists", col.Name)
+	}
+	return r.changeOverlay(ctx, func(o *config.Overlay) { o.Put(col) })
+}
+
+func (r *reloader) UpdateCollection(ctx context.Context, col config.CollectionCfg) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if findCollection(r.cfg, col.Name).Name == "" {
+		return fmt.Errorf("collection %s not found", col.Name)
+	}
+	return r.changeOverlay(ctx, func(o *config.Overlay) { o.Put(col) })
+}
+
+func (r *reloader) RemoveCollection(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if findCollection(r.cfg, name).Name == "" {
+		return fmt.Errorf("collection %s not found", name)
+	}
+	return r.changeOverlay(ctx, func(o *config.Overlay) { o.Remove(name) })
+}
+
+// changeOverlay persists an edit to the collections overlay and reloads. The
+// previous overlay is restored if the edited config is rejected.
+func (r *reloader) changeOverlay(ctx context.Context, edit func(*config.Overlay)) error {
+	path := r.cfg.Server.CollectionsOverlay
+	if path == "" {
+		return errors.New("server.collections_overlay is required for runtime collection changes")
+	}
+	prev, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("read collections overlay: %w", err)
+	}
+	overlay, err := config.LoadOverlay(path)
+	if err != nil {
+		return err
+	}
+	edit(overlay)
+	if err := overlay.Save(path); err != nil {
+		return err
+	}
+	if _, err := r.reloadLocked(ctx); err != nil {
+		if prev == nil {
+			os.Remove(path)
+		} else if werr := os.WriteFile(path, prev, 0o644); werr != nil {
+			r.log.Error("failed to restore collections overlay", "path", path, "err", werr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *reloader) reloadLocked(ctx context.Context) (config.Diff, error) {
+	next, diff, err := config.Reload(r.path, r.cfg)
+	if err != nil {
+		r.log.Error("config reload rejected, keeping running config", "path", r.path, "err", err)
+		return config.Diff{}, err
+	}
+	if len(diff.RestartRequired) > 0 {
+		r.log.Warn("config changes need a restart to take effect", "keys", diff.RestartRequired)
+	}
+	if !diff.CollectionsModified() && len(diff.Sections) == 0 {
+		r.log.Info("config reloaded, nothing to apply", "path", r.path)
+		return diff, nil
+	}
+
+	prev := r.cfg
+	r.cfg = next
+	if diff.SectionChanged("cache") {
+		if removed := r.cache.Reconfigure(&next.Cache); removed > 0 {
+			r.log.Info("cache resized", "max_entries", next.Cache.MaxEntries, "removed", removed)
+		}
+	}
+	r.exec.ApplyConfig(next)
+	r.queries.ApplyConfig(next)
+	r.orch.ApplyConfig(next)
+	r.sched.ApplyConfig(next)
+	if r.watcher != nil {
+		r.watcher.ApplyConfig(next)
+	}
+	r.srv.ApplyConfig(next)
+
+	if diff.CollectionsModified() {
+		r.invalidateCollections(prev, next, diff)
+		r.syncCollections(context.WithoutCancel(ctx), prev, next, diff)
+	}
+
+	r.log.Info("config reloaded",
+		"path", r.path,
+		"collections_added", diff.CollectionsAdded,
+		"collections_removed", diff.CollectionsRemoved,
+		"collections_changed", diff.CollectionsChanged,
+		"sections", diff.Sections,
+	)
+	return diff, nil
+}
+
+// invalidateCollections drops cached answers affected by collection edits.
+// Adding a collection or moving one between tiers changes which collections
+// a tier search covers, so every cached answer is dropped in that case.
+func (r *reloader) invalidateCollections(prev, next *config.Config, diff config.Diff) {
+	scopeChanged := len(diff.CollectionsAdded) > 0
+	for _, name := range diff.CollectionsChanged {
+		old, cur := findCollection(prev, name), findCollection(next, name)
+		if old.Tier != cur.Tier || old.RequireExplicit != cur.RequireExplicit {
+			scopeChanged = true
+		}
+	}
+	if scopeChanged {
+		r.orch.ClearCache()
+		r.log.Info("search scope changed, cache cleared")
+		return
+	}
+	names := slices.Concat(diff.CollectionsChanged, diff.CollectionsRemoved)
+	if removed := r.cache.InvalidateCollections(names); removed > 0 {
+		r.log.Info("cache invalidated for reloaded collections", "collections", names, "removed", removed)
+	}
+}
+
+// syncCollections brings qmd's registrations in line with the new config and
+// reindexes the added and changed collections in the background. qmd keeps
+// the path and mask a collection was added with, so moved collections are
+// removed first and registered again by EnsureCollections.
+func (r *reloader) syncCollections(ctx context.Context, prev, next *config.Config, diff config.Diff) {
+	var stale []config.CollectionCfg
+	for _, name := range diff.CollectionsRemoved {
+		stale = append(stale, findCollection(prev, name))
+	}
+	for _, name := range diff.CollectionsChanged {
+		old, cur := findCollection(prev, name), findCollection(next, name)
+		if old.Path != cur.Path || old.Mask != cur.Mask {
+			stale = append(stale, old)
+		}
+	}
+	if len(stale) > 0 {
+		if err := r.orch.UnregisterCollections(ctx, stale); err != nil {
+			r.log.Error("failed to unregister collections after reload", "err", err)
+		}
+	}
+	if err := r.orch.EnsureCollections(ctx); err != nil {
+		r.log.Error("failed to ensure collections after reload", "err", err)
+	}
+
+	names := slices.Concat(diff.CollectionsAdded, diff.CollectionsChanged)
+	if len(names) == 0 {
+		return
+	}
+	go func() {
+		if err := r.sched.ReindexCollections(ctx, names); err != nil {
+			r.log.Error("reindex after collection change failed", "collections", names, "err", err)
+		}
+	}()
+}
+
+func (r *reloader) watchSignals(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.log.Info("SIGHUP received, reloading config", "path", r.path)
+			r.Reload(ctx)
+		}
+	}
+}
+
+func findCollection(cfg *config.Config, name string) config.CollectionCfg {
+	for _, col := range cfg.Collections {
+		if col.Name == name {
+			return col
+		}
+	}
+	return config.CollectionCfg{}
+}
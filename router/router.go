@@ -1,12 +1,8 @@
 package router
 
 import (
-	"regexp"
-	"strings"
 	"unicode"
 	"unicode/utf8"
-
-	"qmdsr/internal/textutil"
 )
 
 type Mode string
@@ -15,65 +11,34 @@ const (
 	ModeSearch  Mode = "search"
 	ModeVSearch Mode = "vsearch"
 	ModeQuery   Mode = "query"
+	ModeHybrid  Mode = "hybrid"
 	ModeAuto    Mode = "auto"
 )
 
-var (
-	quotedPattern  = regexp.MustCompile(`"[^"]+"`)
-	questionPrefix = []string{
-		"如何", "怎么", "怎样", "什么", "为什么", "为何",
-		"哪些", "哪个", "哪里", "谁", "多少", "是否",
-		"能不能", "可以", "应该",
-	}
-	temporalWords = []string{
-		"之前", "上次", "昨天", "今天", "最近", "过去",
-		"以前", "历史", "曾经", "earlier", "previous", "last time",
-	}
-)
+var defaultRouter = mustNew(DefaultRules())
 
-func DetectMode(query string, hasVector bool, hasDeepQuery bool) Mode {
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return ModeSearch
-	}
-
-	if quotedPattern.MatchString(query) {
-		return ModeSearch
-	}
-
-	words := textutil.CountWordsMixed(query)
-	if words <= 3 && isPredominantlyASCII(query) {
-		return ModeSearch
+func mustNew(rs RuleSet) *Router {
+	r, err := New(rs)
+	if err != nil {
+		panic(err)
 	}
+	return r
+}
 
-	if hasDeepQuery {
-		for _, prefix := range questionPrefix {
-			if strings.HasPrefix(query, prefix) {
-				return ModeQuery
-			}
-		}
-		for _, word := range temporalWords {
-			if strings.Contains(query, word) {
-				return ModeQuery
+// DetectMode picks the mode of an auto search with the built-in rules.
+func DetectMode(query string, hasVector bool, hasDeepQuery bool) Mode {
+	return defaultRouter.Route(Input{
+		Query: query,
+		HasCapability: func(name string) bool {
+			switch name {
+			case "vector":
+				return hasVector
+			case "deep_query":
+				return hasDeepQuery
 			}
-		}
-		if words > 8 {
-			return ModeQuery
-		}
-	}
-
-	if hasVector && words >= 4 {
-		return ModeVSearch
-	}
-	if hasVector && !isPredominantlyASCII(query) && words >= 2 {
-		return ModeVSearch
-	}
-
-	if words <= 3 && isPredominantlyASCII(query) {
-		return ModeSearch
-	}
-
-	return ModeSearch
+			return false
+		},
+	}).Mode
 }
 
 func isPredominantlyASCII(s string) bool {
@@ -0,0 +1,303 @@
+package router
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"qmdsr/internal/textutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.default.yaml
+var defaultRulesYAML []byte
+
+// Gate overrides the deep query gate for matching queries.
+type Gate string
+
+const (
+	GateAllow Gate = "allow"
+	GateDeny  Gate = "deny"
+)
+
+// RuleSet is the content of a routing rule file.
+type RuleSet struct {
+	// Lists are named keyword lists shared by rules and the deep query gate.
+	Lists map[string][]string `yaml:"lists"`
+	Rules []Rule              `yaml:"rules"`
+}
+
+// Rule picks a mode, collections or gate decision for searches it matches.
+type Rule struct {
+	Name        string   `yaml:"name"`
+	Match       Match    `yaml:"match"`
+	Mode        Mode     `yaml:"mode"`
+	Collections []string `yaml:"collections"`
+	Gate        Gate     `yaml:"gate"`
+}
+
+// Match holds the conditions of a rule. All set conditions must hold; a rule
+// without conditions matches every search.
+type Match struct {
+	// Regex is matched against the trimmed query.
+	Regex string `yaml:"regex"`
+	// Keywords and the entries of KeywordList are matched case-insensitively
+	// as substrings; at least MinKeywordHits (default 1) must occur.
+	Keywords       []string `yaml:"keywords"`
+	KeywordList    string   `yaml:"keyword_list"`
+	MinKeywordHits int      `yaml:"min_keyword_hits"`
+	MinWords       int      `yaml:"min_words"`
+	MaxWords       int      `yaml:"max_words"`
+	MinChars       int      `yaml:"min_chars"`
+	MaxChars       int      `yaml:"max_chars"`
+	// Language is "ascii" for queries that are over 80% ASCII and
+	// "non_ascii" otherwise.
+	Language string `yaml:"language"`
+	// Collections matches requests naming any of these collections.
+	Collections []string `yaml:"collections"`
+	// Clients matches the token principal name of the caller.
+	Clients []string `yaml:"clients"`
+	// Requires lists executor capabilities (vector, deep_query) that must
+	// be available.
+	Requires []string `yaml:"requires"`
+}
+
+// Input describes a search to route.
+type Input struct {
+	Query string
+	// Collections are the collections the request names, if any.
+	Collections   []string
+	Client        string
+	HasCapability func(string) bool
+}
+
+// Decision is the outcome of routing. Each *Rule field names the rule that
+// set the matching decision; it is empty when no rule did.
+type Decision struct {
+	Mode            Mode
+	ModeRule        string
+	Collections     []string
+	CollectionsRule string
+	Gate            Gate
+	GateRule        string
+}
+
+// Router evaluates a compiled rule set.
+type Router struct {
+	rules []compiledRule
+	lists map[string][]string
+}
+
+type compiledRule struct {
+	Rule
+	re       *regexp.Regexp
+	keywords []string
+	minHits  int
+}
+
+// ParseRules decodes a rule file and checks that it compiles.
+func ParseRules(data []byte) (RuleSet, error) {
+	var rs RuleSet
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rs); err != nil {
+		return RuleSet{}, fmt.Errorf("parse routing rules: %w", err)
+	}
+	if len(rs.Rules) == 0 {
+		return RuleSet{}, fmt.Errorf("routing rules: no rules defined")
+	}
+	if _, err := New(rs); err != nil {
+		return RuleSet{}, err
+	}
+	return rs, nil
+}
+
+// LoadRules reads and parses a rule file.
+func LoadRules(path string) (RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("read routing rules %s: %w", path, err)
+	}
+	return ParseRules(data)
+}
+
+// DefaultRules returns the built-in rule set, which reproduces the routing
+// qmdsr had before rules were configurable.
+func DefaultRules() RuleSet {
+	rs, err := ParseRules(defaultRulesYAML)
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}
+
+// New compiles a rule set. An empty rule set selects the built-in defaults.
+func New(rs RuleSet) (*Router, error) {
+	if len(rs.Rules) == 0 {
+		rs = DefaultRules()
+	}
+	r := &Router{lists: make(map[string][]string, len(rs.Lists))}
+	for name, words := range rs.Lists {
+		r.lists[name] = lowerAll(words)
+	}
+	seen := make(map[string]bool, len(rs.Rules))
+	for i, rule := range rs.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("routing rule %d: name is required", i+1)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("routing rule %s: duplicate name", rule.Name)
+		}
+		seen[rule.Name] = true
+		c, err := r.compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("routing rule %s: %w", rule.Name, err)
+		}
+		r.rules = append(r.rules, c)
+	}
+	return r, nil
+}
+
+func (r *Router) compile(rule Rule) (compiledRule, error) {
+	c := compiledRule{Rule: rule, keywords: lowerAll(rule.Match.Keywords), minHits: rule.Match.MinKeywordHits}
+	switch rule.Mode {
+	case "", ModeSearch, ModeVSearch, ModeQuery, ModeHybrid:
+	default:
+		return c, fmt.Errorf("mode %q is not supported", rule.Mode)
+	}
+	switch rule.Gate {
+	case "", GateAllow, GateDeny:
+	default:
+		return c, fmt.Errorf("gate %q is not supported", rule.Gate)
+	}
+	if rule.Mode == "" && len(rule.Collections) == 0 && rule.Gate == "" {
+		return c, fmt.Errorf("one of mode, collections or gate is required")
+	}
+	switch rule.Match.Language {
+	case "", "ascii", "non_ascii":
+	default:
+		return c, fmt.Errorf("language %q is not supported", rule.Match.Language)
+	}
+	if rule.Match.Regex != "" {
+		re, err := regexp.Compile(rule.Match.Regex)
+		if err != nil {
+			return c, fmt.Errorf("regex: %w", err)
+		}
+		c.re = re
+	}
+	if name := rule.Match.KeywordList; name != "" {
+		list, ok := r.lists[name]
+		if !ok {
+			return c, fmt.Errorf("keyword list %q is not defined", name)
+		}
+		c.keywords = append(c.keywords, list...)
+	}
+	if len(c.keywords) == 0 && c.minHits > 0 {
+		return c, fmt.Errorf("min_keyword_hits needs keywords or keyword_list")
+	}
+	if len(c.keywords) > 0 && c.minHits <= 0 {
+		c.minHits = 1
+	}
+	return c, nil
+}
+
+// Route evaluates the rules in order. Searches no mode rule matches use
+// search.
+func (r *Router) Route(in Input) Decision {
+	d := Decision{Mode: ModeSearch}
+	q := strings.TrimSpace(in.Query)
+	f := features{query: q, lower: strings.ToLower(q), words: textutil.CountWordsMixed(q), chars: utf8.RuneCountInString(q), ascii: isPredominantlyASCII(q)}
+	for _, rule := range r.rules {
+		if !rule.matches(in, f) {
+			continue
+		}
+		if rule.Mode != "" && d.ModeRule == "" {
+			d.Mode, d.ModeRule = rule.Mode, rule.Name
+		}
+		if len(rule.Collections) > 0 && d.CollectionsRule == "" {
+			d.Collections, d.CollectionsRule = slices.Clone(rule.Collections), rule.Name
+		}
+		if rule.Gate != "" && d.GateRule == "" {
+			d.Gate, d.GateRule = rule.Gate, rule.Name
+		}
+	}
+	return d
+}
+
+// CountCues returns how many entries of a keyword list occur in s.
+func (r *Router) CountCues(list, s string) int {
+	return countHits(r.lists[list], strings.ToLower(s))
+}
+
+type features struct {
+	query, lower string
+	words, chars int
+	ascii        bool
+}
+
+func (c compiledRule) matches(in Input, f features) bool {
+	m := c.Match
+	if len(m.Requires) > 0 {
+		if in.HasCapability == nil {
+			return false
+		}
+		for _, name := range m.Requires {
+			if !in.HasCapability(name) {
+				return false
+			}
+		}
+	}
+	if len(m.Clients) > 0 && !slices.Contains(m.Clients, in.Client) {
+		return false
+	}
+	if len(m.Collections) > 0 && !slices.ContainsFunc(in.Collections, func(name string) bool { return slices.Contains(m.Collections, name) }) {
+		return false
+	}
+	if (m.MinWords > 0 && f.words < m.MinWords) || (m.MaxWords > 0 && f.words > m.MaxWords) {
+		return false
+	}
+	if (m.MinChars > 0 && f.chars < m.MinChars) || (m.MaxChars > 0 && f.chars > m.MaxChars) {
+		return false
+	}
+	switch m.Language {
+	case "ascii":
+		if !f.ascii {
+			return false
+		}
+	case "non_ascii":
+		if f.ascii {
+			return false
+		}
+	}
+	if c.re != nil && !c.re.MatchString(f.query) {
+		return false
+	}
+	if c.minHits > 0 && countHits(c.keywords, f.lower) < c.minHits {
+		return false
+	}
+	return true
+}
+
+func countHits(words []string, lower string) int {
+	n := 0
+	for _, w := range words {
+		if strings.Contains(lower, w) {
+			n++
+		}
+	}
+	return n
+}
+
+func lowerAll(words []string) []string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		out[i] = strings.ToLower(w)
+	}
+	return out
+}
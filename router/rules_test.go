@@ -0,0 +1,133 @@
+package router
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func caps(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestDefaultRules_NameTheRuleThatFired(t *testing.T) {
+	r, err := New(RuleSet{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	all := caps("vector", "deep_query")
+	cases := []struct {
+		query string
+		has   func(string) bool
+		mode  Mode
+		rule  string
+	}{
+		{`find "exact phrase" please now`, all, ModeSearch, "quoted_phrase"},
+		{"grpc retry policy", all, ModeSearch, "short_ascii"},
+		{"如何配置流量整形", all, ModeQuery, "question_prefix"},
+		{"上次 讨论 的 结论", all, ModeQuery, "temporal"},
+		{"what did we decide about Earlier rollout plans", all, ModeQuery, "temporal"},
+		{"one two three four five six seven eight nine", all, ModeQuery, "long_query"},
+		{"memory retrieval fallback behavior", all, ModeVSearch, "semantic"},
+		{"ñandú café", caps("vector"), ModeVSearch, "semantic_non_ascii"},
+		{"memory retrieval fallback behavior", caps(), ModeSearch, "default"},
+	}
+	for _, tc := range cases {
+		d := r.Route(Input{Query: tc.query, HasCapability: tc.has})
+		if d.Mode != tc.mode || d.ModeRule != tc.rule {
+			t.Errorf("Route(%q) = %s by %q, want %s by %q", tc.query, d.Mode, d.ModeRule, tc.mode, tc.rule)
+		}
+	}
+}
+
+func TestRoute_FirstMatchWinsPerDecision(t *testing.T) {
+	rs, err := ParseRules([]byte(`
+lists:
+  ops: [nginx, haproxy, 流量整形]
+rules:
+  - name: bot_no_deep
+    match: {clients: [bot]}
+    gate: deny
+  - name: runbooks
+    match: {keyword_list: ops, min_keyword_hits: 2}
+    mode: search
+    collections: [runbooks]
+  - name: ops
+    match: {keyword_list: ops}
+    mode: vsearch
+    collections: [ops]
+  - name: personal_deep
+    match: {collections: [personal], min_chars: 10}
+    mode: query
+    gate: allow
+  - name: fallback
+    mode: hybrid
+`))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	r, err := New(rs)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	d := r.Route(Input{Query: "NGINX vs haproxy timeouts", Client: "bot"})
+	want := Decision{Mode: ModeSearch, ModeRule: "runbooks", Collections: []string{"runbooks"}, CollectionsRule: "runbooks", Gate: GateDeny, GateRule: "bot_no_deep"}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("two ops keywords: got %+v, want %+v", d, want)
+	}
+
+	d = r.Route(Input{Query: "流量整形 配置"})
+	if d.ModeRule != "ops" || !reflect.DeepEqual(d.Collections, []string{"ops"}) || d.GateRule != "" {
+		t.Errorf("one ops keyword: got %+v", d)
+	}
+
+	d = r.Route(Input{Query: "what did I write last spring", Collections: []string{"notes", "personal"}})
+	if d.Mode != ModeQuery || d.Gate != GateAllow || d.GateRule != "personal_deep" {
+		t.Errorf("personal collection: got %+v", d)
+	}
+
+	d = r.Route(Input{Query: "short", Collections: []string{"personal"}})
+	if d.Mode != ModeHybrid || d.ModeRule != "fallback" || d.Gate != "" {
+		t.Errorf("min_chars not met: got %+v", d)
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"no rules":      `lists: {a: [x]}`,
+		"unknown field": "rules:\n  - name: a\n    mode: search\n    modes: query\n",
+		"no output":     "rules:\n  - name: a\n    match: {min_words: 2}\n",
+		"bad mode":      "rules:\n  - name: a\n    mode: auto\n",
+		"bad gate":      "rules:\n  - name: a\n    gate: maybe\n",
+		"bad regex":     "rules:\n  - name: a\n    match: {regex: '('}\n    mode: search\n",
+		"missing list":  "rules:\n  - name: a\n    match: {keyword_list: nope}\n    mode: search\n",
+		"duplicate":     "rules:\n  - name: a\n    mode: search\n  - name: a\n    mode: query\n",
+		"bad language":  "rules:\n  - name: a\n    match: {language: fr}\n    mode: search\n",
+		"hits no words": "rules:\n  - name: a\n    match: {min_keyword_hits: 2}\n    mode: search\n",
+	}
+	for name, data := range cases {
+		if _, err := ParseRules([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if !strings.Contains(err.Error(), "routing rule") {
+			t.Errorf("%s: error %q should mention the routing rules", name, err)
+		}
+	}
+}
+
+func TestCountCues(t *testing.T) {
+	r, _ := New(RuleSet{})
+	if got := r.CountCues("abstract", "Migration roadmap 和 架构 设计"); got != 4 {
+		t.Errorf("abstract cues = %d, want 4", got)
+	}
+	if got := r.CountCues("missing", "anything"); got != 0 {
+		t.Errorf("unknown list = %d, want 0", got)
+	}
+}
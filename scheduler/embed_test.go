@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/executor"
+)
+
+type fakeEmbedExec struct {
+	executor.Executor
+	perCollection bool
+	embedded      []string
+	globalEmbeds  int
+}
+
+func (f *fakeEmbedExec) HasCapability(cap string) bool {
+	return cap == "collection_embed" && f.perCollection
+}
+
+func (f *fakeEmbedExec) Embed(context.Context, bool) error {
+	f.globalEmbeds++
+	return nil
+}
+
+func (f *fakeEmbedExec) EmbedCollection(_ context.Context, name string, _ bool) error {
+	f.embedded = append(f.embedded, name)
+	return nil
+}
+
+func newEmbedTestScheduler(exec executor.Executor) *Scheduler {
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{
+			{Name: "notes", Embed: true, EmbedInterval: time.Hour},
+			{Name: "journal", Embed: true},
+			{Name: "archive"},
+		},
+		Scheduler: config.SchedulerConfig{EmbedRefresh: 24 * time.Hour},
+	}
+	return New(cfg, exec, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestTaskEmbed_UsesPerCollectionIntervals(t *testing.T) {
+	exec := &fakeEmbedExec{perCollection: true}
+	s := newEmbedTestScheduler(exec)
+
+	start := time.Now()
+	if due := s.dueEmbedCollections(start); len(due) != 0 {
+		t.Fatalf("nothing should be due right after startup, got %v", due)
+	}
+	if due := s.dueEmbedCollections(start.Add(2 * time.Hour)); !slices.Equal(due, []string{"notes"}) {
+		t.Fatalf("expected only notes due after 2h, got %v", due)
+	}
+	if due := s.dueEmbedCollections(start.Add(25 * time.Hour)); !slices.Equal(due, []string{"notes", "journal"}) {
+		t.Fatalf("expected notes and journal due after 25h, got %v", due)
+	}
+
+	if err := s.TriggerEmbed(context.Background(), false); err != nil {
+		t.Fatalf("TriggerEmbed failed: %v", err)
+	}
+	if !slices.Equal(exec.embedded, []string{"notes", "journal"}) || exec.globalEmbeds != 0 {
+		t.Fatalf("expected embed scoped to embed: true collections, got %v global=%d", exec.embedded, exec.globalEmbeds)
+	}
+	for _, st := range s.EmbedStatus() {
+		if st.LastEmbedded.Before(start) {
+			t.Fatalf("last embed time not recorded for %s", st.Collection)
+		}
+	}
+}
+
+func TestEmbedCollections_FallsBackToGlobalEmbed(t *testing.T) {
+	exec := &fakeEmbedExec{}
+	s := newEmbedTestScheduler(exec)
+
+	if err := s.TriggerEmbed(context.Background(), true); err != nil {
+		t.Fatalf("TriggerEmbed failed: %v", err)
+	}
+	if exec.globalEmbeds != 1 || len(exec.embedded) != 0 {
+		t.Fatalf("expected one global embed, got global=%d per-collection=%v", exec.globalEmbeds, exec.embedded)
+	}
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/internal/pathmatch"
+)
+
+// collectionFingerprint hashes path, size and mtime of every file the
+// collection mask selects. qmd update does not report what it touched, so
+// comparing fingerprints across refreshes tells which collections changed.
+func collectionFingerprint(col config.CollectionCfg) (string, error) {
+	h := fnv.New64a()
+	root := filepath.Clean(col.Path)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if !pathmatch.MatchMask(col.Mask, rel) || pathmatch.Excluded(col.Exclude, rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", rel, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x", h.Sum64()), nil
+}
+
+// collectionVersions fingerprints cols for use as per-collection cache
+// versions. Because the version is derived from file contents, it stays valid
+// across restarts and a refresh that changed nothing keeps cached entries. A
+// tree that cannot be read gets a unique version so its entries are dropped.
+func (s *Scheduler) collectionVersions(cols []config.CollectionCfg) map[string]string {
+	versions := make(map[string]string, len(cols))
+	for _, col := range cols {
+		fp, err := collectionFingerprint(col)
+		if err != nil {
+			s.log.Warn("collection fingerprint failed", "collection", col.Name, "err", err)
+			fp = "unreadable-" + time.Now().Format("20060102150405.000000000")
+		}
+		versions[col.Name] = fp
+	}
+	return versions
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+)
+
+func TestCollectionVersions_InvalidateOnlyChangedCollections(t *testing.T) {
+	notes, work := t.TempDir(), t.TempDir()
+	for _, p := range []string{filepath.Join(notes, "a.md"), filepath.Join(work, "b.md")} {
+		if err := os.WriteFile(p, []byte("v1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Collections: []config.CollectionCfg{
+		{Name: "notes", Path: notes},
+		{Name: "work", Path: work},
+	}}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true})
+	s := New(cfg, nil, c, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if changed, _ := c.SetCollectionVersions(s.collectionVersions(cfg.Collections)); !reflect.DeepEqual(changed, []string{"notes", "work"}) {
+		t.Fatalf("expected all collections versioned on first refresh, got %v", changed)
+	}
+	c.Put("notes-query", cache.Entry{Collections: []string{"notes"}})
+	c.Put("work-query", cache.Entry{Collections: []string{"work"}})
+
+	if changed, removed := c.SetCollectionVersions(s.collectionVersions(cfg.Collections)); len(changed) != 0 || removed != 0 {
+		t.Fatalf("expected no changes, got %v (removed %d)", changed, removed)
+	}
+
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(work, "b.md"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(notes, "ignored.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed, removed := c.SetCollectionVersions(s.collectionVersions(cfg.Collections))
+	if !reflect.DeepEqual(changed, []string{"work"}) || removed != 1 {
+		t.Fatalf("expected only work invalidated, got %v (removed %d)", changed, removed)
+	}
+	if _, ok := c.Get("notes-query"); !ok {
+		t.Fatalf("expected notes entry to survive")
+	}
+}
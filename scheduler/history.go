@@ -0,0 +1,289 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/internal/schedule"
+	"qmdsr/model"
+)
+
+const (
+	// historySize bounds the runs remembered per task.
+	historySize = 20
+	// staleAfter is how many scheduled periods a task may go without a
+	// successful run before the scheduler reports itself degraded.
+	staleAfter = 3
+)
+
+// TaskRun is one finished run of a task, including its retries.
+type TaskRun struct {
+	Task     string
+	JobID    string
+	Start    time.Time
+	Duration time.Duration
+	Attempts int
+	Err      string
+	// CacheVersions are the collection versions a reindex recorded in the
+	// result cache, for the collections whose files changed.
+	CacheVersions map[string]string
+}
+
+// TaskStatus summarises the schedule and recent runs of one task.
+type TaskStatus struct {
+	Task        string
+	Schedule    string
+	LastRun     time.Time
+	NextRun     time.Time
+	LastSuccess time.Time
+	Running     bool
+	Stale       bool
+	// History lists recent runs, newest first.
+	History []TaskRun
+}
+
+type taskRun struct {
+	mu   sync.Mutex
+	info TaskRun
+	// noop marks a run that found nothing to do; it is not recorded.
+	noop bool
+}
+
+type runKey struct{}
+
+func withRun(ctx context.Context, run *taskRun) context.Context {
+	return context.WithValue(ctx, runKey{}, run)
+}
+
+func runFromContext(ctx context.Context) *taskRun {
+	run, _ := ctx.Value(runKey{}).(*taskRun)
+	return run
+}
+
+func (r *taskRun) addAttempt() {
+	r.mu.Lock()
+	r.info.Attempts++
+	r.mu.Unlock()
+}
+
+func markNoop(ctx context.Context) {
+	if run := runFromContext(ctx); run != nil {
+		run.mu.Lock()
+		run.noop = true
+		run.mu.Unlock()
+	}
+}
+
+// recordCacheVersions attaches the versions of changed collections to the
+// current run.
+func recordCacheVersions(ctx context.Context, versions map[string]string, changed []string) {
+	run := runFromContext(ctx)
+	if run == nil || len(changed) == 0 {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.info.CacheVersions == nil {
+		run.info.CacheVersions = make(map[string]string, len(changed))
+	}
+	for _, name := range changed {
+		run.info.CacheVersions[name] = versions[name]
+	}
+}
+
+func (s *Scheduler) recordRun(run *taskRun, elapsed time.Duration, err error) {
+	run.mu.Lock()
+	if run.noop && err == nil {
+		run.mu.Unlock()
+		return
+	}
+	info := run.info
+	info.Duration = elapsed
+	if err != nil {
+		info.Err = err.Error()
+	}
+	run.mu.Unlock()
+
+	s.historyMu.Lock()
+	runs := append(s.history[info.Task], info)
+	if len(runs) > historySize {
+		runs = slices.Delete(runs, 0, len(runs)-historySize)
+	}
+	s.history[info.Task] = runs
+	s.historyMu.Unlock()
+
+	if err != nil {
+		return
+	}
+	s.stateMu.Lock()
+	st := s.tasks[info.Task]
+	st.LastSuccess = info.Start.Add(elapsed)
+	s.tasks[info.Task] = st
+	s.stateMu.Unlock()
+	s.saveState()
+}
+
+// Status reports schedule, staleness and recent runs of every task.
+func (s *Scheduler) Status() []TaskStatus {
+	cfg := s.config()
+	now := time.Now()
+	triggers := s.triggers(cfg)
+	states := s.TaskStates()
+
+	s.historyMu.Lock()
+	history := make(map[string][]TaskRun, len(s.history))
+	for name, runs := range s.history {
+		history[name] = slices.Clone(runs)
+	}
+	s.historyMu.Unlock()
+
+	names := slices.Sorted(maps.Keys(triggers))
+	for _, name := range slices.Sorted(maps.Keys(history)) {
+		if _, ok := triggers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	s.mu.Lock()
+	running := maps.Clone(s.running)
+	s.mu.Unlock()
+
+	out := make([]TaskStatus, 0, len(names))
+	for _, name := range names {
+		st := states[name]
+		ts := TaskStatus{
+			Task:        name,
+			LastRun:     st.LastRun,
+			NextRun:     st.NextRun,
+			LastSuccess: st.LastSuccess,
+		}
+		_, ts.Running = running[name]
+		if trig, ok := triggers[name]; ok {
+			ts.Schedule = trig.String()
+			if name == "embed_refresh" {
+				ts.Schedule, ts.NextRun = s.embedSchedule(cfg)
+			}
+			if s.watchStaleness(cfg, name) {
+				ts.Stale, _ = s.stale(cfg, trig, st.LastSuccess, now)
+			}
+		}
+		runs := history[name]
+		slices.Reverse(runs)
+		ts.History = runs
+		if len(runs) > 0 && runs[0].Start.After(ts.LastRun) {
+			ts.LastRun = runs[0].Start
+		}
+		out = append(out, ts)
+	}
+	return out
+}
+
+// CheckStaleness is the heartbeat check for scheduled index maintenance
+// that has stopped succeeding.
+func (s *Scheduler) CheckStaleness(context.Context) (model.HealthLevel, string) {
+	cfg := s.config()
+	now := time.Now()
+	states := s.TaskStates()
+	triggers := s.triggers(cfg)
+	var stale []string
+	for _, name := range slices.Sorted(maps.Keys(triggers)) {
+		if !s.watchStaleness(cfg, name) {
+			continue
+		}
+		trig := triggers[name]
+		if ok, since := s.stale(cfg, trig, states[name].LastSuccess, now); ok {
+			stale = append(stale, fmt.Sprintf("%s has not succeeded for %s (schedule %s)", name, since.Round(time.Minute), trig))
+		}
+	}
+	if len(stale) > 0 {
+		return model.Degraded, strings.Join(stale, "; ")
+	}
+	return model.Healthy, ""
+}
+
+// stale reports whether a task has gone staleAfter periods without a
+// successful run, counting neither quiet hours nor time before the
+// scheduler started. A run held back by a busy host for up to
+// scheduler.max_deferral is not stale either.
+func (s *Scheduler) stale(cfg *config.Config, trig trigger, lastSuccess, now time.Time) (bool, time.Duration) {
+	s.stateMu.Lock()
+	baseline := s.startedAt
+	s.stateMu.Unlock()
+	if lastSuccess.After(baseline) {
+		baseline = lastSuccess
+	}
+	if baseline.IsZero() {
+		return false, 0
+	}
+	first := trig.next(now)
+	period := trig.next(first).Sub(first)
+	if period <= 0 {
+		return false, 0
+	}
+	since := now.Sub(baseline)
+	if windows, err := schedule.ParseWindows(cfg.Scheduler.QuietHours); err == nil {
+		since -= schedule.Overlap(windows, baseline, now)
+	}
+	return since > staleAfter*period+cfg.Scheduler.MaxDeferral, now.Sub(baseline)
+}
+
+// triggers returns the schedule of every task the scheduler runs with the
+// current config.
+func (s *Scheduler) triggers(cfg *config.Config) map[string]trigger {
+	out := map[string]trigger{
+		"index_refresh": indexTrigger(cfg.Scheduler),
+		"cache_cleanup": intervalTrigger(cfg.Scheduler.CacheCleanup),
+	}
+	if s.embedEnabled(cfg) {
+		out["embed_refresh"] = intervalTrigger(cfg.Scheduler.EmbedRefresh)
+		out["embed_full_refresh"] = embedFullTrigger(cfg.Scheduler)
+	}
+	return out
+}
+
+// embedSchedule describes embed_refresh by the configured embed intervals,
+// listing collections with their own, and returns when the next collection
+// falls due. The loop itself checks every embedCheckInterval.
+func (s *Scheduler) embedSchedule(cfg *config.Config) (string, time.Time) {
+	s.embedMu.Lock()
+	since := s.embedSince
+	s.embedMu.Unlock()
+
+	var own []string
+	var next time.Time
+	for _, st := range s.EmbedStatus() {
+		if st.Interval != cfg.Scheduler.EmbedRefresh {
+			own = append(own, st.Collection+"="+st.Interval.String())
+		}
+		last := st.LastEmbedded
+		if last.IsZero() {
+			last = since
+		}
+		if due := last.Add(st.Interval); next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	schedule := cfg.Scheduler.EmbedRefresh.String()
+	if len(own) > 0 {
+		schedule += " (" + strings.Join(own, ", ") + ")"
+	}
+	return schedule, next
+}
+
+// watchStaleness limits the staleness check to the tasks that keep the
+// index and the embeddings current.
+func (s *Scheduler) watchStaleness(cfg *config.Config, task string) bool {
+	switch task {
+	case "index_refresh":
+		return true
+	case "embed_full_refresh":
+		return s.embedEnabled(cfg) && len(s.embedCollectionNames()) > 0
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/model"
+)
+
+func TestRunTask_RecordsHistory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	exec := &fakeUpdateExec{updates: make(chan struct{}, 4)}
+	s := newStateTestScheduler(t, "", exec, config.SchedulerConfig{IndexRefresh: time.Hour})
+	s.cfg.Collections = []config.CollectionCfg{{Name: "notes", Path: dir}}
+
+	if err := s.TriggerReindex(context.Background()); err != nil {
+		t.Fatalf("TriggerReindex failed: %v", err)
+	}
+	calls := 0
+	err := s.runTask(context.Background(), "cache_cleanup", func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+
+	status := make(map[string]TaskStatus)
+	for _, ts := range s.Status() {
+		status[ts.Task] = ts
+	}
+	reindex := status["index_refresh"]
+	if len(reindex.History) != 1 || reindex.LastSuccess.IsZero() || reindex.Schedule != "1h0m0s" {
+		t.Fatalf("unexpected index_refresh status: %+v", reindex)
+	}
+	if v := reindex.History[0].CacheVersions["notes"]; v == "" {
+		t.Fatalf("expected the cache version of notes recorded, got %+v", reindex.History[0])
+	}
+	cleanup := status["cache_cleanup"]
+	if len(cleanup.History) != 1 || cleanup.History[0].Attempts != 2 || cleanup.History[0].Err != "" {
+		t.Fatalf("unexpected cache_cleanup history: %+v", cleanup.History)
+	}
+}
+
+func TestCheckStaleness(t *testing.T) {
+	s := newStateTestScheduler(t, "", nil, config.SchedulerConfig{IndexRefresh: time.Hour})
+	s.startedAt = time.Now().Add(-4 * time.Hour)
+
+	if level, msg := s.CheckStaleness(context.Background()); level != model.Degraded || msg == "" {
+		t.Fatalf("expected degraded after 4h without a successful index_refresh, got %v %q", level, msg)
+	}
+
+	s.ApplyConfig(&config.Config{Scheduler: config.SchedulerConfig{IndexRefresh: time.Hour, QuietHours: "00:00-24:00"}})
+	if level, msg := s.CheckStaleness(context.Background()); level != model.Healthy {
+		t.Fatalf("quiet hours should not count towards staleness, got %v %q", level, msg)
+	}
+
+	s.ApplyConfig(&config.Config{Scheduler: config.SchedulerConfig{IndexRefresh: time.Hour, MaxDeferral: 2 * time.Hour}})
+	if level, msg := s.CheckStaleness(context.Background()); level != model.Healthy {
+		t.Fatalf("a run deferred up to max_deferral should not be stale, got %v %q", level, msg)
+	}
+
+	s.ApplyConfig(&config.Config{Scheduler: config.SchedulerConfig{IndexRefresh: time.Hour}})
+	s.recordRun(&taskRun{info: TaskRun{Task: "index_refresh", Start: time.Now()}}, time.Second, nil)
+	if level, msg := s.CheckStaleness(context.Background()); level != model.Healthy {
+		t.Fatalf("expected healthy after a successful run, got %v %q", level, msg)
+	}
+}
+
+func TestStatus_ReportsConfiguredEmbedIntervals(t *testing.T) {
+	s := newEmbedTestScheduler(&fakeEmbedExec{perCollection: true})
+	embedded := time.Now().Add(-30 * time.Minute)
+	s.markEmbedded([]string{"notes"}, embedded)
+
+	for _, ts := range s.Status() {
+		if ts.Task != "embed_refresh" {
+			continue
+		}
+		if want := "24h0m0s (notes=1h0m0s)"; ts.Schedule != want {
+			t.Errorf("embed_refresh schedule = %q, want %q", ts.Schedule, want)
+		}
+		if want := embedded.Add(time.Hour); !ts.NextRun.Equal(want) {
+			t.Errorf("embed_refresh next run = %v, want notes due at %v", ts.NextRun, want)
+		}
+		return
+	}
+	t.Fatalf("embed_refresh missing from Status")
+}
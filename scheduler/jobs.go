@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"qmdsr/executor"
+)
+
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+const (
+	// maxFinishedJobs bounds how many finished jobs ListJobs remembers.
+	maxFinishedJobs = 50
+	stderrTailBytes = 4096
+)
+
+// ErrTaskRunning is returned when a task is started while a previous run of
+// the same task has not finished.
+var ErrTaskRunning = errors.New("task already running")
+
+// ErrStopped is returned when a task is started after Stop.
+var ErrStopped = errors.New("scheduler stopped")
+
+// Job is a snapshot of an admin-triggered reindex or embed.
+type Job struct {
+	ID         string
+	Kind       string
+	TraceID    string
+	State      JobState
+	StartedAt  time.Time
+	EndedAt    time.Time
+	Retries    int
+	StderrTail string
+	Err        string
+}
+
+type job struct {
+	mu     sync.Mutex
+	info   Job
+	tail   []byte
+	cancel context.CancelFunc
+}
+
+type jobKey struct{}
+
+func withJob(ctx context.Context, j *job) context.Context {
+	return executor.WithStderr(context.WithValue(ctx, jobKey{}, j), j)
+}
+
+func jobFromContext(ctx context.Context) *job {
+	j, _ := ctx.Value(jobKey{}).(*job)
+	return j
+}
+
+// Write keeps the last stderrTailBytes of qmd stderr.
+func (j *job) Write(p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.tail = append(j.tail, p...)
+	if over := len(j.tail) - stderrTailBytes; over > 0 {
+		j.tail = slices.Delete(j.tail, 0, over)
+	}
+	return len(p), nil
+}
+
+func (j *job) snapshot() Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	info := j.info
+	info.StderrTail = string(j.tail)
+	return info
+}
+
+func (j *job) addRetry() {
+	j.mu.Lock()
+	j.info.Retries++
+	j.mu.Unlock()
+}
+
+func (j *job) finish(ctx context.Context, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.EndedAt = time.Now()
+	switch {
+	case err == nil:
+		j.info.State = JobSucceeded
+	case ctx.Err() != nil:
+		j.info.State = JobCancelled
+		j.info.Err = err.Error()
+	default:
+		j.info.State = JobFailed
+		j.info.Err = err.Error()
+	}
+}
+
+// SubmitReindex starts qmd update as a background job and returns at once.
+func (s *Scheduler) SubmitReindex(traceID string) (Job, error) {
+	return s.submitJob("reindex", "index_refresh", traceID, s.reindexAll)
+}
+
+// SubmitEmbed starts embedding of all embed: true collections as a
+// background job and returns at once.
+func (s *Scheduler) SubmitEmbed(force bool, traceID string) (Job, error) {
+	kind, task := "embed", "embed_refresh"
+	if force {
+		kind, task = "embed_full", "embed_full_refresh"
+	}
+	return s.submitJob(kind, task, traceID, func(ctx context.Context) error {
+		return s.embedCollections(ctx, s.embedCollectionNames(), force)
+	})
+}
+
+func (s *Scheduler) submitJob(kind, task, traceID string, fn func(context.Context) error) (Job, error) {
+	j := &job{info: Job{
+		ID:        newJobID(),
+		Kind:      kind,
+		TraceID:   traceID,
+		State:     JobRunning,
+		StartedAt: time.Now(),
+	}}
+	if err := s.acquire(task, j.info.ID); err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(s.jobsCtx)
+	j.cancel = cancel
+	s.jobsMu.Lock()
+	s.jobs[j.info.ID] = j
+	s.jobOrder = append(s.jobOrder, j.info.ID)
+	s.pruneJobsLocked()
+	s.jobsMu.Unlock()
+
+	s.log.Info("job started", "job", j.info.ID, "kind", kind, "trace_id", traceID)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		defer s.release(task)
+		ctx := withJob(ctx, j)
+		err := s.execTask(ctx, task, fn)
+		j.finish(ctx, err)
+		info := j.snapshot()
+		s.log.Info("job finished", "job", info.ID, "kind", kind, "state", info.State, "retries", info.Retries, "elapsed", info.EndedAt.Sub(info.StartedAt))
+	}()
+	return j.snapshot(), nil
+}
+
+// GetJob returns the job with the given id.
+func (s *Scheduler) GetJob(id string) (Job, error) {
+	s.jobsMu.Lock()
+	j, ok := s.jobs[id]
+	s.jobsMu.Unlock()
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	return j.snapshot(), nil
+}
+
+// ListJobs returns running and recently finished jobs, newest first.
+func (s *Scheduler) ListJobs() []Job {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	out := make([]Job, 0, len(s.jobOrder))
+	for _, id := range slices.Backward(s.jobOrder) {
+		out = append(out, s.jobs[id].snapshot())
+	}
+	return out
+}
+
+// CancelJob cancels a running job, which kills its qmd process group.
+// Cancelling a finished job returns it unchanged.
+func (s *Scheduler) CancelJob(id string) (Job, error) {
+	s.jobsMu.Lock()
+	j, ok := s.jobs[id]
+	s.jobsMu.Unlock()
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	if j.snapshot().State == JobRunning {
+		s.log.Info("cancelling job", "job", id)
+		j.cancel()
+	}
+	return j.snapshot(), nil
+}
+
+func (s *Scheduler) pruneJobsLocked() {
+	finished := 0
+	for _, id := range s.jobOrder {
+		if s.jobs[id].snapshot().State != JobRunning {
+			finished++
+		}
+	}
+	s.jobOrder = slices.DeleteFunc(s.jobOrder, func(id string) bool {
+		if finished <= maxFinishedJobs || s.jobs[id].snapshot().State == JobRunning {
+			return false
+		}
+		finished--
+		delete(s.jobs, id)
+		return true
+	})
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
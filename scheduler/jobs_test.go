@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeJobExec struct {
+	fakeEmbedExec
+	started chan struct{}
+}
+
+// EmbedCollection blocks until the job is cancelled, like a long qmd embed.
+func (f *fakeJobExec) EmbedCollection(ctx context.Context, name string, _ bool) error {
+	if j := jobFromContext(ctx); j != nil {
+		fmt.Fprintf(j, "embedding %s\n", name)
+	}
+	f.started <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func waitForJob(t *testing.T, s *Scheduler, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := s.GetJob(id)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if job.State != JobRunning {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestSubmitEmbed_CancelStopsJob(t *testing.T) {
+	exec := &fakeJobExec{fakeEmbedExec: fakeEmbedExec{perCollection: true}, started: make(chan struct{}, 1)}
+	s := newEmbedTestScheduler(exec)
+
+	job, err := s.SubmitEmbed(false, "trace-1")
+	if err != nil {
+		t.Fatalf("SubmitEmbed failed: %v", err)
+	}
+	if job.ID == "" || job.State != JobRunning || job.Kind != "embed" || job.TraceID != "trace-1" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	<-exec.started
+
+	_, err = s.SubmitEmbed(false, "trace-2")
+	if !errors.Is(err, ErrTaskRunning) || !strings.Contains(err.Error(), job.ID) {
+		t.Fatalf("expected ErrTaskRunning naming job %s, got %v", job.ID, err)
+	}
+
+	if _, err := s.CancelJob(job.ID); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+	done := waitForJob(t, s, job.ID)
+	if done.State != JobCancelled || done.EndedAt.IsZero() || done.Err == "" {
+		t.Fatalf("expected cancelled job with error, got %+v", done)
+	}
+	if done.Retries != 0 {
+		t.Fatalf("cancelled job should not be retried, got %d retries", done.Retries)
+	}
+	if !strings.Contains(done.StderrTail, "embedding notes") {
+		t.Fatalf("expected stderr tail captured, got %q", done.StderrTail)
+	}
+
+	if _, err := s.SubmitEmbed(false, ""); err != nil {
+		t.Fatalf("expected a new job after cancel, got %v", err)
+	}
+	<-exec.started
+	if jobs := s.ListJobs(); len(jobs) != 2 || jobs[1].ID != job.ID {
+		t.Fatalf("expected newest-first job list, got %+v", jobs)
+	}
+	s.Stop()
+}
+
+func TestStop_WaitsForRunningJobs(t *testing.T) {
+	exec := &fakeJobExec{fakeEmbedExec: fakeEmbedExec{perCollection: true}, started: make(chan struct{}, 2)}
+	s := newEmbedTestScheduler(exec)
+
+	job, err := s.SubmitEmbed(true, "")
+	if err != nil {
+		t.Fatalf("SubmitEmbed failed: %v", err)
+	}
+	<-exec.started
+	s.Stop()
+
+	if got, _ := s.GetJob(job.ID); got.State != JobCancelled {
+		t.Fatalf("expected the job to have finished when Stop returns, got %+v", got)
+	}
+	if _, err := s.SubmitReindex(""); !errors.Is(err, ErrStopped) {
+		t.Fatalf("expected ErrStopped after Stop, got %v", err)
+	}
+}
+
+func TestGetJob_Unknown(t *testing.T) {
+	s := newEmbedTestScheduler(&fakeEmbedExec{})
+	if _, err := s.GetJob("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestJobWrite_KeepsTail(t *testing.T) {
+	j := &job{}
+	fmt.Fprint(j, strings.Repeat("a", stderrTailBytes))
+	fmt.Fprint(j, "end")
+	tail := j.snapshot().StderrTail
+	if len(tail) != stderrTailBytes || !strings.HasSuffix(tail, "aend") {
+		t.Fatalf("unexpected tail length %d suffix %q", len(tail), tail[len(tail)-4:])
+	}
+}
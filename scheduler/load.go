@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"qmdsr/internal/resourceguard"
+)
+
+// Load reports the interactive work scheduled index maintenance yields to.
+type Load interface {
+	CPUSnapshot() resourceguard.CPUSnapshot
+	InFlightSearches() int
+}
+
+// watchLoad tracks since when the host has been idle: no CPU overload and no
+// search in flight.
+func (s *Scheduler) watchLoad(ctx context.Context) {
+	for {
+		idle := !s.load.CPUSnapshot().Overloaded && s.load.InFlightSearches() == 0
+		s.loadMu.Lock()
+		switch {
+		case !idle:
+			s.idleSince = time.Time{}
+		case s.idleSince.IsZero():
+			s.idleSince = time.Now()
+		}
+		s.loadMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.loadPollInterval()):
+		}
+	}
+}
+
+func (s *Scheduler) idleFor(now time.Time) time.Duration {
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+	if s.idleSince.IsZero() {
+		return 0
+	}
+	return now.Sub(s.idleSince)
+}
+
+func (s *Scheduler) loadPollInterval() time.Duration {
+	return max(min(time.Second, s.config().Scheduler.IdleWindow/4), 10*time.Millisecond)
+}
+
+// waitForIdle holds a heavy task back until the host has been idle for
+// scheduler.idle_window, or until scheduler.max_deferral has passed.
+func (s *Scheduler) waitForIdle(ctx context.Context, task string) error {
+	if s.load == nil {
+		return nil
+	}
+	cfg := s.config().Scheduler
+	start := time.Now()
+	deadline := start.Add(cfg.MaxDeferral)
+	deferred := false
+
+	for {
+		now := time.Now()
+		if s.idleFor(now) >= cfg.IdleWindow {
+			if deferred {
+				s.log.Info("host idle, running deferred task", "task", task, "deferred", now.Sub(start).Round(time.Second))
+			}
+			return nil
+		}
+		if !now.Before(deadline) {
+			s.log.Warn("max deferral reached, running task on a busy host", "task", task, "max_deferral", cfg.MaxDeferral)
+			return nil
+		}
+		if !deferred {
+			deferred = true
+			cpu := s.load.CPUSnapshot()
+			s.log.Info("host busy, deferring task",
+				"task", task,
+				"cpu_overloaded", cpu.Overloaded,
+				"searches_in_flight", s.load.InFlightSearches(),
+				"idle_window", cfg.IdleWindow,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(s.loadPollInterval(), deadline.Sub(now))):
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/internal/resourceguard"
+)
+
+type fakeLoad struct {
+	overloaded atomic.Bool
+	searches   atomic.Int64
+}
+
+func (f *fakeLoad) CPUSnapshot() resourceguard.CPUSnapshot {
+	return resourceguard.CPUSnapshot{Overloaded: f.overloaded.Load()}
+}
+
+func (f *fakeLoad) InFlightSearches() int { return int(f.searches.Load()) }
+
+func newLoadTestScheduler(load Load, idle, maxDeferral time.Duration) *Scheduler {
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{IdleWindow: idle, MaxDeferral: maxDeferral}}
+	return New(cfg, nil, nil, load, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestWaitForIdle_DefersWhileBusy(t *testing.T) {
+	load := &fakeLoad{}
+	load.overloaded.Store(true)
+	load.searches.Store(1)
+	s := newLoadTestScheduler(load, 100*time.Millisecond, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watchLoad(ctx)
+
+	done := make(chan time.Time, 1)
+	go func() {
+		if err := s.waitForIdle(ctx, "embed_full_refresh"); err != nil {
+			t.Errorf("waitForIdle failed: %v", err)
+		}
+		done <- time.Now()
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	load.overloaded.Store(false)
+	time.Sleep(200 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatalf("task ran while a search was in flight")
+	default:
+	}
+
+	released := time.Now()
+	load.searches.Store(0)
+	select {
+	case ranAt := <-done:
+		if ranAt.Sub(released) < 100*time.Millisecond {
+			t.Fatalf("task ran before a full idle window, after %v", ranAt.Sub(released))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("task not released after the host became idle")
+	}
+}
+
+func TestWaitForIdle_RunsAfterMaxDeferral(t *testing.T) {
+	load := &fakeLoad{}
+	load.overloaded.Store(true)
+	s := newLoadTestScheduler(load, time.Hour, 150*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watchLoad(ctx)
+
+	start := time.Now()
+	if err := s.waitForIdle(ctx, "index_refresh"); err != nil {
+		t.Fatalf("waitForIdle failed: %v", err)
+	}
+	if waited := time.Since(start); waited < 150*time.Millisecond || waited > 2*time.Second {
+		t.Fatalf("expected release at max_deferral, waited %v", waited)
+	}
+}
+
+func TestReindexCollections_DoesNotWaitForIdle(t *testing.T) {
+	load := &fakeLoad{}
+	load.searches.Store(1)
+	exec := &fakeUpdateExec{updates: make(chan struct{}, 1)}
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{IdleWindow: time.Hour, MaxDeferral: time.Hour}}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	s := New(cfg, exec, c, load, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.ReindexCollections(ctx, []string{"notes"}); err != nil {
+		t.Fatalf("ReindexCollections on a busy host: %v", err)
+	}
+	if len(exec.updates) != 1 {
+		t.Fatalf("expected qmd update to run without waiting for an idle host")
+	}
+}
@@ -2,74 +2,188 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
 	"qmdsr/cache"
 	"qmdsr/config"
 	"qmdsr/executor"
+	"qmdsr/metrics"
 )
 
 type Scheduler struct {
+	cfgMu               sync.RWMutex
 	cfg                 *config.Config
+	rearm               chan struct{}
 	exec                executor.Executor
 	cache               *cache.Cache
+	load                Load
 	log                 *slog.Logger
 	cleanupDeepNegative func() int
 
-	mu        sync.Mutex
-	running   map[string]bool
-	cancel    context.CancelFunc
-	lastEmbed time.Time
-	lastFull  time.Time
+	mu sync.Mutex
+	// running maps a task in progress to its job id ("" when scheduled).
+	running  map[string]string
+	stopped  bool
+	cancel   context.CancelFunc
+	updateMu sync.Mutex
+	// wg tracks the task loops and background jobs Stop waits for.
+	wg sync.WaitGroup
+	// jobsCtx is the parent of every job context; Stop cancels it.
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
+
+	jobsMu   sync.Mutex
+	jobs     map[string]*job
+	jobOrder []string
+
+	stateMu   sync.Mutex
+	tasks     map[string]TaskState
+	saveMu    sync.Mutex
+	startedAt time.Time
+
+	historyMu sync.Mutex
+	history   map[string][]TaskRun
+
+	loadMu    sync.Mutex
+	idleSince time.Time
+
+	embedMu      sync.Mutex
+	embedSince   time.Time
+	lastEmbedded map[string]time.Time
+
+	refreshMu sync.Mutex
+	onRefresh []func(context.Context, []string)
 }
 
-func New(cfg *config.Config, exec executor.Executor, c *cache.Cache, cleanupDeepNegative func() int, logger *slog.Logger) *Scheduler {
+// embedCheckInterval is how often collections are checked against their own
+// embed intervals.
+const embedCheckInterval = time.Minute
+
+// EmbedState is the embedding schedule of one collection with embed: true.
+type EmbedState struct {
+	Collection   string
+	Interval     time.Duration
+	LastEmbedded time.Time
+}
+
+// New creates a scheduler. load may be nil, in which case heavy tasks never
+// wait for an idle host.
+func New(cfg *config.Config, exec executor.Executor, c *cache.Cache, load Load, cleanupDeepNegative func() int, logger *slog.Logger) *Scheduler {
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
 	return &Scheduler{
 		cfg:                 cfg,
 		exec:                exec,
 		cache:               c,
+		load:                load,
 		log:                 logger,
 		cleanupDeepNegative: cleanupDeepNegative,
-		running:             make(map[string]bool),
+		running:             make(map[string]string),
+		jobsCtx:             jobsCtx,
+		stopJobs:            stopJobs,
+		jobs:                make(map[string]*job),
+		tasks:               make(map[string]TaskState),
+		history:             make(map[string][]TaskRun),
+		rearm:               make(chan struct{}),
+		embedSince:          time.Now(),
+		lastEmbedded:        make(map[string]time.Time),
+	}
+}
+
+func (s *Scheduler) config() *config.Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// ApplyConfig switches to a reloaded config. Changed intervals re-arm the
+// running tickers; tasks already in progress are not interrupted.
+func (s *Scheduler) ApplyConfig(cfg *config.Config) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	changed := cfg.Scheduler != s.cfg.Scheduler
+	s.cfg = cfg
+	if changed {
+		close(s.rearm)
+		s.rearm = make(chan struct{})
 	}
 }
 
 func (s *Scheduler) Start(ctx context.Context) {
 	ctx, s.cancel = context.WithCancel(ctx)
+	cfg := s.config()
+	s.stateMu.Lock()
+	s.startedAt = time.Now()
+	s.stateMu.Unlock()
+
+	if s.load != nil {
+		s.wg.Go(func() { s.watchLoad(ctx) })
+	}
+	s.loadState()
 
-	go s.loop(ctx, "index_refresh", s.cfg.Scheduler.IndexRefresh, s.taskReindex)
-	if !s.cfg.Runtime.LowResourceMode || s.cfg.Runtime.AllowCPUVSearch || s.cfg.Runtime.AllowCPUDeepQuery {
-		go s.loop(ctx, "embed_refresh", s.cfg.Scheduler.EmbedRefresh, s.taskEmbed)
-		go s.loop(ctx, "embed_full_refresh", s.cfg.Scheduler.EmbedFullRefresh, s.taskEmbedFull)
+	s.wg.Go(func() { s.loop(ctx, "index_refresh", true, indexTrigger, s.taskReindex) })
+	if s.embedEnabled(cfg) {
+		s.wg.Go(func() {
+			s.loop(ctx, "embed_refresh", false, func(c config.SchedulerConfig) trigger {
+				return intervalTrigger(min(c.EmbedRefresh, embedCheckInterval))
+			}, s.taskEmbed)
+		})
+		s.wg.Go(func() { s.loop(ctx, "embed_full_refresh", true, embedFullTrigger, s.taskEmbedFull) })
 	} else {
 		s.log.Info("low_resource_mode enabled, scheduled embed tasks disabled")
 	}
-	go s.loop(ctx, "cache_cleanup", s.cfg.Scheduler.CacheCleanup, s.taskCacheCleanup)
+	s.wg.Go(func() {
+		s.loop(ctx, "cache_cleanup", false, func(c config.SchedulerConfig) trigger {
+			return intervalTrigger(c.CacheCleanup)
+		}, s.taskCacheCleanup)
+	})
 
 	s.log.Info("scheduler started",
-		"index_refresh", s.cfg.Scheduler.IndexRefresh,
-		"embed_refresh", s.cfg.Scheduler.EmbedRefresh,
-		"embed_full_refresh", s.cfg.Scheduler.EmbedFullRefresh,
-		"cache_cleanup", s.cfg.Scheduler.CacheCleanup,
+		"index_refresh", indexTrigger(cfg.Scheduler),
+		"embed_refresh", cfg.Scheduler.EmbedRefresh,
+		"embed_full_refresh", embedFullTrigger(cfg.Scheduler),
+		"cache_cleanup", cfg.Scheduler.CacheCleanup,
+		"idle_window", cfg.Scheduler.IdleWindow,
+		"max_deferral", cfg.Scheduler.MaxDeferral,
+		"quiet_hours", cfg.Scheduler.QuietHours,
+		"jitter", cfg.Scheduler.Jitter,
+		"catch_up", cfg.Scheduler.CatchUp,
 	)
 }
 
+// Stop cancels scheduled tasks and running jobs and waits for them to
+// return, so no task writes scheduler state after Stop.
 func (s *Scheduler) Stop() {
 	if s.cancel != nil {
 		s.cancel()
 	}
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.stopJobs()
+	s.wg.Wait()
 }
 
 func (s *Scheduler) TriggerReindex(ctx context.Context) error {
-	return s.runTask("index_refresh", func() error {
-		return s.taskReindex(ctx)
+	return s.runTask(ctx, "index_refresh", s.reindexAll)
+}
+
+// ReindexCollections runs qmd update for filesystem changes reported by the
+// watcher and invalidates cached results of the changed collections only.
+// Unlike scheduled updates it does not wait for an idle host: the update is
+// incremental and searches should see the changes promptly.
+func (s *Scheduler) ReindexCollections(ctx context.Context, names []string) error {
+	return s.runTask(ctx, "watch_reindex", func(ctx context.Context) error {
+		return s.taskReindexCollections(ctx, names)
 	})
 }
 
 func (s *Scheduler) TriggerEmbed(ctx context.Context, force bool) error {
-	if s.cfg.Runtime.LowResourceMode && !(s.cfg.Runtime.AllowCPUVSearch || s.cfg.Runtime.AllowCPUDeepQuery) {
+	if !s.embedEnabled(s.config()) {
 		s.log.Info("embed trigger skipped in low_resource_mode", "force", force)
 		return nil
 	}
@@ -77,70 +191,185 @@ func (s *Scheduler) TriggerEmbed(ctx context.Context, force bool) error {
 	if force {
 		name = "embed_full_refresh"
 	}
-	return s.runTask(name, func() error {
-		if force {
-			return s.taskEmbedFull(ctx)
-		}
-		return s.taskEmbed(ctx)
+	return s.runTask(ctx, name, func(ctx context.Context) error {
+		return s.embedCollections(ctx, s.embedCollectionNames(), force)
 	})
 }
 
-func (s *Scheduler) loop(ctx context.Context, name string, interval time.Duration, task func(context.Context) error) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// embedEnabled reports whether embed tasks run at all; low_resource_mode
+// disables them unless CPU vector search or deep query is allowed.
+func (s *Scheduler) embedEnabled(cfg *config.Config) bool {
+	return !cfg.Runtime.LowResourceMode || cfg.Runtime.AllowCPUVSearch || cfg.Runtime.AllowCPUDeepQuery
+}
+
+// EmbedStatus reports the embed interval and last embed time of every
+// collection with embed: true.
+func (s *Scheduler) EmbedStatus() []EmbedState {
+	cfg := s.config()
+	s.embedMu.Lock()
+	defer s.embedMu.Unlock()
+
+	var out []EmbedState
+	for _, col := range cfg.Collections {
+		if !col.Embed {
+			continue
+		}
+		out = append(out, EmbedState{
+			Collection:   col.Name,
+			Interval:     embedInterval(cfg, col),
+			LastEmbedded: s.lastEmbedded[col.Name],
+		})
+	}
+	return out
+}
+
+// loop runs task on its trigger. Persistent tasks keep their last and next
+// run in the state directory so restarts neither reset nor skip them.
+func (s *Scheduler) loop(ctx context.Context, name string, persistent bool, plan func(config.SchedulerConfig) trigger, task func(context.Context) error) {
+	s.cfgMu.RLock()
+	current, rearm := plan(s.cfg.Scheduler), s.rearm
+	s.cfgMu.RUnlock()
+
+	next := time.Now().Add(current.every)
+	if persistent {
+		next = s.firstRun(name, current, time.Now())
+	}
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			if err := s.runTask(name, func() error { return task(ctx) }); err != nil {
+		case <-rearm:
+			s.cfgMu.RLock()
+			trig := plan(s.cfg.Scheduler)
+			rearm = s.rearm
+			s.cfgMu.RUnlock()
+			if trig.same(current) {
+				continue
+			}
+			current = trig
+			if persistent {
+				next = s.rescheduleRun(name, current)
+			} else {
+				next = time.Now().Add(current.every)
+			}
+			timer.Reset(time.Until(next))
+			s.log.Info("scheduled task re-armed", "task", name, "schedule", current, "next_run", next)
+		case <-timer.C:
+			started := time.Now()
+			err := s.runTask(ctx, name, task)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case errors.Is(err, ErrTaskRunning):
+				s.log.Debug("task already running, skipping", "task", name)
+			case err != nil:
 				s.log.Error("scheduled task failed", "task", name, "err", err)
 			}
+			switch {
+			case persistent && err != nil && !errors.Is(err, ErrTaskRunning):
+				next = s.retryRun(name, current)
+			case persistent:
+				next = s.finishRun(name, current, started)
+			default:
+				next = time.Now().Add(current.every)
+			}
+			timer.Reset(time.Until(next))
 		}
 	}
 }
 
-func (s *Scheduler) runTask(name string, fn func() error) error {
+func (s *Scheduler) runTask(ctx context.Context, name string, fn func(context.Context) error) error {
+	if err := s.acquire(name, ""); err != nil {
+		return err
+	}
+	defer s.release(name)
+	return s.execTask(ctx, name, fn)
+}
+
+// acquire marks name as running. A job also joins the goroutines Stop
+// waits for, under the same lock that Stop uses to refuse new tasks.
+func (s *Scheduler) acquire(name, jobID string) error {
 	s.mu.Lock()
-	if s.running[name] {
-		s.mu.Unlock()
-		s.log.Debug("task already running, skipping", "task", name)
-		return nil
+	defer s.mu.Unlock()
+	if s.stopped {
+		return fmt.Errorf("%w: %s", ErrStopped, name)
+	}
+	if active, ok := s.running[name]; ok {
+		if active != "" {
+			return fmt.Errorf("%w: %s (job %s)", ErrTaskRunning, name, active)
+		}
+		return fmt.Errorf("%w: %s", ErrTaskRunning, name)
 	}
-	s.running[name] = true
+	s.running[name] = jobID
+	if jobID != "" {
+		s.wg.Add(1)
+	}
+	return nil
+}
+
+func (s *Scheduler) release(name string) {
+	s.mu.Lock()
+	delete(s.running, name)
 	s.mu.Unlock()
+}
 
-	defer func() {
-		s.mu.Lock()
-		delete(s.running, name)
-		s.mu.Unlock()
-	}()
+func (s *Scheduler) execTask(ctx context.Context, name string, fn func(context.Context) error) error {
+	run := &taskRun{info: TaskRun{Task: name, Start: time.Now(), Attempts: 1}}
+	if j := jobFromContext(ctx); j != nil {
+		run.info.JobID = j.snapshot().ID
+	}
+	ctx = withRun(ctx, run)
 
 	s.log.Info("running scheduled task", "task", name)
-	start := time.Now()
-	err := fn()
+	start := run.info.Start
+	err := fn(ctx)
 	elapsed := time.Since(start)
 
 	if err != nil {
 		s.log.Error("task failed", "task", name, "elapsed", elapsed, "err", err)
-		return s.retry(name, fn, 3)
+		if ctx.Err() == nil {
+			err = s.retry(ctx, name, fn, err, 3)
+		}
+		metrics.SchedulerTaskDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		if err != nil {
+			metrics.SchedulerTaskFailures.WithLabelValues(name).Inc()
+		}
+		s.recordRun(run, time.Since(start), err)
+		return err
 	}
 
+	metrics.SchedulerTaskDuration.WithLabelValues(name).Observe(elapsed.Seconds())
 	s.log.Info("task completed", "task", name, "elapsed", elapsed)
+	s.recordRun(run, elapsed, nil)
 	return nil
 }
 
-func (s *Scheduler) retry(name string, fn func() error, maxRetries int) error {
-	var lastErr error
+// retry stops early when ctx is cancelled, e.g. by CancelJob or shutdown.
+func (s *Scheduler) retry(ctx context.Context, name string, fn func(context.Context) error, lastErr error, maxRetries int) error {
 	for i := 1; i <= maxRetries; i++ {
 		delay := time.Duration(i*i) * time.Second
 		s.log.Info("retrying task", "task", name, "attempt", i, "delay", delay)
-		time.Sleep(delay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w while waiting to retry: %v", ctx.Err(), lastErr)
+		case <-time.After(delay):
+		}
+		if j := jobFromContext(ctx); j != nil {
+			j.addRetry()
+		}
+		if run := runFromContext(ctx); run != nil {
+			run.addAttempt()
+		}
 
-		if err := fn(); err != nil {
+		if err := fn(ctx); err != nil {
 			lastErr = err
 			s.log.Warn("retry failed", "task", name, "attempt", i, "err", err)
+			if ctx.Err() != nil {
+				return err
+			}
 			continue
 		}
 		s.log.Info("retry succeeded", "task", name, "attempt", i)
@@ -149,24 +378,185 @@ func (s *Scheduler) retry(name string, fn func() error, maxRetries int) error {
 	return lastErr
 }
 
+// OnIndexRefresh registers fn to run after every successful index refresh
+// with the names of the refreshed collections.
+func (s *Scheduler) OnIndexRefresh(fn func(ctx context.Context, names []string)) {
+	s.refreshMu.Lock()
+	s.onRefresh = append(s.onRefresh, fn)
+	s.refreshMu.Unlock()
+}
+
+func (s *Scheduler) notifyIndexRefresh(ctx context.Context, names []string) {
+	s.refreshMu.Lock()
+	listeners := slices.Clone(s.onRefresh)
+	s.refreshMu.Unlock()
+	for _, fn := range listeners {
+		fn(ctx, names)
+	}
+}
+
+// taskReindex honours quiet hours but does not wait for an idle host; only
+// embeds are heavy enough to defer.
 func (s *Scheduler) taskReindex(ctx context.Context) error {
+	if err := s.waitQuietHours(ctx, "index_refresh"); err != nil {
+		return err
+	}
+	return s.reindexAll(ctx)
+}
+
+func (s *Scheduler) reindexAll(ctx context.Context) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	cols := s.config().Collections
+	versions := s.collectionVersions(cols)
 	if err := s.exec.Update(ctx); err != nil {
 		return err
 	}
-	version := time.Now().Format("20060102150405")
-	s.cache.SetVersion(version)
-	s.log.Info("index refreshed, cache version updated", "version", version)
+	changed, removed := s.cache.SetCollectionVersions(versions)
+	recordCacheVersions(ctx, versions, changed)
+	s.log.Info("index refreshed, cache versions updated", "changed_collections", changed, "cache_invalidated", removed)
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.Name
+	}
+	s.notifyIndexRefresh(ctx, names)
 	return nil
 }
 
+// SyncCacheVersions fingerprints every collection and drops cached entries
+// of the collections that changed since their versions were recorded, e.g.
+// while the service was down. qmd has not indexed those changes yet, so they
+// keep a provisional version that the next index refresh replaces.
+func (s *Scheduler) SyncCacheVersions() {
+	versions := s.collectionVersions(s.config().Collections)
+	changed, removed := s.cache.SetCollectionVersions(versions)
+	if len(changed) == 0 {
+		return
+	}
+	s.cache.InvalidateCollections(changed)
+	s.log.Info("cache versions synced with collections", "changed_collections", changed, "cache_invalidated", removed)
+}
+
+func (s *Scheduler) taskReindexCollections(ctx context.Context, names []string) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	if err := s.exec.Update(ctx); err != nil {
+		return err
+	}
+	var cols []config.CollectionCfg
+	for _, col := range s.config().Collections {
+		if slices.Contains(names, col.Name) {
+			cols = append(cols, col)
+		}
+	}
+	versions := s.collectionVersions(cols)
+	changed, removed := s.cache.SetCollectionVersions(versions)
+	recordCacheVersions(ctx, versions, changed)
+	s.log.Info("index refreshed after file changes", "collections", names, "changed_collections", changed, "cache_invalidated", removed)
+	s.notifyIndexRefresh(ctx, names)
+	return nil
+}
+
+// taskEmbed embeds the collections whose own embed interval has elapsed.
 func (s *Scheduler) taskEmbed(ctx context.Context) error {
-	s.lastEmbed = time.Now()
-	return s.exec.Embed(ctx, false)
+	if len(s.dueEmbedCollections(time.Now())) == 0 {
+		markNoop(ctx)
+		return nil
+	}
+	if err := s.waitToStart(ctx, "embed_refresh"); err != nil {
+		return err
+	}
+	return s.embedCollections(ctx, s.dueEmbedCollections(time.Now()), false)
 }
 
 func (s *Scheduler) taskEmbedFull(ctx context.Context) error {
-	s.lastFull = time.Now()
-	return s.exec.Embed(ctx, true)
+	if err := s.waitToStart(ctx, "embed_full_refresh"); err != nil {
+		return err
+	}
+	return s.embedCollections(ctx, s.embedCollectionNames(), true)
+}
+
+// embedCollections runs qmd embed for each named collection. A qmd without
+// per-collection embed gets a single global embed instead.
+func (s *Scheduler) embedCollections(ctx context.Context, names []string, force bool) error {
+	if len(names) == 0 {
+		s.log.Debug("no collections due for embedding")
+		markNoop(ctx)
+		return nil
+	}
+	if !s.exec.HasCapability("collection_embed") {
+		s.log.Info("qmd lacks per-collection embed, running global embed", "collections", names)
+		if err := s.exec.Embed(ctx, force); err != nil {
+			return err
+		}
+		s.markEmbedded(s.embedCollectionNames(), time.Now())
+		return nil
+	}
+
+	var errs []error
+	for _, name := range names {
+		if err := s.exec.EmbedCollection(ctx, name, force); err != nil {
+			errs = append(errs, fmt.Errorf("embed %s: %w", name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		s.markEmbedded([]string{name}, time.Now())
+		s.log.Info("collection embedded", "collection", name, "force", force)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) embedCollectionNames() []string {
+	var names []string
+	for _, col := range s.config().Collections {
+		if col.Embed {
+			names = append(names, col.Name)
+		}
+	}
+	return names
+}
+
+// dueEmbedCollections returns the collections whose embed interval has
+// elapsed. Collections not embedded since startup count from startup.
+func (s *Scheduler) dueEmbedCollections(now time.Time) []string {
+	cfg := s.config()
+	s.embedMu.Lock()
+	defer s.embedMu.Unlock()
+
+	var due []string
+	for _, col := range cfg.Collections {
+		if !col.Embed {
+			continue
+		}
+		last, ok := s.lastEmbedded[col.Name]
+		if !ok {
+			last = s.embedSince
+		}
+		if now.Sub(last) >= embedInterval(cfg, col) {
+			due = append(due, col.Name)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) markEmbedded(names []string, at time.Time) {
+	s.embedMu.Lock()
+	for _, name := range names {
+		s.lastEmbedded[name] = at
+	}
+	s.embedMu.Unlock()
+	s.saveState()
+}
+
+func embedInterval(cfg *config.Config, col config.CollectionCfg) time.Duration {
+	if col.EmbedInterval > 0 {
+		return col.EmbedInterval
+	}
+	return cfg.Scheduler.EmbedRefresh
 }
 
 func (s *Scheduler) taskCacheCleanup(_ context.Context) error {
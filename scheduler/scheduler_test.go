@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+)
+
+func TestApplyConfig_RearmsTickers(t *testing.T) {
+	cfg := &config.Config{
+		Scheduler: config.SchedulerConfig{
+			IndexRefresh:     time.Hour,
+			EmbedRefresh:     time.Hour,
+			EmbedFullRefresh: time.Hour,
+			CacheCleanup:     time.Hour,
+		},
+		Runtime: config.RuntimeConfig{LowResourceMode: true},
+	}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	cleaned := make(chan struct{}, 1)
+	s := New(cfg, nil, c, nil, func() int {
+		select {
+		case cleaned <- struct{}{}:
+		default:
+		}
+		return 0
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	defer s.Stop()
+
+	next := *cfg
+	next.Scheduler.CacheCleanup = 20 * time.Millisecond
+	s.ApplyConfig(&next)
+
+	select {
+	case <-cleaned:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("cache_cleanup did not run on the re-armed interval")
+	}
+}
+
+func TestOnIndexRefresh_ReceivesRefreshedCollections(t *testing.T) {
+	cfg := &config.Config{Collections: []config.CollectionCfg{
+		{Name: "notes", Path: t.TempDir()},
+		{Name: "work", Path: t.TempDir()},
+	}}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true})
+	s := New(cfg, &fakeUpdateExec{updates: make(chan struct{}, 2)}, c, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var got [][]string
+	s.OnIndexRefresh(func(_ context.Context, names []string) { got = append(got, names) })
+
+	if err := s.reindexAll(context.Background()); err != nil {
+		t.Fatalf("reindexAll: %v", err)
+	}
+	if err := s.taskReindexCollections(context.Background(), []string{"work"}); err != nil {
+		t.Fatalf("taskReindexCollections: %v", err)
+	}
+	want := [][]string{{"notes", "work"}, {"work"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("refresh listeners got %v, want %v", got, want)
+	}
+}
+
+func TestSyncCacheVersions_DropsEntriesOfCollectionsChangedWhileDown(t *testing.T) {
+	notes, work := t.TempDir(), t.TempDir()
+	for _, dir := range []string{notes, work} {
+		if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("v1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Collections: []config.CollectionCfg{
+		{Name: "notes", Path: notes, Mask: "**/*.md"},
+		{Name: "work", Path: work, Mask: "**/*.md"},
+	}}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10, VersionAware: true})
+	s := New(cfg, &fakeUpdateExec{updates: make(chan struct{}, 2)}, c, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// State as restored from a snapshot taken before the restart.
+	c.SetCollectionVersions(s.collectionVersions(cfg.Collections))
+	c.Put("notes", cache.Entry{Collections: []string{"notes"}})
+	c.Put("work", cache.Entry{Collections: []string{"work"}})
+
+	if err := os.WriteFile(filepath.Join(notes, "a.md"), []byte("edited while down"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s.SyncCacheVersions()
+	if _, ok := c.Get("notes"); ok {
+		t.Errorf("entry of a collection edited while down survived the sync")
+	}
+	if _, ok := c.Get("work"); !ok {
+		t.Errorf("entry of an unchanged collection was dropped")
+	}
+
+	// Answers cached before qmd indexed the edit go with the next refresh.
+	c.Put("notes", cache.Entry{Collections: []string{"notes"}})
+	if err := s.reindexAll(context.Background()); err != nil {
+		t.Fatalf("reindexAll: %v", err)
+	}
+	if _, ok := c.Get("notes"); ok {
+		t.Errorf("entry cached before the index refresh survived it")
+	}
+}
@@ -0,0 +1,287 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"path/filepath"
+	"time"
+
+	"qmdsr/config"
+	"qmdsr/internal/schedule"
+	"qmdsr/internal/snapshot"
+)
+
+const (
+	stateFile    = "scheduler_state.json"
+	stateKind    = "scheduler_state"
+	stateVersion = 1
+
+	// failedRunRetry bounds how long a persistent task waits to run again
+	// after a failed run.
+	failedRunRetry = 15 * time.Minute
+)
+
+// trigger is either a fixed interval or a cron expression.
+type trigger struct {
+	every time.Duration
+	expr  string
+	cron  *schedule.Cron
+}
+
+func intervalTrigger(every time.Duration) trigger {
+	return trigger{every: every}
+}
+
+// cronTrigger falls back to the interval when expr is empty. Config
+// validation rejects expressions that do not parse.
+func cronTrigger(expr string, every time.Duration) trigger {
+	if expr == "" {
+		return intervalTrigger(every)
+	}
+	cron, err := schedule.ParseCron(expr)
+	if err != nil {
+		return intervalTrigger(every)
+	}
+	return trigger{every: every, expr: expr, cron: cron}
+}
+
+func (t trigger) next(after time.Time) time.Time {
+	if t.cron != nil {
+		return t.cron.Next(after)
+	}
+	return after.Add(t.every)
+}
+
+func (t trigger) same(o trigger) bool {
+	if t.cron != nil || o.cron != nil {
+		return t.expr == o.expr
+	}
+	return t.every == o.every
+}
+
+func (t trigger) String() string {
+	if t.cron != nil {
+		return t.expr
+	}
+	return t.every.String()
+}
+
+// TaskState is the persisted schedule of one task. LastRun and NextRun are
+// kept for tasks that survive restarts, LastSuccess for every task.
+type TaskState struct {
+	LastRun     time.Time `json:"last_run"`
+	NextRun     time.Time `json:"next_run"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+}
+
+type persistedState struct {
+	Tasks        map[string]TaskState `json:"tasks"`
+	LastEmbedded map[string]time.Time `json:"last_embedded"`
+}
+
+// TaskStates returns the persisted state of every task that has run or been
+// scheduled.
+func (s *Scheduler) TaskStates() map[string]TaskState {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	out := make(map[string]TaskState, len(s.tasks))
+	for name, st := range s.tasks {
+		out[name] = st
+	}
+	return out
+}
+
+func (s *Scheduler) statePath() string {
+	dir := s.config().Server.StateDir
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, stateFile)
+}
+
+// loadState restores task run times and per-collection embed times saved by
+// a previous process.
+func (s *Scheduler) loadState() {
+	path := s.statePath()
+	if path == "" {
+		return
+	}
+	var st persistedState
+	_, err := snapshot.Read(path, stateKind, stateVersion, &st)
+	switch {
+	case err == nil:
+	case errors.Is(err, fs.ErrNotExist):
+		return
+	case errors.Is(err, snapshot.ErrCorrupt):
+		s.log.Warn("discarding corrupt scheduler state", "path", path, "err", err)
+		if qErr := snapshot.Quarantine(path); qErr != nil {
+			s.log.Warn("failed to quarantine scheduler state", "path", path, "err", qErr)
+		}
+		return
+	default:
+		s.log.Warn("failed to restore scheduler state", "path", path, "err", err)
+		return
+	}
+
+	s.stateMu.Lock()
+	for name, ts := range st.Tasks {
+		s.tasks[name] = ts
+	}
+	s.stateMu.Unlock()
+	s.embedMu.Lock()
+	for name, at := range st.LastEmbedded {
+		s.lastEmbedded[name] = at
+	}
+	s.embedMu.Unlock()
+	s.log.Info("scheduler state restored", "tasks", len(st.Tasks), "collections", len(st.LastEmbedded), "path", path)
+}
+
+func (s *Scheduler) saveState() {
+	path := s.statePath()
+	if path == "" {
+		return
+	}
+	st := persistedState{Tasks: s.TaskStates()}
+	s.embedMu.Lock()
+	st.LastEmbedded = make(map[string]time.Time, len(s.lastEmbedded))
+	for name, at := range s.lastEmbedded {
+		st.LastEmbedded[name] = at
+	}
+	s.embedMu.Unlock()
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if err := snapshot.Write(path, stateKind, stateVersion, st); err != nil {
+		s.log.Warn("failed to save scheduler state", "path", path, "err", err)
+	}
+}
+
+// firstRun picks the first run of a persistent task after startup. A run
+// missed while qmdsr was down starts right away with catch_up: run and is
+// dropped with catch_up: skip.
+func (s *Scheduler) firstRun(name string, trig trigger, now time.Time) time.Time {
+	s.stateMu.Lock()
+	st, known := s.tasks[name]
+	s.stateMu.Unlock()
+
+	due := st.NextRun
+	if !st.LastRun.IsZero() {
+		// Recomputed so a changed interval or cron applies to the old run.
+		due = trig.next(st.LastRun)
+	}
+	if !known || due.IsZero() {
+		return s.setNextRun(name, trig.next(now))
+	}
+	if due.After(now) {
+		return s.setNextRun(name, due)
+	}
+	if s.config().Scheduler.CatchUp == "skip" {
+		s.log.Info("skipping missed scheduled run", "task", name, "missed", due, "last_run", st.LastRun)
+		return s.setNextRun(name, trig.next(now))
+	}
+	s.log.Info("catching up missed scheduled run", "task", name, "missed", due, "last_run", st.LastRun)
+	return s.setNextRun(name, now)
+}
+
+// finishRun records a successful or skipped run and schedules the next one.
+func (s *Scheduler) finishRun(name string, trig trigger, started time.Time) time.Time {
+	s.stateMu.Lock()
+	st := s.tasks[name]
+	st.LastRun = started
+	s.tasks[name] = st
+	s.stateMu.Unlock()
+	next := trig.next(started)
+	if now := time.Now(); !next.After(now) {
+		next = trig.next(now)
+	}
+	return s.setNextRun(name, next)
+}
+
+// retryRun schedules a failed run again within failedRunRetry. LastRun is
+// left alone, so a restart before the retry still catches the run up.
+func (s *Scheduler) retryRun(name string, trig trigger) time.Time {
+	now := time.Now()
+	next := trig.next(now)
+	if retry := now.Add(failedRunRetry); retry.Before(next) {
+		next = retry
+	}
+	return s.setNextRun(name, next)
+}
+
+// rescheduleRun applies a changed interval or cron to the last run.
+func (s *Scheduler) rescheduleRun(name string, trig trigger) time.Time {
+	s.stateMu.Lock()
+	last := s.tasks[name].LastRun
+	s.stateMu.Unlock()
+	now := time.Now()
+	if last.IsZero() {
+		last = now
+	}
+	next := trig.next(last)
+	if next.Before(now) {
+		next = now
+	}
+	return s.setNextRun(name, next)
+}
+
+func (s *Scheduler) setNextRun(name string, next time.Time) time.Time {
+	if jitter := s.config().Scheduler.Jitter; jitter > 0 {
+		next = next.Add(rand.N(jitter))
+	}
+	s.stateMu.Lock()
+	st := s.tasks[name]
+	st.NextRun = next
+	s.tasks[name] = st
+	s.stateMu.Unlock()
+	s.saveState()
+	return next
+}
+
+// waitToStart holds a scheduled embed back until quiet hours are over and
+// the host is idle.
+func (s *Scheduler) waitToStart(ctx context.Context, task string) error {
+	for {
+		if err := s.waitQuietHours(ctx, task); err != nil {
+			return err
+		}
+		if err := s.waitForIdle(ctx, task); err != nil {
+			return err
+		}
+		if _, quiet := s.quietUntil(time.Now()); !quiet {
+			return nil
+		}
+	}
+}
+
+func (s *Scheduler) waitQuietHours(ctx context.Context, task string) error {
+	for {
+		until, quiet := s.quietUntil(time.Now())
+		if !quiet {
+			return nil
+		}
+		s.log.Info("quiet hours, deferring task", "task", task, "until", until)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(until)):
+		}
+	}
+}
+
+func (s *Scheduler) quietUntil(now time.Time) (time.Time, bool) {
+	windows, err := schedule.ParseWindows(s.config().Scheduler.QuietHours)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return schedule.Until(windows, now)
+}
+
+func indexTrigger(c config.SchedulerConfig) trigger {
+	return cronTrigger(c.IndexRefreshCron, c.IndexRefresh)
+}
+
+func embedFullTrigger(c config.SchedulerConfig) trigger {
+	return cronTrigger(c.EmbedFullRefreshCron, c.EmbedFullRefresh)
+}
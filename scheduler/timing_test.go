@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/executor"
+)
+
+type fakeUpdateExec struct {
+	executor.Executor
+	updates chan struct{}
+}
+
+func (f *fakeUpdateExec) Update(context.Context) error {
+	f.updates <- struct{}{}
+	return nil
+}
+
+func newStateTestScheduler(t *testing.T, dir string, exec executor.Executor, sc config.SchedulerConfig) *Scheduler {
+	t.Helper()
+	cfg := &config.Config{
+		Server:      config.ServerConfig{StateDir: dir},
+		Collections: []config.CollectionCfg{{Name: "notes", Embed: true}},
+		Scheduler:   sc,
+		Runtime:     config.RuntimeConfig{LowResourceMode: true},
+	}
+	c := cache.New(&config.CacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 10})
+	return New(cfg, exec, c, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestSchedulerState_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	sc := config.SchedulerConfig{EmbedFullRefresh: 168 * time.Hour, CatchUp: "run"}
+	s := newStateTestScheduler(t, dir, nil, sc)
+
+	lastRun := time.Now().Add(-time.Hour).Truncate(time.Second)
+	next := s.finishRun("embed_full_refresh", embedFullTrigger(sc), lastRun)
+	s.markEmbedded([]string{"notes"}, lastRun)
+
+	restarted := newStateTestScheduler(t, dir, nil, sc)
+	restarted.loadState()
+	st := restarted.TaskStates()["embed_full_refresh"]
+	if !st.LastRun.Equal(lastRun) || !st.NextRun.Equal(next) {
+		t.Fatalf("task state not restored: %+v, want last %v next %v", st, lastRun, next)
+	}
+	if got := restarted.lastEmbedded["notes"]; !got.Equal(lastRun) {
+		t.Fatalf("last embed time not restored: %v", got)
+	}
+	// The weekly clock keeps running from the last run instead of restarting.
+	if got := restarted.firstRun("embed_full_refresh", embedFullTrigger(sc), time.Now()); !got.Equal(lastRun.Add(168 * time.Hour)) {
+		t.Fatalf("expected next run one week after the last run, got %v", got)
+	}
+}
+
+func TestFirstRun_CatchUpPolicy(t *testing.T) {
+	now := time.Now()
+	lastRun := now.Add(-200 * time.Hour)
+	for _, tc := range []struct {
+		policy string
+		want   time.Time
+	}{
+		{"run", now},
+		{"skip", now.Add(168 * time.Hour)},
+	} {
+		sc := config.SchedulerConfig{EmbedFullRefresh: 168 * time.Hour, CatchUp: tc.policy}
+		s := newStateTestScheduler(t, t.TempDir(), nil, sc)
+		s.tasks["embed_full_refresh"] = TaskState{LastRun: lastRun, NextRun: lastRun.Add(168 * time.Hour)}
+		if got := s.firstRun("embed_full_refresh", embedFullTrigger(sc), now); !got.Equal(tc.want) {
+			t.Fatalf("catch_up %s: next run %v, want %v", tc.policy, got, tc.want)
+		}
+	}
+}
+
+func TestRetryRun_KeepsFailedRunDue(t *testing.T) {
+	dir := t.TempDir()
+	sc := config.SchedulerConfig{EmbedFullRefresh: 168 * time.Hour, CatchUp: "run"}
+	s := newStateTestScheduler(t, dir, nil, sc)
+	lastRun := time.Now().Add(-200 * time.Hour).Truncate(time.Second)
+	s.tasks["embed_full_refresh"] = TaskState{LastRun: lastRun}
+
+	next := s.retryRun("embed_full_refresh", embedFullTrigger(sc))
+	if next.After(time.Now().Add(failedRunRetry)) {
+		t.Fatalf("failed weekly run retried at %v, want within %v", next, failedRunRetry)
+	}
+	if got := s.TaskStates()["embed_full_refresh"].LastRun; !got.Equal(lastRun) {
+		t.Fatalf("failed run advanced LastRun to %v", got)
+	}
+
+	restarted := newStateTestScheduler(t, dir, nil, sc)
+	restarted.loadState()
+	now := time.Now()
+	if got := restarted.firstRun("embed_full_refresh", embedFullTrigger(sc), now); !got.Equal(now) {
+		t.Fatalf("expected the failed run to be caught up after a restart, got %v", got)
+	}
+}
+
+func TestStart_CatchesUpMissedIndexRefresh(t *testing.T) {
+	dir := t.TempDir()
+	sc := config.SchedulerConfig{IndexRefresh: time.Hour, CacheCleanup: time.Hour, CatchUp: "run"}
+	seed := newStateTestScheduler(t, dir, nil, sc)
+	seed.finishRun("index_refresh", indexTrigger(sc), time.Now().Add(-3*time.Hour))
+
+	exec := &fakeUpdateExec{updates: make(chan struct{}, 1)}
+	s := newStateTestScheduler(t, dir, exec, sc)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	defer s.Stop()
+
+	select {
+	case <-exec.updates:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("missed index_refresh was not caught up at startup")
+	}
+}
+
+func TestWaitToStart_HonoursQuietHours(t *testing.T) {
+	sc := config.SchedulerConfig{QuietHours: "00:00-24:00"}
+	s := newStateTestScheduler(t, "", nil, sc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.waitToStart(ctx, "embed_full_refresh"); err == nil {
+		t.Fatalf("expected the task to be held back during quiet hours")
+	}
+
+	s.ApplyConfig(&config.Config{Scheduler: config.SchedulerConfig{QuietHours: ""}})
+	if err := s.waitToStart(context.Background(), "embed_full_refresh"); err != nil {
+		t.Fatalf("expected the task to start outside quiet hours, got %v", err)
+	}
+}
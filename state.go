@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"qmdsr/cache"
+	"qmdsr/config"
+	"qmdsr/internal/snapshot"
+	"qmdsr/orchestrator"
+)
+
+const (
+	cacheSnapshotFile   = "result_cache.json"
+	deepNegSnapshotFile = "deep_negative_cache.json"
+)
+
+// stateStore snapshots the result cache and the deep negative cache into the
+// state directory so a restart does not begin with cold caches.
+type stateStore struct {
+	cfg   *config.Config
+	cache *cache.Cache
+	orch  *orchestrator.Orchestrator
+	log   *slog.Logger
+}
+
+func (s *stateStore) restore() {
+	s.load("result_cache", filepath.Join(s.cfg.Server.StateDir, cacheSnapshotFile), s.cache.LoadSnapshot)
+	s.load("deep_negative_cache", filepath.Join(s.cfg.Server.StateDir, deepNegSnapshotFile), s.orch.LoadDeepNegativeCache)
+}
+
+func (s *stateStore) load(name, path string, fn func(string) (int, error)) {
+	loaded, err := fn(path)
+	switch {
+	case err == nil:
+		s.log.Info("state restored", "store", name, "entries", loaded, "path", path)
+	case errors.Is(err, fs.ErrNotExist):
+		s.log.Debug("no state snapshot", "store", name, "path", path)
+	case errors.Is(err, snapshot.ErrCorrupt):
+		s.log.Warn("discarding corrupt state snapshot", "store", name, "path", path, "err", err)
+		if qErr := snapshot.Quarantine(path); qErr != nil {
+			s.log.Warn("failed to quarantine snapshot", "path", path, "err", qErr)
+		}
+	default:
+		s.log.Warn("failed to restore state", "store", name, "path", path, "err", err)
+	}
+}
+
+func (s *stateStore) save() {
+	if n, err := s.cache.SaveSnapshot(filepath.Join(s.cfg.Server.StateDir, cacheSnapshotFile)); err != nil {
+		s.log.Warn("failed to save state", "store", "result_cache", "err", err)
+	} else {
+		s.log.Debug("state saved", "store", "result_cache", "entries", n)
+	}
+	if n, err := s.orch.SaveDeepNegativeCache(filepath.Join(s.cfg.Server.StateDir, deepNegSnapshotFile)); err != nil {
+		s.log.Warn("failed to save state", "store", "deep_negative_cache", "err", err)
+	} else {
+		s.log.Debug("state saved", "store", "deep_negative_cache", "entries", n)
+	}
+}
+
+func (s *stateStore) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Cache.PersistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.save()
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"qmdsr/config"
+	"qmdsr/internal/pathmatch"
+)
+
+// ChangeFunc receives the names of collections whose files changed since the
+// previous call.
+type ChangeFunc func(ctx context.Context, collections []string) error
+
+// Watcher observes collection directories with inotify and reports debounced
+// batches of changed collections, so edits are searchable without waiting for
+// the next scheduled index refresh.
+type Watcher struct {
+	cfg      *config.Config
+	onChange ChangeFunc
+	log      *slog.Logger
+
+	fsw    *fsnotify.Watcher
+	dirs   map[string]bool
+	reload chan *config.Config
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(cfg *config.Config, onChange ChangeFunc, logger *slog.Logger) *Watcher {
+	return &Watcher{
+		cfg:      cfg,
+		onChange: onChange,
+		log:      logger,
+		dirs:     make(map[string]bool),
+		reload:   make(chan *config.Config),
+	}
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create fs watcher: %w", err)
+	}
+	w.fsw = fsw
+
+	w.watchCollections()
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+	go w.loop(ctx)
+
+	w.log.Info("watcher started",
+		"dirs", len(w.dirs),
+		"debounce", w.cfg.Watcher.Debounce,
+		"max_wait", w.cfg.Watcher.MaxWait,
+	)
+	return nil
+}
+
+// ApplyConfig hands a reloaded config to the watch loop, which starts
+// watching added collections and drops directories no collection covers.
+func (w *Watcher) ApplyConfig(cfg *config.Config) {
+	if w.done == nil {
+		w.cfg = cfg
+		return
+	}
+	select {
+	case w.reload <- cfg:
+	case <-w.done:
+	}
+}
+
+func (w *Watcher) watchCollections() {
+	for _, col := range w.cfg.Collections {
+		if _, err := os.Stat(col.Path); err != nil {
+			w.log.Warn("collection path not watchable", "collection", col.Name, "path", col.Path, "err", err)
+			continue
+		}
+		w.addTree(col.Path)
+	}
+}
+
+func (w *Watcher) applyConfig(cfg *config.Config) {
+	w.cfg = cfg
+	for dir := range w.dirs {
+		if len(w.owners(dir)) == 0 {
+			w.fsw.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+	w.watchCollections()
+	w.log.Info("watcher reconfigured", "dirs", len(w.dirs))
+}
+
+func (w *Watcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+		<-w.done
+	}
+	if w.fsw != nil {
+		w.fsw.Close()
+	}
+}
+
+func (w *Watcher) loop(ctx context.Context) {
+	defer close(w.done)
+
+	pending := make(map[string]bool)
+	var first time.Time
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	busy := false
+	// finished receives the collections of a failed reindex, or nil.
+	finished := make(chan []string, 1)
+	flush := func() {
+		if busy || len(pending) == 0 {
+			return
+		}
+		names := make([]string, 0, len(pending))
+		for name := range pending {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		clear(pending)
+		busy = true
+		go func() {
+			w.log.Info("collection files changed", "collections", names)
+			if err := w.onChange(ctx, names); err != nil && ctx.Err() == nil {
+				w.log.Error("reindex after file change failed, retrying", "collections", names, "err", err)
+				finished <- names
+				return
+			}
+			finished <- nil
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if busy {
+				<-finished
+			}
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			names := w.handle(ev)
+			if len(names) == 0 {
+				continue
+			}
+			if len(pending) == 0 {
+				first = time.Now()
+			}
+			for _, name := range names {
+				pending[name] = true
+			}
+			delay := w.cfg.Watcher.Debounce
+			if remaining := w.cfg.Watcher.MaxWait - time.Since(first); remaining < delay {
+				delay = max(remaining, 0)
+			}
+			timer.Reset(delay)
+		case cfg := <-w.reload:
+			w.applyConfig(cfg)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.log.Warn("fs watcher error", "err", err)
+		case <-timer.C:
+			flush()
+		case failed := <-finished:
+			busy = false
+			if len(failed) == 0 {
+				flush()
+				continue
+			}
+			// Retry after a debounce so a failing reindex does not spin.
+			if len(pending) == 0 {
+				first = time.Now()
+			}
+			for _, name := range failed {
+				pending[name] = true
+			}
+			timer.Reset(w.cfg.Watcher.Debounce)
+		}
+	}
+}
+
+// handle returns the collections affected by a single event and keeps the
+// watch list in sync with directories created or removed underneath.
+func (w *Watcher) handle(ev fsnotify.Event) []string {
+	if ev.Op == fsnotify.Chmod {
+		return nil
+	}
+	name := filepath.Clean(ev.Name)
+
+	if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+		if w.dirs[name] {
+			w.forgetTree(name)
+			return w.owners(name)
+		}
+		return w.matching(name)
+	}
+
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			if isHidden(filepath.Base(name)) {
+				return nil
+			}
+			w.addTree(name)
+			return w.matchingUnder(name)
+		}
+	}
+	return w.matching(name)
+}
+
+func (w *Watcher) addTree(root string) {
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if p != root && isHidden(d.Name()) {
+			return filepath.SkipDir
+		}
+		if w.dirs[p] {
+			return nil
+		}
+		if err := w.fsw.Add(p); err != nil {
+			w.log.Warn("failed to watch directory", "path", p, "err", err)
+			return nil
+		}
+		w.dirs[p] = true
+		return nil
+	})
+}
+
+func (w *Watcher) forgetTree(root string) {
+	prefix := root + string(filepath.Separator)
+	for dir := range w.dirs {
+		if dir == root || strings.HasPrefix(dir, prefix) {
+			w.fsw.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+}
+
+// matchingUnder reports collections with at least one indexable file below a
+// freshly created directory, e.g. a folder moved into the vault.
+func (w *Watcher) matchingUnder(root string) []string {
+	seen := make(map[string]bool)
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != root && isHidden(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, name := range w.matching(p) {
+			seen[name] = true
+		}
+		return nil
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matching returns collections whose mask and exclude rules select file.
+func (w *Watcher) matching(file string) []string {
+	var names []string
+	for _, col := range w.cfg.Collections {
+		rel, ok := relativeTo(col.Path, file)
+		if !ok || hasHiddenSegment(rel) {
+			continue
+		}
+		if pathmatch.Excluded(col.Exclude, rel) || !pathmatch.MatchMask(col.Mask, rel) {
+			continue
+		}
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// owners returns collections containing dir; used when a whole directory
+// disappears and the files inside can no longer be inspected.
+func (w *Watcher) owners(dir string) []string {
+	var names []string
+	for _, col := range w.cfg.Collections {
+		if _, ok := relativeTo(col.Path, dir); !ok {
+			continue
+		}
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+func relativeTo(root, p string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Clean(root), p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func hasHiddenSegment(rel string) bool {
+	for _, seg := range strings.Split(rel, "/") {
+		if isHidden(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
@@ -0,0 +1,162 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"qmdsr/config"
+)
+
+type changeRecorder struct {
+	mu    sync.Mutex
+	calls [][]string
+	// failures is how many calls fail before the recorder succeeds.
+	failures int
+}
+
+func (r *changeRecorder) record(_ context.Context, names []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, names)
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("task already running")
+	}
+	return nil
+}
+
+func (r *changeRecorder) snapshot() [][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]string(nil), r.calls...)
+}
+
+func startTestWatcher(t *testing.T, cols []config.CollectionCfg) *changeRecorder {
+	t.Helper()
+	return startRecordingWatcher(t, cols, &changeRecorder{})
+}
+
+func startRecordingWatcher(t *testing.T, cols []config.CollectionCfg, rec *changeRecorder) *changeRecorder {
+	t.Helper()
+	cfg := &config.Config{
+		Collections: cols,
+		Watcher:     config.WatcherConfig{Enabled: true, Debounce: 100 * time.Millisecond, MaxWait: time.Second},
+	}
+	w := New(cfg, rec.record, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(w.Stop)
+	return rec
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForCalls(rec *changeRecorder, n int) [][]string {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if calls := rec.snapshot(); len(calls) >= n {
+			return calls
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return rec.snapshot()
+}
+
+func TestWatcher_DebouncesMatchingChanges(t *testing.T) {
+	notes, work := t.TempDir(), t.TempDir()
+	rec := startTestWatcher(t, []config.CollectionCfg{
+		{Name: "notes", Path: notes},
+		{Name: "work", Path: work, Exclude: []string{"drafts/"}},
+	})
+
+	for i := 0; i < 5; i++ {
+		writeFile(t, filepath.Join(notes, "a.md"), "edit")
+	}
+	writeFile(t, filepath.Join(notes, "sub", "b.md"), "new dir")
+	writeFile(t, filepath.Join(work, "drafts", "c.md"), "excluded")
+	writeFile(t, filepath.Join(work, "d.txt"), "not in mask")
+
+	waitForCalls(rec, 1)
+	time.Sleep(300 * time.Millisecond)
+	calls := rec.snapshot()
+	if len(calls) != 1 {
+		t.Fatalf("expected a single debounced callback, got %v", calls)
+	}
+	if !reflect.DeepEqual(calls[0], []string{"notes"}) {
+		t.Fatalf("expected only notes to be reindexed, got %v", calls[0])
+	}
+}
+
+func TestWatcher_RemovedFileTriggersOwningCollection(t *testing.T) {
+	notes := t.TempDir()
+	writeFile(t, filepath.Join(notes, "old.md"), "x")
+	writeFile(t, filepath.Join(notes, ".obsidian", "workspace.md"), "x")
+	rec := startTestWatcher(t, []config.CollectionCfg{{Name: "notes", Path: notes}})
+
+	writeFile(t, filepath.Join(notes, ".obsidian", "workspace.md"), "hidden")
+	if err := os.Remove(filepath.Join(notes, "old.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := waitForCalls(rec, 1)
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0], []string{"notes"}) {
+		t.Fatalf("expected notes reindex after removal, got %v", calls)
+	}
+}
+
+func TestWatcher_ApplyConfigWatchesAddedCollections(t *testing.T) {
+	notes, work := t.TempDir(), t.TempDir()
+	cfg := &config.Config{
+		Collections: []config.CollectionCfg{{Name: "notes", Path: notes}},
+		Watcher:     config.WatcherConfig{Enabled: true, Debounce: 100 * time.Millisecond, MaxWait: time.Second},
+	}
+	rec := &changeRecorder{}
+	w := New(cfg, rec.record, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(w.Stop)
+
+	next := *cfg
+	next.Collections = []config.CollectionCfg{{Name: "work", Path: work}}
+	w.ApplyConfig(&next)
+
+	writeFile(t, filepath.Join(notes, "a.md"), "dropped collection")
+	writeFile(t, filepath.Join(work, "b.md"), "added collection")
+
+	waitForCalls(rec, 1)
+	time.Sleep(300 * time.Millisecond)
+	calls := rec.snapshot()
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0], []string{"work"}) {
+		t.Fatalf("expected only the added collection to be reindexed, got %v", calls)
+	}
+}
+
+func TestWatcher_RetriesFailedReindex(t *testing.T) {
+	notes := t.TempDir()
+	rec := startRecordingWatcher(t, []config.CollectionCfg{{Name: "notes", Path: notes}}, &changeRecorder{failures: 1})
+
+	writeFile(t, filepath.Join(notes, "a.md"), "edit")
+	calls := waitForCalls(rec, 2)
+	want := [][]string{{"notes"}, {"notes"}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want the failed collections retried: %v", calls, want)
+	}
+}